@@ -0,0 +1,54 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+// DetectorLoader reads the config and creates the detector without the rules.
+type DetectorLoader func(cliCtx *cli.Context) (*Detector, error)
+
+var testTimeoutFlag = cli.DurationFlag{
+	Name:  "timeout",
+	Usage: "the timeout to send the test notifications to all the webhooks",
+	Value: time.Minute,
+}
+
+// NewCommand returns the `alerts` command for the binary.
+func NewCommand(load DetectorLoader) cli.Command {
+	return cli.Command{
+		Name:  "alerts",
+		Usage: "subcommand for the stall detector alerts",
+		Subcommands: []cli.Command{
+			{
+				Name:  "test",
+				Usage: "send a firing and a resolved test notification to each webhook in the config",
+				Flags: []cli.Flag{testTimeoutFlag},
+				Action: func(cliCtx *cli.Context) error {
+					detector, err := load(cliCtx)
+					if err != nil {
+						return err
+					}
+
+					if len(detector.webhooks) == 0 {
+						return errors.New("no webhook in `alerting.webhooks` of the config")
+					}
+
+					ctx, cancel := context.WithTimeout(context.Background(), cliCtx.Duration(testTimeoutFlag.Name))
+					defer cancel()
+
+					if err := detector.Test(ctx); err != nil {
+						return err
+					}
+
+					fmt.Printf("the test notifications are sent to %d webhooks\n", len(detector.webhooks))
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,154 @@
+package alerting
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
+)
+
+// The formats of the webhook body.
+const (
+	// FormatGeneric posts the Notification as json.
+	FormatGeneric = "generic"
+	// FormatSlack posts a message for the Slack incoming webhooks, also accepted by Mattermost and Discord `/slack`.
+	FormatSlack = "slack"
+)
+
+const (
+	defaultInterval         = 30 * time.Second
+	defaultRenotifyInterval = 4 * time.Hour
+	defaultMaxProbeFailures = 3
+
+	defaultWebhookTimeout       = 10 * time.Second
+	defaultWebhookMaxRetries    = 3
+	defaultWebhookRetryInterval = 2 * time.Second
+)
+
+type Config struct {
+	// The interval to check the rules, default is 30s.
+	Interval time.Duration `yaml:"interval,omitempty"`
+	// The interval to notify a firing incident again, default is 4h, negative to never notify again.
+	RenotifyInterval time.Duration `yaml:"renotify_interval,omitempty"`
+	// Raise `<alert>_probe_failing` if the check of a rule failed more than these times in a row,
+	// default is 3, negative to disable.
+	MaxProbeFailures int `yaml:"max_probe_failures,omitempty"`
+	// The webhooks to notify the incidents raised and resolved.
+	Webhooks []WebhookConfig `yaml:"webhooks,omitempty"`
+	// The thresholds of the rules, a rule is disabled if its threshold is not set.
+	Rules RulesConfig `yaml:"rules,omitempty"`
+}
+
+// RulesConfig is the thresholds of the rules. The rpc services check the finality rules,
+// and the operator checks the rules of its finality providers.
+type RulesConfig struct {
+	// Raise `finality_lag` if the l2 head is more than these blocks ahead of the babylon finalized head.
+	MaxFinalityLagBlocks uint64 `yaml:"max_finality_lag_blocks,omitempty"`
+	// Raise `fp_participation` for each finality provider with voting power which voted less than the ratio
+	// of the recent blocks checked, from 0 to 1.
+	MinParticipationRate float64 `yaml:"min_participation_rate,omitempty"`
+	// Raise `babylon_block_production` if the latest babylon block is older than it.
+	MaxBabylonBlockAge time.Duration `yaml:"max_babylon_block_age,omitempty"`
+	// Raise `pub_rand_runway` for each finality provider if the committed public randomness
+	// can vote less than these blocks from the last voted height.
+	MinPubRandRunwayBlocks uint64 `yaml:"min_pub_rand_runway_blocks,omitempty"`
+	// Raise `balance_days_left` for each monitored account if its balance can only pay less than these days
+	// by the spend observed. The balance thresholds are set for each account in the `balance` config.
+	MinBalanceDaysLeft float64 `yaml:"min_balance_days_left,omitempty"`
+}
+
+type WebhookConfig struct {
+	// The name of the webhook, should be unique
+	Name string `yaml:"name"`
+	// The format of the body, `generic` or `slack`, default is `generic`
+	Format string `yaml:"format,omitempty"`
+	// The url to POST, the Slack webhook urls contain the token
+	Url string `yaml:"url" secret:"true"`
+	// The extra headers for each request
+	Headers map[string]string `yaml:"headers,omitempty" secret:"true"`
+	// The timeout for each request
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// The max retries count for a notification, default is 3 if unset, 0 to disable the retries
+	MaxRetries *int `yaml:"max_retries,omitempty"`
+	// The interval between the retries, will be doubled by each retry
+	RetryInterval time.Duration `yaml:"retry_interval,omitempty"`
+}
+
+// IsEmpty returns true if no rule is set.
+func (c *RulesConfig) IsEmpty() bool {
+	return *c == RulesConfig{}
+}
+
+func (c *Config) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.Interval < 0 {
+		errs.Addf("interval", "should not be negative")
+	}
+
+	names := make(map[string]struct{}, len(c.Webhooks))
+	for i, w := range c.Webhooks {
+		field := fmt.Sprintf("webhooks[%d]", i)
+		if w.Name == "" {
+			errs.Addf(field+".name", "required")
+		} else if _, ok := names[w.Name]; ok {
+			errs.Addf(field+".name", "the webhook name %s is duplicated", w.Name)
+		}
+		names[w.Name] = struct{}{}
+
+		errs.Merge(field, w.Validate())
+	}
+
+	errs.Merge("rules", c.Rules.Validate())
+
+	return errs.Err()
+}
+
+func (c *RulesConfig) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.MinParticipationRate < 0 || c.MinParticipationRate > 1 {
+		errs.Addf("min_participation_rate", "should be in [0, 1], got %v", c.MinParticipationRate)
+	}
+
+	if c.MaxBabylonBlockAge < 0 {
+		errs.Addf("max_babylon_block_age", "should not be negative")
+	}
+
+	if c.MinBalanceDaysLeft < 0 {
+		errs.Addf("min_balance_days_left", "should not be negative")
+	}
+
+	return errs.Err()
+}
+
+func (c *WebhookConfig) Validate() error {
+	var errs utils.ConfigErrors
+
+	switch c.Format {
+	case "", FormatGeneric, FormatSlack:
+	default:
+		errs.Addf("format", "unknown format %s, should be `%s` or `%s`", c.Format, FormatGeneric, FormatSlack)
+	}
+
+	if c.Url == "" {
+		errs.Addf("url", "required")
+	} else if err := utils.CheckURL(c.Url, "http", "https"); err != nil {
+		// the error contains the url, which may contain the token
+		errs.Addf("url", "invalid url, should be like `https://host/path`")
+	}
+
+	if c.Timeout < 0 {
+		errs.Addf("timeout", "should not be negative")
+	}
+
+	if c.MaxRetries != nil && *c.MaxRetries < 0 {
+		errs.Addf("max_retries", "should not be negative")
+	}
+
+	if c.RetryInterval < 0 {
+		errs.Addf("retry_interval", "should not be negative")
+	}
+
+	return errs.Err()
+}
@@ -0,0 +1,511 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/alt-research/blitz/finality-gadget/metrics"
+)
+
+// The severities of the rules.
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// probeFailingSuffix is the suffix of the alert raised when the check of a rule keeps failing.
+const probeFailingSuffix = "_probe_failing"
+
+// maxResolving is the max resolved incidents kept to retry the notifications,
+// the oldest ones are dropped if the webhooks keep failing.
+const maxResolving = 100
+
+// Sample is a value of the signal, the subject is like the fp btc pk if the signal has a value for each,
+// or empty if the signal has only one value.
+type Sample struct {
+	Subject string
+	Value   float64
+	// Threshold overrides the threshold of the rule for the subject if not zero.
+	Threshold float64
+}
+
+// Probe returns the current values of the signal.
+type Probe func(ctx context.Context) ([]Sample, error)
+
+// Rule raises an incident for each subject which value crossed the threshold,
+// and resolves it when the value is back.
+type Rule struct {
+	// The alert name, like `finality_lag`.
+	Alert    string
+	Severity string
+	// Summary describes the incident of the sample.
+	Summary   func(s Sample) string
+	Threshold float64
+	// Below raises the incident if the value is below the threshold, or above if false.
+	Below bool
+	Probe Probe
+}
+
+// thresholdOf returns the threshold for the sample.
+func (r *Rule) thresholdOf(s Sample) float64 {
+	if s.Threshold != 0 {
+		return s.Threshold
+	}
+	return r.Threshold
+}
+
+func (r *Rule) breached(s Sample) bool {
+	threshold := r.thresholdOf(s)
+	if r.Below {
+		return s.Value < threshold
+	}
+	return s.Value > threshold
+}
+
+// Incident is a rule breached by a subject.
+type Incident struct {
+	Alert     string    `json:"alert"`
+	Subject   string    `json:"subject,omitempty"`
+	Severity  string    `json:"severity"`
+	Summary   string    `json:"summary"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at,omitzero"`
+
+	// the last time notified to each webhook, to re-notify and retry the failed ones
+	notifiedAt map[string]time.Time
+}
+
+// Fingerprint is the key to deduplicate the incidents.
+func (i *Incident) Fingerprint() string {
+	return i.Alert + "/" + i.Subject
+}
+
+// Detector checks the rules by the interval, and notifies the webhooks when the incidents raised and resolved.
+// The incident of a subject is only notified once until resolved, and notified again by the renotify interval.
+type Detector struct {
+	logger   *zap.Logger
+	cfg      Config
+	labels   map[string]string
+	webhooks []*webhook
+	metrics  *metrics.AlertingMetrics
+
+	rules     []Rule
+	incidents map[string]*Incident
+	// the resolved incidents not acknowledged by all the webhooks notified the firing
+	resolving []*Incident
+	// the consecutive failed checks of each rule
+	probeFailures map[string]int
+	mu            sync.Mutex
+	// checkMu serializes the checks, the mu is not held when notifying so the incidents can be read
+	checkMu sync.Mutex
+	// reloaded sends the new interval after the config reloaded
+	reloaded chan time.Duration
+}
+
+// NewDetector creates the detector by the config, the labels are attached to all the notifications.
+func NewDetector(logger *zap.Logger, cfg *Config, labels map[string]string) *Detector {
+	d := &Detector{
+		logger:        logger.With(zap.String("module", "alerting")),
+		labels:        labels,
+		metrics:       metrics.NewAlertingMetrics(),
+		incidents:     make(map[string]*Incident),
+		probeFailures: make(map[string]int),
+		reloaded:      make(chan time.Duration, 1),
+	}
+	d.applyConfig(cfg)
+
+	return d
+}
+
+func (d *Detector) applyConfig(cfg *Config) {
+	d.cfg = *cfg
+
+	if d.cfg.Interval == 0 {
+		d.cfg.Interval = defaultInterval
+	}
+
+	if d.cfg.RenotifyInterval == 0 {
+		d.cfg.RenotifyInterval = defaultRenotifyInterval
+	}
+
+	if d.cfg.MaxProbeFailures == 0 {
+		d.cfg.MaxProbeFailures = defaultMaxProbeFailures
+	}
+
+	d.webhooks = make([]*webhook, 0, len(cfg.Webhooks))
+	for _, w := range cfg.Webhooks {
+		d.webhooks = append(d.webhooks, newWebhook(w))
+	}
+}
+
+// Reload applies the reloaded config, the rules are removed and added again by addRules.
+// The incidents of the rules still added are kept, the others are resolved.
+func (d *Detector) Reload(ctx context.Context, cfg *Config, addRules func(d *Detector)) {
+	d.checkMu.Lock()
+	defer d.checkMu.Unlock()
+
+	d.mu.Lock()
+	d.applyConfig(cfg)
+	oldRules := d.rules
+	d.rules = nil
+	d.mu.Unlock()
+
+	addRules(d)
+
+	d.mu.Lock()
+	alerts := make(map[string]struct{}, len(d.rules))
+	for _, rule := range d.rules {
+		alerts[rule.Alert] = struct{}{}
+		if d.cfg.MaxProbeFailures >= 0 {
+			alerts[rule.Alert+probeFailingSuffix] = struct{}{}
+		}
+	}
+
+	for _, rule := range oldRules {
+		if _, ok := alerts[rule.Alert]; !ok {
+			d.metrics.SetIncidentsFiring(rule.Alert, 0)
+			delete(d.probeFailures, rule.Alert)
+		}
+		if _, ok := alerts[rule.Alert+probeFailingSuffix]; !ok {
+			d.metrics.SetIncidentsFiring(rule.Alert+probeFailingSuffix, 0)
+		}
+	}
+
+	now := time.Now()
+	var resolved []*Incident
+	for key, incident := range d.incidents {
+		if _, ok := alerts[incident.Alert]; ok {
+			continue
+		}
+
+		incident.EndsAt = now
+		delete(d.incidents, key)
+		resolved = append(resolved, incident)
+		d.logger.Info("incident resolved as the rule removed",
+			zap.String("alert", incident.Alert), zap.String("subject", incident.Subject))
+	}
+	d.mu.Unlock()
+
+	for _, incident := range resolved {
+		d.resolve(ctx, incident, now)
+	}
+
+	// drop the interval not applied yet
+	select {
+	case <-d.reloaded:
+	default:
+	}
+	d.reloaded <- d.cfg.Interval
+
+	d.logger.Info("alerting config reloaded",
+		zap.Int("rules", len(d.rules)), zap.Int("webhooks", len(d.webhooks)), zap.Duration("interval", d.cfg.Interval))
+}
+
+// AddRule adds the rule, the rules are checked by the order added.
+func (d *Detector) AddRule(rule Rule) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.rules = append(d.rules, rule)
+	d.metrics.SetIncidentsFiring(rule.Alert, 0)
+}
+
+// Start checks the rules in a goroutine until the ctx is done.
+func (d *Detector) Start(ctx context.Context) {
+	metrics.RegisterStatusProvider("incidents", func() any {
+		return d.Incidents()
+	})
+
+	go func() {
+		d.logger.Info("Stall detector is starting",
+			zap.Int("rules", len(d.rules)), zap.Int("webhooks", len(d.webhooks)), zap.Duration("interval", d.cfg.Interval))
+
+		ticker := time.NewTicker(d.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			d.Check(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case interval := <-d.reloaded:
+				ticker.Reset(interval)
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Check checks all the rules once, and notifies the changes.
+func (d *Detector) Check(ctx context.Context) {
+	d.checkMu.Lock()
+	defer d.checkMu.Unlock()
+
+	d.mu.Lock()
+	rules := d.rules
+	resolving := d.resolving
+	d.resolving = nil
+	d.mu.Unlock()
+
+	// retry the resolved notifications failed
+	for _, incident := range resolving {
+		d.resolve(ctx, incident, time.Now())
+	}
+
+	for i := range rules {
+		d.checkRule(ctx, &rules[i])
+	}
+}
+
+func (d *Detector) checkRule(ctx context.Context, rule *Rule) {
+	// the probe is limited by the interval, the notifications have their own timeouts
+	probeCtx, cancel := context.WithTimeout(ctx, d.cfg.Interval)
+	samples, err := rule.Probe(probeCtx)
+	cancel()
+
+	now := time.Now()
+	d.checkProbeFailures(ctx, rule, err, now)
+
+	if err != nil {
+		// keep the incidents unchanged, the upstream errors are alerted by the metrics
+		d.logger.Warn("check rule failed", zap.String("alert", rule.Alert), zap.Error(err))
+		d.metrics.RecordProbeError(rule.Alert)
+		return
+	}
+
+	firing, resolved := d.update(rule, samples, now)
+
+	for _, incident := range firing {
+		d.notify(ctx, incident, StatusFiring, now)
+	}
+
+	for _, incident := range resolved {
+		d.resolve(ctx, incident, now)
+	}
+}
+
+// checkProbeFailures raises `<alert>_probe_failing` if the check of the rule failed more than
+// MaxProbeFailures times in a row, and resolves it once the check succeeded.
+func (d *Detector) checkProbeFailures(ctx context.Context, rule *Rule, err error, now time.Time) {
+	if d.cfg.MaxProbeFailures < 0 {
+		return
+	}
+
+	d.mu.Lock()
+	if err != nil {
+		d.probeFailures[rule.Alert]++
+	} else {
+		delete(d.probeFailures, rule.Alert)
+	}
+	failures := d.probeFailures[rule.Alert]
+	d.mu.Unlock()
+
+	probeRule := &Rule{
+		Alert:     rule.Alert + probeFailingSuffix,
+		Severity:  rule.Severity,
+		Threshold: float64(d.cfg.MaxProbeFailures),
+		Summary: func(s Sample) string {
+			return fmt.Sprintf("the check of %s failed %v times in a row, see the logs for the errors", rule.Alert, s.Value)
+		},
+	}
+
+	firing, resolved := d.update(probeRule, []Sample{{Value: float64(failures)}}, now)
+
+	for _, incident := range firing {
+		d.notify(ctx, incident, StatusFiring, now)
+	}
+
+	for _, incident := range resolved {
+		d.resolve(ctx, incident, now)
+	}
+}
+
+// update updates the incidents of the rule by the samples, returns the firing and the resolved ones.
+func (d *Detector) update(rule *Rule, samples []Sample, now time.Time) ([]*Incident, []*Incident) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var firing, resolved []*Incident
+	breached := make(map[string]struct{}, len(samples))
+	for _, s := range samples {
+		if !rule.breached(s) {
+			continue
+		}
+
+		key := rule.Alert + "/" + s.Subject
+		breached[key] = struct{}{}
+
+		incident, ok := d.incidents[key]
+		if !ok {
+			incident = &Incident{
+				Alert:      rule.Alert,
+				Subject:    s.Subject,
+				Severity:   rule.Severity,
+				Threshold:  rule.thresholdOf(s),
+				StartsAt:   now,
+				notifiedAt: make(map[string]time.Time, len(d.webhooks)),
+			}
+			d.incidents[key] = incident
+			d.logger.Warn("incident raised",
+				zap.String("alert", rule.Alert), zap.String("subject", s.Subject), zap.Float64("value", s.Value))
+		}
+
+		incident.Value = s.Value
+		incident.Summary = rule.Summary(s)
+		firing = append(firing, incident)
+	}
+
+	// the subjects not breached or not sampled any more are resolved
+	for key, incident := range d.incidents {
+		if incident.Alert != rule.Alert {
+			continue
+		}
+
+		if _, ok := breached[key]; ok {
+			continue
+		}
+
+		incident.EndsAt = now
+		delete(d.incidents, key)
+		resolved = append(resolved, incident)
+		d.logger.Info("incident resolved",
+			zap.String("alert", rule.Alert), zap.String("subject", incident.Subject), zap.Duration("duration", now.Sub(incident.StartsAt)))
+	}
+
+	d.metrics.SetIncidentsFiring(rule.Alert, len(firing))
+
+	return firing, resolved
+}
+
+// resolve notifies the resolved incident, it is kept to retry by the next check
+// until all the webhooks notified the firing acknowledged the resolved.
+func (d *Detector) resolve(ctx context.Context, incident *Incident, now time.Time) {
+	// the webhooks removed by the reload are not notified
+	names := make(map[string]struct{}, len(d.webhooks))
+	for _, w := range d.webhooks {
+		names[w.cfg.Name] = struct{}{}
+	}
+	for name := range incident.notifiedAt {
+		if _, ok := names[name]; !ok {
+			delete(incident.notifiedAt, name)
+		}
+	}
+
+	d.notify(ctx, incident, StatusResolved, now)
+	if len(incident.notifiedAt) == 0 {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.resolving = append(d.resolving, incident)
+	if len(d.resolving) > maxResolving {
+		dropped := d.resolving[0]
+		d.resolving = d.resolving[1:]
+		d.logger.Warn("drop the resolved incident not notified",
+			zap.String("alert", dropped.Alert), zap.String("subject", dropped.Subject))
+	}
+}
+
+// notify sends the notification to the webhooks, the firing ones are skipped if notified in the renotify interval,
+// the resolved ones are only sent to the webhooks which were notified the firing and not the resolved yet.
+func (d *Detector) notify(ctx context.Context, incident *Incident, status string, now time.Time) {
+	for _, w := range d.webhooks {
+		last, notified := incident.notifiedAt[w.cfg.Name]
+		if status == StatusFiring && notified {
+			if d.cfg.RenotifyInterval < 0 || now.Sub(last) < d.cfg.RenotifyInterval {
+				continue
+			}
+		}
+		if status == StatusResolved && !notified {
+			// never notified as firing, so no need to resolve
+			continue
+		}
+
+		d.mu.Lock()
+		n := &Notification{
+			Status:   status,
+			Repeat:   status == StatusFiring && notified,
+			Incident: *incident,
+			Labels:   d.labels,
+		}
+		d.mu.Unlock()
+
+		err := w.send(ctx, n)
+		d.metrics.RecordNotification(w.cfg.Name, err)
+		if err != nil {
+			// it will be retried by the next check
+			d.logger.Error("notify webhook failed",
+				zap.String("webhook", w.cfg.Name), zap.String("alert", incident.Alert), zap.String("status", status), zap.Error(err))
+			continue
+		}
+
+		if status == StatusResolved {
+			delete(incident.notifiedAt, w.cfg.Name)
+			continue
+		}
+		incident.notifiedAt[w.cfg.Name] = now
+	}
+}
+
+// Incidents returns the firing incidents, sorted by the start time.
+func (d *Detector) Incidents() []Incident {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	res := make([]Incident, 0, len(d.incidents))
+	for _, incident := range d.incidents {
+		res = append(res, *incident)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].StartsAt.Equal(res[j].StartsAt) {
+			return res[i].Fingerprint() < res[j].Fingerprint()
+		}
+		return res[i].StartsAt.Before(res[j].StartsAt)
+	})
+
+	return res
+}
+
+// Test sends a firing and a resolved test notification to all the webhooks, to check the receivers.
+func (d *Detector) Test(ctx context.Context) error {
+	incident := Incident{
+		Alert:    "test",
+		Severity: SeverityWarning,
+		Summary:  "a test notification from the stall detector, please ignore",
+		StartsAt: time.Now(),
+	}
+
+	var failed int
+	for _, w := range d.webhooks {
+		for _, status := range []string{StatusFiring, StatusResolved} {
+			if status == StatusResolved {
+				incident.EndsAt = time.Now()
+			}
+
+			err := w.send(ctx, &Notification{Status: status, Incident: incident, Labels: d.labels})
+			if err != nil {
+				failed++
+				d.logger.Error("test webhook failed", zap.String("webhook", w.cfg.Name), zap.String("status", status), zap.Error(err))
+				break
+			}
+			d.logger.Info("test webhook sent", zap.String("webhook", w.cfg.Name), zap.String("status", status))
+		}
+	}
+
+	if failed != 0 {
+		return fmt.Errorf("%d of %d webhooks failed", failed, len(d.webhooks))
+	}
+
+	return nil
+}
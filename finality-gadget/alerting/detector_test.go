@@ -0,0 +1,222 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// receiver records the generic notifications, the first `failResolved` resolved ones are failed.
+type receiver struct {
+	t            *testing.T
+	failResolved atomic.Int32
+
+	mu            sync.Mutex
+	notifications []Notification
+}
+
+func newReceiver(t *testing.T) (*receiver, *httptest.Server) {
+	r := &receiver{t: t}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var n Notification
+		if err := json.NewDecoder(req.Body).Decode(&n); err != nil {
+			t.Errorf("decode body: %v", err)
+			return
+		}
+
+		if n.Status == StatusResolved && r.failResolved.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.notifications = append(r.notifications, n)
+	}))
+	t.Cleanup(srv.Close)
+
+	return r, srv
+}
+
+// take returns the notifications received since the last take.
+func (r *receiver) take() []Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := r.notifications
+	r.notifications = nil
+	return res
+}
+
+func (r *receiver) expect(status string, repeat bool) Notification {
+	r.t.Helper()
+
+	got := r.take()
+	if len(got) != 1 {
+		r.t.Fatalf("got %d notifications %+v, want one %s", len(got), got, status)
+	}
+
+	if got[0].Status != status || got[0].Repeat != repeat {
+		r.t.Fatalf("got %s repeat %v, want %s repeat %v", got[0].Status, got[0].Repeat, status, repeat)
+	}
+
+	return got[0]
+}
+
+func (r *receiver) expectNone() {
+	r.t.Helper()
+
+	if got := r.take(); len(got) != 0 {
+		r.t.Fatalf("got unexpected notifications %+v", got)
+	}
+}
+
+func newTestDetector(t *testing.T, url string, cfg Config) *Detector {
+	cfg.Webhooks = []WebhookConfig{{Name: "hook", Url: url, MaxRetries: intPtr(0)}}
+	return NewDetector(zaptest.NewLogger(t), &cfg, map[string]string{"service": "test"})
+}
+
+func TestDetectorNotifications(t *testing.T) {
+	r, srv := newReceiver(t)
+	d := newTestDetector(t, srv.URL, Config{RenotifyInterval: 50 * time.Millisecond})
+
+	var lag atomic.Int64
+	d.AddRule(Rule{
+		Alert:     AlertFinalityLag,
+		Severity:  SeverityCritical,
+		Threshold: 100,
+		Summary:   func(s Sample) string { return "lagging" },
+		Probe: func(ctx context.Context) ([]Sample, error) {
+			return []Sample{{Value: float64(lag.Load())}}, nil
+		},
+	})
+
+	ctx := context.Background()
+
+	d.Check(ctx)
+	r.expectNone()
+
+	lag.Store(200)
+	d.Check(ctx)
+	n := r.expect(StatusFiring, false)
+	if n.Alert != AlertFinalityLag || n.Value != 200 || n.Threshold != 100 || n.Labels["service"] != "test" {
+		t.Fatalf("unexpected firing notification %+v", n)
+	}
+	if len(d.Incidents()) != 1 {
+		t.Fatalf("got incidents %+v", d.Incidents())
+	}
+
+	// deduplicated in the renotify interval
+	d.Check(ctx)
+	r.expectNone()
+
+	time.Sleep(60 * time.Millisecond)
+	d.Check(ctx)
+	r.expect(StatusFiring, true)
+
+	lag.Store(10)
+	d.Check(ctx)
+	n = r.expect(StatusResolved, false)
+	if n.EndsAt.IsZero() {
+		t.Fatalf("resolved without ends_at %+v", n)
+	}
+	if len(d.Incidents()) != 0 {
+		t.Fatalf("got incidents %+v", d.Incidents())
+	}
+
+	d.Check(ctx)
+	r.expectNone()
+}
+
+func TestDetectorRetryResolved(t *testing.T) {
+	r, srv := newReceiver(t)
+	d := newTestDetector(t, srv.URL, Config{})
+
+	var breached atomic.Bool
+	breached.Store(true)
+	d.AddRule(Rule{
+		Alert:     AlertBalanceLow,
+		Severity:  SeverityWarning,
+		Threshold: 1,
+		Below:     true,
+		Summary:   func(s Sample) string { return "low" },
+		Probe: func(ctx context.Context) ([]Sample, error) {
+			if breached.Load() {
+				return []Sample{{Subject: "submitter", Value: 0.5}}, nil
+			}
+			return nil, nil
+		},
+	})
+
+	ctx := context.Background()
+
+	d.Check(ctx)
+	r.expect(StatusFiring, false)
+
+	// the resolved is kept until acknowledged
+	r.failResolved.Store(2)
+	breached.Store(false)
+	d.Check(ctx)
+	r.expectNone()
+	d.Check(ctx)
+	r.expectNone()
+
+	d.Check(ctx)
+	n := r.expect(StatusResolved, false)
+	if n.Subject != "submitter" {
+		t.Fatalf("unexpected resolved notification %+v", n)
+	}
+
+	d.Check(ctx)
+	r.expectNone()
+}
+
+func TestDetectorProbeFailing(t *testing.T) {
+	r, srv := newReceiver(t)
+	d := newTestDetector(t, srv.URL, Config{MaxProbeFailures: 2})
+
+	var failing atomic.Bool
+	failing.Store(true)
+	d.AddRule(Rule{
+		Alert:     AlertBabylonBlockProduction,
+		Severity:  SeverityCritical,
+		Threshold: 120,
+		Summary:   func(s Sample) string { return "stalled" },
+		Probe: func(ctx context.Context) ([]Sample, error) {
+			if failing.Load() {
+				return nil, errors.New("babylon unavailable")
+			}
+			return []Sample{{Value: 1}}, nil
+		},
+	})
+
+	ctx := context.Background()
+
+	d.Check(ctx)
+	d.Check(ctx)
+	r.expectNone()
+
+	d.Check(ctx)
+	n := r.expect(StatusFiring, false)
+	if n.Alert != AlertBabylonBlockProduction+probeFailingSuffix || n.Severity != SeverityCritical || n.Value != 3 {
+		t.Fatalf("unexpected probe failing notification %+v", n)
+	}
+
+	d.Check(ctx)
+	r.expectNone()
+
+	failing.Store(false)
+	d.Check(ctx)
+	n = r.expect(StatusResolved, false)
+	if n.Alert != AlertBabylonBlockProduction+probeFailingSuffix {
+		t.Fatalf("unexpected resolved notification %+v", n)
+	}
+}
@@ -0,0 +1,200 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/alt-research/blitz/finality-gadget/metrics"
+)
+
+// The alert names of the rules.
+const (
+	AlertFinalityLag            = "finality_lag"
+	AlertFpParticipation        = "fp_participation"
+	AlertBabylonBlockProduction = "babylon_block_production"
+	AlertPubRandRunway          = "pub_rand_runway"
+	AlertBalanceLow             = "balance_low"
+	AlertBalanceCritical        = "balance_critical"
+	AlertBalanceDaysLeft        = "balance_days_left"
+)
+
+// FinalitySource is the finality state checked by the finality rules, implemented by the rpc provider.
+type FinalitySource interface {
+	// FinalizedHead returns the l2 head and the babylon finalized height.
+	FinalizedHead(ctx context.Context) (uint64, uint64, error)
+	// ParticipationRates returns the participation rate of each fp on the recent blocks.
+	ParticipationRates() map[string]float64
+	// BabylonLatestBlockTime returns the time of the latest babylon block.
+	BabylonLatestBlockTime(ctx context.Context) (time.Time, error)
+}
+
+// Labels returns the labels attached to all the notifications of the service.
+func Labels(service string, chainId uint64) map[string]string {
+	labels := map[string]string{"service": service}
+	if chainId != 0 {
+		labels["chain_id"] = strconv.FormatUint(chainId, 10)
+	}
+	return labels
+}
+
+// AddFinalityRules adds the rules of the finality state, the rules without threshold are skipped.
+func (d *Detector) AddFinalityRules(rules *RulesConfig, source FinalitySource) {
+	if rules.MaxFinalityLagBlocks != 0 {
+		d.AddRule(Rule{
+			Alert:     AlertFinalityLag,
+			Severity:  SeverityCritical,
+			Threshold: float64(rules.MaxFinalityLagBlocks),
+			Summary: func(s Sample) string {
+				return fmt.Sprintf("the babylon finalized head is %v blocks behind the l2 head", s.Value)
+			},
+			Probe: func(ctx context.Context) ([]Sample, error) {
+				head, finalized, err := source.FinalizedHead(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				var lag uint64
+				if head > finalized {
+					lag = head - finalized
+				}
+
+				return []Sample{{Value: float64(lag)}}, nil
+			},
+		})
+	}
+
+	if rules.MinParticipationRate != 0 {
+		d.AddRule(Rule{
+			Alert:     AlertFpParticipation,
+			Severity:  SeverityWarning,
+			Threshold: rules.MinParticipationRate,
+			Below:     true,
+			Summary: func(s Sample) string {
+				return fmt.Sprintf("the finality provider %s voted %.1f%% of the recent blocks", s.Subject, s.Value*100)
+			},
+			Probe: func(ctx context.Context) ([]Sample, error) {
+				return mapSamples(source.ParticipationRates(), func(v float64) float64 { return v }), nil
+			},
+		})
+	}
+
+	if rules.MaxBabylonBlockAge != 0 {
+		d.AddRule(Rule{
+			Alert:     AlertBabylonBlockProduction,
+			Severity:  SeverityCritical,
+			Threshold: rules.MaxBabylonBlockAge.Seconds(),
+			Summary: func(s Sample) string {
+				return fmt.Sprintf("no new babylon block in %s", time.Duration(s.Value*float64(time.Second)).Round(time.Second))
+			},
+			Probe: func(ctx context.Context) ([]Sample, error) {
+				latest, err := source.BabylonLatestBlockTime(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				return []Sample{{Value: time.Since(latest).Seconds()}}, nil
+			},
+		})
+	}
+}
+
+// AddOperatorRules adds the rules of the finality providers run by the operator,
+// the rules are checked by the metrics recorded by the operator, the rules without threshold are skipped.
+func (d *Detector) AddOperatorRules(rules *RulesConfig) {
+	if rules.MinPubRandRunwayBlocks != 0 {
+		voteMetrics := metrics.NewVoteMetrics()
+		d.AddRule(Rule{
+			Alert:     AlertPubRandRunway,
+			Severity:  SeverityCritical,
+			Threshold: float64(rules.MinPubRandRunwayBlocks),
+			Below:     true,
+			Summary: func(s Sample) string {
+				return fmt.Sprintf("the committed public randomness of %s can only vote %v more blocks", s.Subject, s.Value)
+			},
+			Probe: func(ctx context.Context) ([]Sample, error) {
+				return mapSamples(voteMetrics.PubRandRunway(), func(v uint64) float64 { return float64(v) }), nil
+			},
+		})
+	}
+
+	// the thresholds of the balances are set for each account
+	fpMetrics := metrics.NewFpMetrics()
+	balanceSummary := func(s Sample) string {
+		for _, b := range fpMetrics.AccountBalances() {
+			if b.Name != s.Subject {
+				continue
+			}
+
+			summary := fmt.Sprintf("the balance of the %s account %s is %v", b.Name, b.Address, b.Balance)
+			if b.DaysLeft != nil {
+				summary += fmt.Sprintf(", about %.1f days left", *b.DaysLeft)
+			}
+			return summary
+		}
+
+		return fmt.Sprintf("the balance of the %s account is %v", s.Subject, s.Value)
+	}
+
+	d.AddRule(Rule{
+		Alert:    AlertBalanceLow,
+		Severity: SeverityWarning,
+		Below:    true,
+		Summary:  balanceSummary,
+		Probe: func(ctx context.Context) ([]Sample, error) {
+			return balanceSamples(fpMetrics.AccountBalances(), func(b *metrics.AccountBalance) float64 { return b.WarningThreshold }), nil
+		},
+	})
+
+	d.AddRule(Rule{
+		Alert:    AlertBalanceCritical,
+		Severity: SeverityCritical,
+		Below:    true,
+		Summary:  balanceSummary,
+		Probe: func(ctx context.Context) ([]Sample, error) {
+			return balanceSamples(fpMetrics.AccountBalances(), func(b *metrics.AccountBalance) float64 { return b.CriticalThreshold }), nil
+		},
+	})
+
+	if rules.MinBalanceDaysLeft != 0 {
+		d.AddRule(Rule{
+			Alert:     AlertBalanceDaysLeft,
+			Severity:  SeverityWarning,
+			Threshold: rules.MinBalanceDaysLeft,
+			Below:     true,
+			Summary: func(s Sample) string {
+				return fmt.Sprintf("the balance of the %s account can only pay about %.1f days by the spend observed", s.Subject, s.Value)
+			},
+			Probe: func(ctx context.Context) ([]Sample, error) {
+				var samples []Sample
+				for _, b := range fpMetrics.AccountBalances() {
+					// unknown if not observed long enough or nothing spent
+					if b.DaysLeft != nil {
+						samples = append(samples, Sample{Subject: b.Name, Value: *b.DaysLeft})
+					}
+				}
+				return samples, nil
+			},
+		})
+	}
+}
+
+// balanceSamples returns the balances of the accounts with the threshold set.
+func balanceSamples(balances []metrics.AccountBalance, threshold func(b *metrics.AccountBalance) float64) []Sample {
+	var samples []Sample
+	for i := range balances {
+		if t := threshold(&balances[i]); t != 0 {
+			samples = append(samples, Sample{Subject: balances[i].Name, Value: balances[i].Balance, Threshold: t})
+		}
+	}
+	return samples
+}
+
+func mapSamples[T any](values map[string]T, value func(v T) float64) []Sample {
+	samples := make([]Sample, 0, len(values))
+	for subject, v := range values {
+		samples = append(samples, Sample{Subject: subject, Value: value(v)})
+	}
+	return samples
+}
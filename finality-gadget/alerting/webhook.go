@@ -0,0 +1,205 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The status of the notifications.
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// Notification is the body of the generic webhooks.
+type Notification struct {
+	// `firing` or `resolved`
+	Status string `json:"status"`
+	// Repeat is true if the incident was notified before and still firing.
+	Repeat bool `json:"repeat,omitempty"`
+	Incident
+	// The labels of the process, like the service and the chain id.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type webhook struct {
+	cfg        WebhookConfig
+	maxRetries int
+	client     *http.Client
+}
+
+func newWebhook(cfg WebhookConfig) *webhook {
+	if cfg.Format == "" {
+		cfg.Format = FormatGeneric
+	}
+
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultWebhookTimeout
+	}
+
+	maxRetries := defaultWebhookMaxRetries
+	if cfg.MaxRetries != nil {
+		maxRetries = *cfg.MaxRetries
+	}
+
+	if cfg.RetryInterval == 0 {
+		cfg.RetryInterval = defaultWebhookRetryInterval
+	}
+
+	return &webhook{
+		cfg:        cfg,
+		maxRetries: maxRetries,
+		client:     &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// sendTimeout is the max time of a notification with all the retries.
+func (w *webhook) sendTimeout() time.Duration {
+	timeout := w.cfg.Timeout * time.Duration(w.maxRetries+1)
+	interval := w.cfg.RetryInterval
+	for i := 0; i < w.maxRetries; i++ {
+		timeout += interval
+		interval *= 2
+	}
+
+	return timeout
+}
+
+// send posts the notification with the retries, each notification has its own timeout.
+func (w *webhook) send(ctx context.Context, n *Notification) error {
+	ctx, cancel := context.WithTimeout(ctx, w.sendTimeout())
+	defer cancel()
+
+	var v any = n
+	if w.cfg.Format == FormatSlack {
+		v = newSlackMessage(n)
+	}
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrap(err, "marshal notification failed")
+	}
+
+	interval := w.cfg.RetryInterval
+	for i := 0; ; i++ {
+		err = w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+
+		if i >= w.maxRetries {
+			return errors.Wrapf(err, "post failed after %d retries", i)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+	}
+}
+
+func (w *webhook) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.Url, bytes.NewReader(body))
+	if err != nil {
+		// the error contains the url
+		return errors.New("new request failed")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		// the *url.Error contains the url, which may contain the token
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return errors.Wrap(err, "do request failed")
+	}
+	defer resp.Body.Close()
+
+	// drain the body to reuse the connection
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// slackMessage is the message of the Slack incoming webhooks.
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Text   string       `json:"text"`
+	Fields []slackField `json:"fields,omitempty"`
+	Ts     int64        `json:"ts"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+func newSlackMessage(n *Notification) *slackMessage {
+	title := n.Alert
+	if n.Subject != "" {
+		title = fmt.Sprintf("%s %s", n.Alert, n.Subject)
+	}
+
+	color := "warning"
+	ts := n.StartsAt
+	switch {
+	case n.Status == StatusResolved:
+		color = "good"
+		ts = n.EndsAt
+	case n.Severity == SeverityCritical:
+		color = "danger"
+	}
+
+	fields := []slackField{
+		{Title: "Severity", Value: n.Severity, Short: true},
+		{Title: "Value", Value: fmt.Sprintf("%v (threshold %v)", n.Value, n.Threshold), Short: true},
+		{Title: "Since", Value: n.StartsAt.UTC().Format(time.RFC3339), Short: true},
+	}
+
+	keys := make([]string, 0, len(n.Labels))
+	for k := range n.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fields = append(fields, slackField{Title: k, Value: n.Labels[k], Short: true})
+	}
+
+	return &slackMessage{
+		Text: fmt.Sprintf("[%s] %s", strings.ToUpper(n.Status), title),
+		Attachments: []slackAttachment{{
+			Color:  color,
+			Text:   n.Summary,
+			Fields: fields,
+			Ts:     ts.Unix(),
+		}},
+	}
+}
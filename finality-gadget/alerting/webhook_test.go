@@ -0,0 +1,135 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestWebhookSlackMessage(t *testing.T) {
+	received := make(chan *slackMessage, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("content type %s", got)
+		}
+
+		var msg slackMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		received <- &msg
+	}))
+	defer srv.Close()
+
+	w := newWebhook(WebhookConfig{Name: "slack", Format: FormatSlack, Url: srv.URL})
+
+	startsAt := time.Unix(1700000000, 0)
+	err := w.send(context.Background(), &Notification{
+		Status: StatusFiring,
+		Incident: Incident{
+			Alert:     AlertPubRandRunway,
+			Subject:   "fp1",
+			Severity:  SeverityCritical,
+			Summary:   "the runway is low",
+			Value:     120,
+			Threshold: 500,
+			StartsAt:  startsAt,
+		},
+		Labels: map[string]string{"service": "operator", "chain_id": "42"},
+	})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	msg := <-received
+	if want := "[FIRING] pub_rand_runway fp1"; msg.Text != want {
+		t.Fatalf("text %q, want %q", msg.Text, want)
+	}
+
+	if len(msg.Attachments) != 1 {
+		t.Fatalf("got %d attachments", len(msg.Attachments))
+	}
+
+	attachment := msg.Attachments[0]
+	if attachment.Color != "danger" || attachment.Text != "the runway is low" || attachment.Ts != startsAt.Unix() {
+		t.Fatalf("unexpected attachment %+v", attachment)
+	}
+
+	want := []slackField{
+		{Title: "Severity", Value: SeverityCritical, Short: true},
+		{Title: "Value", Value: "120 (threshold 500)", Short: true},
+		{Title: "Since", Value: "2023-11-14T22:13:20Z", Short: true},
+		{Title: "chain_id", Value: "42", Short: true},
+		{Title: "service", Value: "operator", Short: true},
+	}
+	if len(attachment.Fields) != len(want) {
+		t.Fatalf("fields %+v, want %+v", attachment.Fields, want)
+	}
+	for i := range want {
+		if attachment.Fields[i] != want[i] {
+			t.Fatalf("field %d %+v, want %+v", i, attachment.Fields[i], want[i])
+		}
+	}
+}
+
+func TestWebhookRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries *int
+		failures   int32
+		want       int32
+		wantErr    bool
+	}{
+		{name: "retry until ok", maxRetries: nil, failures: 2, want: 3},
+		{name: "retries exhausted", maxRetries: intPtr(1), failures: 5, want: 2, wantErr: true},
+		{name: "retries disabled", maxRetries: intPtr(0), failures: 5, want: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusBadGateway)
+				}
+			}))
+			defer srv.Close()
+
+			w := newWebhook(WebhookConfig{
+				Name:          "hook",
+				Url:           srv.URL,
+				MaxRetries:    tt.maxRetries,
+				RetryInterval: time.Millisecond,
+			})
+
+			err := w.send(context.Background(), &Notification{Status: StatusFiring, Incident: Incident{Alert: "test"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("send err %v, want err %v", err, tt.wantErr)
+			}
+
+			if got := calls.Load(); got != tt.want {
+				t.Fatalf("got %d calls, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookConfigValidate(t *testing.T) {
+	cfg := WebhookConfig{Name: "hook", Url: "http://127.0.0.1:8080", MaxRetries: intPtr(0)}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("zero retries should be valid: %v", err)
+	}
+
+	cfg.MaxRetries = intPtr(-1)
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("negative retries should be invalid")
+	}
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+/// @title FinalityAttestationVerifier
+/// @notice The reference verifier for the Blitz finality attestations, which attest the
+/// l2 block (chainId, height, blockHash) is finalized by Babylon, signed by k of n signer keys.
+/// @dev The EIP-712 domain has no chainId and verifyingContract, so the same attestation
+/// can be verified on both the l2 and its parent chain.
+contract FinalityAttestationVerifier {
+    bytes32 public constant DOMAIN_TYPEHASH = keccak256("EIP712Domain(string name,string version)");
+    bytes32 public constant FINALITY_ATTESTATION_TYPEHASH =
+        keccak256("FinalityAttestation(uint256 chainId,uint64 height,bytes32 blockHash)");
+    bytes32 public immutable DOMAIN_SEPARATOR;
+
+    // secp256k1n / 2, to reject the malleable signatures
+    uint256 private constant HALF_CURVE_ORDER = 0x7fffffffffffffffffffffffffffffff5d576e7357a4501ddfe92f46681b20a0;
+
+    mapping(address => bool) public isSigner;
+    uint256 public immutable threshold;
+
+    error InvalidThreshold();
+    error InvalidSignatureLength();
+    error NotEnoughSignatures(uint256 got, uint256 need);
+
+    constructor(address[] memory signers, uint256 _threshold) {
+        uint256 count;
+        for (uint256 i = 0; i < signers.length; i++) {
+            if (!isSigner[signers[i]] && signers[i] != address(0)) {
+                isSigner[signers[i]] = true;
+                count++;
+            }
+        }
+        if (_threshold == 0 || _threshold > count) revert InvalidThreshold();
+        threshold = _threshold;
+
+        DOMAIN_SEPARATOR = keccak256(abi.encode(DOMAIN_TYPEHASH, keccak256("BlitzFinality"), keccak256("1")));
+    }
+
+    /// @notice The EIP-712 digest of the attestation.
+    function digest(uint256 chainId, uint64 height, bytes32 blockHash) public view returns (bytes32) {
+        bytes32 structHash = keccak256(abi.encode(FINALITY_ATTESTATION_TYPEHASH, chainId, height, blockHash));
+        return keccak256(abi.encodePacked("\x19\x01", DOMAIN_SEPARATOR, structHash));
+    }
+
+    /// @notice Verify the attestation is signed by at least `threshold` distinct signers.
+    /// @param signatures the 65 bytes `r | s | v` signatures, sorted by the signer address ascending.
+    function verify(uint256 chainId, uint64 height, bytes32 blockHash, bytes[] calldata signatures)
+        external
+        view
+        returns (bool)
+    {
+        bytes32 d = digest(chainId, height, blockHash);
+
+        uint256 valid;
+        address last;
+        for (uint256 i = 0; i < signatures.length; i++) {
+            address signer = _recover(d, signatures[i]);
+            // the ascending order makes sure each signer is counted once
+            if (signer > last && isSigner[signer]) {
+                valid++;
+                last = signer;
+            }
+        }
+
+        if (valid < threshold) revert NotEnoughSignatures(valid, threshold);
+        return true;
+    }
+
+    function _recover(bytes32 d, bytes calldata sig) private pure returns (address) {
+        if (sig.length != 65) revert InvalidSignatureLength();
+
+        bytes32 r = bytes32(sig[0:32]);
+        bytes32 s = bytes32(sig[32:64]);
+        uint8 v = uint8(sig[64]);
+        if (v < 27) v += 27;
+
+        if (uint256(s) > HALF_CURVE_ORDER || (v != 27 && v != 28)) {
+            return address(0);
+        }
+
+        return ecrecover(d, v, r, s);
+    }
+}
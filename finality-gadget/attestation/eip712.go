@@ -0,0 +1,119 @@
+package attestation
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DomainName is the EIP-712 domain name of the finality attestation.
+	DomainName = "BlitzFinality"
+	// DomainVersion is the EIP-712 domain version of the finality attestation.
+	DomainVersion = "1"
+)
+
+var (
+	// the domain has no chainId and verifyingContract, so the attestation can be verified
+	// on both the l2 and its parent chain, the l2 chain id is in the message.
+	domainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version)"))
+
+	// FinalityAttestationTypeHash is the EIP-712 type hash of the FinalityAttestation.
+	FinalityAttestationTypeHash = crypto.Keccak256Hash(
+		[]byte("FinalityAttestation(uint256 chainId,uint64 height,bytes32 blockHash)"))
+
+	// DomainSeparator is the EIP-712 domain separator of the finality attestation.
+	DomainSeparator = crypto.Keccak256Hash(
+		domainTypeHash.Bytes(),
+		crypto.Keccak256([]byte(DomainName)),
+		crypto.Keccak256([]byte(DomainVersion)),
+	)
+)
+
+// Attestation attests the l2 block (chainId, height, hash) is finalized by babylon.
+type Attestation struct {
+	ChainId   uint64      `json:"chainId"`
+	Height    uint64      `json:"height"`
+	BlockHash common.Hash `json:"blockHash"`
+}
+
+// SignedAttestation is the attestation signed by a signer key.
+type SignedAttestation struct {
+	Attestation
+	// The EIP-712 digest signed.
+	Digest common.Hash `json:"digest"`
+	// The address of the signer key.
+	Signer common.Address `json:"signer"`
+	// The 65 bytes `r | s | v` signature, v is 27 or 28.
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// StructHash returns the EIP-712 struct hash of the attestation.
+func (a *Attestation) StructHash() common.Hash {
+	return crypto.Keccak256Hash(
+		FinalityAttestationTypeHash.Bytes(),
+		math.U256Bytes(new(big.Int).SetUint64(a.ChainId)),
+		math.U256Bytes(new(big.Int).SetUint64(a.Height)),
+		a.BlockHash.Bytes(),
+	)
+}
+
+// Digest returns the EIP-712 digest to sign.
+func (a *Attestation) Digest() common.Hash {
+	return crypto.Keccak256Hash(
+		[]byte{0x19, 0x01},
+		DomainSeparator.Bytes(),
+		a.StructHash().Bytes(),
+	)
+}
+
+// Sign signs the attestation by the key.
+func (a *Attestation) Sign(key *ecdsa.PrivateKey) (*SignedAttestation, error) {
+	digest := a.Digest()
+
+	sig, err := crypto.Sign(digest.Bytes(), key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign the attestation")
+	}
+
+	// the v of ecrecover in solidity is 27 or 28
+	sig[crypto.RecoveryIDOffset] += 27
+
+	return &SignedAttestation{
+		Attestation: *a,
+		Digest:      digest,
+		Signer:      crypto.PubkeyToAddress(key.PublicKey),
+		Signature:   sig,
+	}, nil
+}
+
+// RecoverSigner returns the address signed the attestation by the signature.
+func (a *Attestation) RecoverSigner(signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, errors.Errorf("invalid signature length %d", len(signature))
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	// reject the malleable signatures, same as the solidity verifier
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+	if !crypto.ValidateSignatureValues(sig[crypto.RecoveryIDOffset], r, s, true) {
+		return common.Address{}, errors.New("invalid signature values")
+	}
+
+	pubKey, err := crypto.SigToPub(a.Digest().Bytes(), sig)
+	if err != nil {
+		return common.Address{}, errors.Wrap(err, "failed to recover the signer")
+	}
+
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
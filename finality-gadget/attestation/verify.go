@@ -0,0 +1,95 @@
+package attestation
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// ErrNotEnoughSignatures is returned when the valid signatures from the signers are less than the threshold.
+var ErrNotEnoughSignatures = errors.New("not enough valid signatures")
+
+// Verifier verifies the attestation is signed by k of the n signers, same as the solidity verifier.
+type Verifier struct {
+	signers   map[common.Address]struct{}
+	threshold int
+}
+
+// NewVerifier creates the verifier, the zero address is not a signer, same as the solidity verifier.
+func NewVerifier(signers []common.Address, threshold int) (*Verifier, error) {
+	set := make(map[common.Address]struct{}, len(signers))
+	for _, s := range signers {
+		if s != (common.Address{}) {
+			set[s] = struct{}{}
+		}
+	}
+
+	if threshold <= 0 || threshold > len(set) {
+		return nil, errors.Errorf("invalid threshold %d for %d signers", threshold, len(set))
+	}
+
+	return &Verifier{
+		signers:   set,
+		threshold: threshold,
+	}, nil
+}
+
+// Verify verifies the signatures of the attestation, which should be sorted by the signer address ascending.
+// Same as `verify` of the solidity verifier, a signature is only counted if its signer is known and greater than
+// the last counted one, so each signer is counted once, the invalid signatures are ignored,
+// but a signature not in 65 bytes fails the verification.
+func (v *Verifier) Verify(a *Attestation, signatures [][]byte) error {
+	var (
+		valid int
+		last  common.Address
+	)
+
+	for i, sig := range signatures {
+		if len(sig) != crypto.SignatureLength {
+			return errors.Errorf("invalid signature %d length %d", i, len(sig))
+		}
+
+		signer, err := a.RecoverSigner(sig)
+		if err != nil {
+			continue
+		}
+
+		if _, ok := v.signers[signer]; ok && bytes.Compare(signer.Bytes(), last.Bytes()) > 0 {
+			valid++
+			last = signer
+		}
+	}
+
+	if valid < v.threshold {
+		return errors.Wrapf(ErrNotEnoughSignatures, "got %d, need %d", valid, v.threshold)
+	}
+
+	return nil
+}
+
+// VerifySigned verifies the signed attestations are all for the same attestation,
+// and signed by k of the n signers, the signatures are sorted by the signer for Verify.
+func (v *Verifier) VerifySigned(signed []*SignedAttestation) error {
+	if len(signed) == 0 {
+		return errors.Wrap(ErrNotEnoughSignatures, "no attestation")
+	}
+
+	a := signed[0].Attestation
+	signed = slices.Clone(signed)
+	slices.SortFunc(signed, func(x, y *SignedAttestation) int {
+		return bytes.Compare(x.Signer.Bytes(), y.Signer.Bytes())
+	})
+
+	signatures := make([][]byte, 0, len(signed))
+	for _, s := range signed {
+		if s.Attestation != a {
+			return errors.Errorf("the attestations are different: %+v and %+v", a, s.Attestation)
+		}
+		signatures = append(signatures, s.Signature)
+	}
+
+	return v.Verify(&a, signatures)
+}
@@ -0,0 +1,184 @@
+package attestation
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var testAttestation = Attestation{
+	ChainId:   42,
+	Height:    100,
+	BlockHash: common.HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111"),
+}
+
+func TestDigest(t *testing.T) {
+	// the values of the solidity verifier
+	tests := []struct {
+		name string
+		got  common.Hash
+		want string
+	}{
+		{name: "domain separator", got: DomainSeparator, want: "0xac89789df0dfcc9629473914b91031541108edeed81c9bd179581b71732e5ad9"},
+		{name: "type hash", got: FinalityAttestationTypeHash, want: "0xa9abb7ac00dcd3b24a01de364749589bbc6a94e6e7f9a2ae488d76347108ad2e"},
+		{name: "struct hash", got: testAttestation.StructHash(), want: "0xc21dc1943f374cf1158471b0bcc73930cc6f55cf85f501ab31bbb8bb6f80aa58"},
+		{name: "digest", got: testAttestation.Digest(), want: "0x34d4d94d0c957fc9b89a7a684efeaab2cbc05326a65d7812ff877f6703f8132c"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != common.HexToHash(tt.want) {
+			t.Errorf("%s got %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestStructHashAbiEncode(t *testing.T) {
+	// `keccak256(abi.encode(FINALITY_ATTESTATION_TYPEHASH, chainId, height, blockHash))` in solidity
+	newType := func(name string) abi.Type {
+		typ, err := abi.NewType(name, "", nil)
+		if err != nil {
+			t.Fatalf("new type %s: %v", name, err)
+		}
+		return typ
+	}
+
+	args := abi.Arguments{
+		{Type: newType("bytes32")},
+		{Type: newType("uint256")},
+		{Type: newType("uint64")},
+		{Type: newType("bytes32")},
+	}
+
+	encoded, err := args.Pack(
+		[32]byte(FinalityAttestationTypeHash),
+		new(big.Int).SetUint64(testAttestation.ChainId),
+		testAttestation.Height,
+		[32]byte(testAttestation.BlockHash),
+	)
+	if err != nil {
+		t.Fatalf("abi encode: %v", err)
+	}
+
+	if got, want := testAttestation.StructHash(), crypto.Keccak256Hash(encoded); got != want {
+		t.Fatalf("struct hash %s, want %s", got, want)
+	}
+}
+
+// secp256k1N is the order of the secp256k1 curve.
+var secp256k1N = crypto.S256().Params().N
+
+func TestRecoverSignerRejectHighS(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	signed, err := testAttestation.Sign(key)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	signer, err := testAttestation.RecoverSigner(signed.Signature)
+	if err != nil || signer != signed.Signer {
+		t.Fatalf("recover got %s, %v, want %s", signer, err, signed.Signer)
+	}
+
+	// (r, n - s, v ^ 1) is also a valid signature for the same key, which is rejected
+	highS := make([]byte, crypto.SignatureLength)
+	copy(highS, signed.Signature)
+	s := new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(highS[32:64]))
+	s.FillBytes(highS[32:64])
+	highS[crypto.RecoveryIDOffset] ^= 1
+
+	if _, err := testAttestation.RecoverSigner(highS); err == nil {
+		t.Fatal("the high s signature should be rejected")
+	}
+
+	verifier, err := NewVerifier([]common.Address{signed.Signer}, 1)
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	if err := verifier.Verify(&testAttestation, [][]byte{highS}); !errors.Is(err, ErrNotEnoughSignatures) {
+		t.Fatalf("got %v, want ErrNotEnoughSignatures", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 3)
+	signed := make([]*SignedAttestation, 3)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("generate key: %v", err)
+		}
+		keys[i] = key
+
+		if signed[i], err = testAttestation.Sign(key); err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+	}
+
+	// sorted by the signer ascending
+	slices.SortFunc(signed, func(x, y *SignedAttestation) int {
+		return bytes.Compare(x.Signer.Bytes(), y.Signer.Bytes())
+	})
+
+	signers := []common.Address{signed[0].Signer, signed[1].Signer, signed[2].Signer}
+	verifier, err := NewVerifier(signers, 2)
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+
+	unknown, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	unknownSigned, err := testAttestation.Sign(unknown)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		signatures [][]byte
+		wantErr    bool
+	}{
+		{name: "ascending", signatures: [][]byte{signed[0].Signature, signed[2].Signature}},
+		{name: "all", signatures: [][]byte{signed[0].Signature, signed[1].Signature, signed[2].Signature}},
+		{name: "descending", signatures: [][]byte{signed[2].Signature, signed[0].Signature}, wantErr: true},
+		{name: "duplicated", signatures: [][]byte{signed[1].Signature, signed[1].Signature}, wantErr: true},
+		{name: "unknown signer", signatures: [][]byte{signed[0].Signature, unknownSigned.Signature}, wantErr: true},
+		{name: "invalid length", signatures: [][]byte{signed[0].Signature, signed[1].Signature, {0x01}}, wantErr: true},
+		{name: "below threshold", signatures: [][]byte{signed[1].Signature}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifier.Verify(&testAttestation, tt.signatures); (err != nil) != tt.wantErr {
+				t.Fatalf("verify err %v, want err %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// VerifySigned sorts the signatures
+	if err := verifier.VerifySigned([]*SignedAttestation{signed[2], signed[0]}); err != nil {
+		t.Fatalf("verify signed: %v", err)
+	}
+
+	other := testAttestation
+	other.Height++
+	otherSigned, err := other.Sign(keys[0])
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if err := verifier.VerifySigned([]*SignedAttestation{signed[1], otherSigned}); err == nil {
+		t.Fatal("the different attestations should be rejected")
+	}
+}
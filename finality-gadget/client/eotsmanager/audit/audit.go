@@ -0,0 +1,283 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// the max size of an entry line
+const maxEntrySize = 64 * 1024
+
+// Entry is a record of a signing call, it is chained by the hash of the previous entry,
+// so deleting or editing any entry will break the chain.
+type Entry struct {
+	Seq     uint64    `json:"seq"`
+	Time    time.Time `json:"time"`
+	Method  string    `json:"method"`
+	Uid     string    `json:"uid"`
+	ChainId string    `json:"chain_id,omitempty"`
+	Height  uint64    `json:"height,omitempty"`
+	// the number of the randomness for `CreateRandomnessPairList`
+	Num uint32 `json:"num,omitempty"`
+	// the sha256 of the message to sign
+	MsgHash string `json:"msg_hash,omitempty"`
+	// the error if the call failed
+	Error    string `json:"error,omitempty"`
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
+
+// computeHash returns the sha256 of the entry json without the hash.
+func (e *Entry) computeHash() (string, error) {
+	c := *e
+	c.Hash = ""
+
+	data, err := json.Marshal(&c)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the audit entry")
+	}
+
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// Log is the append only audit log file, one json entry per line.
+type Log struct {
+	file     *os.File
+	lastSeq  uint64
+	lastHash string
+	mu       sync.Mutex
+}
+
+// Open opens or creates the audit log, the entries will be verified to continue the chain.
+// If the last line is not complete, which happens if the process stopped while writing it,
+// the log is truncated back to the last entry. Any invalid complete entry fails the open,
+// as the log may be edited.
+func Open(logger *zap.Logger, path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, errors.Wrapf(err, "failed to create the dir for audit log %s", path)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the audit log %s", path)
+	}
+
+	last, validSize, partial, err := lastEntry(file)
+	if err != nil {
+		file.Close()
+		return nil, errors.Wrapf(err, "failed to verify the audit log %s", path)
+	}
+
+	if partial {
+		logger.Warn("the last line of the audit log is not complete, truncate it",
+			zap.String("path", path),
+			zap.Int64("size", validSize))
+
+		if err := file.Truncate(validSize); err != nil {
+			file.Close()
+			return nil, errors.Wrapf(err, "failed to truncate the audit log %s", path)
+		}
+	}
+
+	res := &Log{file: file}
+	if last != nil {
+		res.lastSeq = last.Seq
+		res.lastHash = last.Hash
+	}
+
+	return res, nil
+}
+
+// lastEntry returns the last entry and the size of the file up to it, all the complete lines must be
+// valid entries chained one by one. The partial is true if the last line has no '\n', which is not written completely.
+func lastEntry(file *os.File) (last *Entry, validSize int64, partial bool, err error) {
+	reader := bufio.NewReader(file)
+	for lineNum := 1; ; lineNum++ {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return nil, 0, false, readErr
+		}
+
+		if readErr == io.EOF {
+			return last, validSize, len(line) > 0, nil
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			e, err := checkNext(last, trimmed)
+			if err != nil {
+				return nil, 0, false, errors.Wrapf(err, "line %d: invalid audit entry after seq %d, the log may be edited", lineNum, seqOf(last))
+			}
+			last = e
+		}
+
+		validSize += int64(len(line))
+	}
+}
+
+// checkNext parses the line, and checks it is the entry chained to prev.
+func checkNext(prev *Entry, line []byte) (*Entry, error) {
+	if len(line) > maxEntrySize {
+		return nil, errors.Errorf("the line size %d is too large", len(line))
+	}
+
+	var e Entry
+	if err := json.Unmarshal(line, &e); err != nil {
+		return nil, errors.Wrap(err, "invalid audit entry")
+	}
+
+	hash, err := e.computeHash()
+	if err != nil {
+		return nil, err
+	}
+
+	if hash != e.Hash {
+		return nil, errors.Errorf("the hash of the entry %d is mismatched", e.Seq)
+	}
+
+	if prev != nil && (e.Seq != prev.Seq+1 || e.PrevHash != prev.Hash) {
+		return nil, errors.Errorf("the entry %d is not chained to the entry %d", e.Seq, prev.Seq)
+	}
+
+	return &e, nil
+}
+
+func seqOf(e *Entry) uint64 {
+	if e == nil {
+		return 0
+	}
+
+	return e.Seq
+}
+
+// Append appends the entry to the log, the seq, time and hashes will be filled.
+func (l *Log) Append(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Seq = l.lastSeq + 1
+	e.Time = time.Now().UTC()
+	e.PrevHash = l.lastHash
+
+	hash, err := e.computeHash()
+	if err != nil {
+		return err
+	}
+	e.Hash = hash
+
+	data, err := json.Marshal(&e)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the audit entry")
+	}
+
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return errors.Wrap(err, "failed to write the audit entry")
+	}
+
+	if err := l.file.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync the audit log")
+	}
+
+	l.lastSeq = e.Seq
+	l.lastHash = e.Hash
+
+	return nil
+}
+
+func (l *Log) Close() error {
+	return l.file.Close()
+}
+
+// MsgHash returns the hex sha256 of the msg.
+func MsgHash(msg []byte) string {
+	h := sha256.Sum256(msg)
+	return hex.EncodeToString(h[:])
+}
+
+// Verify verifies the chain of the entries from r, returns the number of the valid entries.
+func Verify(r io.Reader) (uint64, error) {
+	var (
+		count    uint64
+		prevHash string
+	)
+
+	err := forEach(r, func(line []byte, e *Entry) error {
+		if e.Seq != count+1 {
+			return errors.Errorf("entry %d: the seq should be %d, some entries may be deleted", e.Seq, count+1)
+		}
+
+		if e.PrevHash != prevHash {
+			return errors.Errorf("entry %d: the prev hash is mismatched, the chain is broken", e.Seq)
+		}
+
+		hash, err := e.computeHash()
+		if err != nil {
+			return err
+		}
+
+		if hash != e.Hash {
+			return errors.Errorf("entry %d: the hash is mismatched, the entry may be edited", e.Seq)
+		}
+
+		prevHash = e.Hash
+		count++
+
+		return nil
+	})
+
+	return count, err
+}
+
+// Export writes the entries which seq in [from, to] from r into w, to = 0 means no limit.
+func Export(r io.Reader, w io.Writer, from, to uint64) (uint64, error) {
+	var count uint64
+
+	err := forEach(r, func(line []byte, e *Entry) error {
+		if e.Seq < from || (to != 0 && e.Seq > to) {
+			return nil
+		}
+
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return errors.Wrap(err, "failed to write the audit entry")
+		}
+		count++
+
+		return nil
+	})
+
+	return count, err
+}
+
+func forEach(r io.Reader, f func(line []byte, e *Entry) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxEntrySize)
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return errors.Wrapf(err, "line %d: invalid audit entry", lineNum)
+		}
+
+		if err := f(line, &e); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,193 @@
+package audit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// writeTestLog appends n entries into a new audit log, and returns the path.
+func writeTestLog(t *testing.T, n int) string {
+	path := filepath.Join(t.TempDir(), "eots-audit.log")
+
+	l, err := Open(zaptest.NewLogger(t), path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer l.Close()
+
+	for i := 1; i <= n; i++ {
+		if err := l.Append(Entry{Method: "SignEOTS", Uid: "01", Height: uint64(i), MsgHash: MsgHash([]byte{byte(i)})}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	return path
+}
+
+func readLines(t *testing.T, path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func writeLines(t *testing.T, path string, lines []string, suffix string) {
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+suffix), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func verifyFile(t *testing.T, path string) (uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer file.Close()
+
+	return Verify(file)
+}
+
+func TestVerify(t *testing.T) {
+	path := writeTestLog(t, 3)
+
+	count, err := verifyFile(t, path)
+	if err != nil || count != 3 {
+		t.Fatalf("verify got %d, %v, want 3 entries", count, err)
+	}
+
+	lines := readLines(t, path)
+	tests := []struct {
+		name  string
+		lines []string
+	}{
+		{name: "deleted", lines: []string{lines[0], lines[2]}},
+		{name: "edited", lines: []string{lines[0], strings.Replace(lines[1], `"height":2`, `"height":9`, 1), lines[2]}},
+		{name: "reordered", lines: []string{lines[1], lines[0], lines[2]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeLines(t, path, tt.lines, "\n")
+			if _, err := verifyFile(t, path); err == nil {
+				t.Fatal("the broken chain should fail the verification")
+			}
+		})
+	}
+}
+
+func TestOpenContinuesChain(t *testing.T) {
+	path := writeTestLog(t, 2)
+
+	l, err := Open(zaptest.NewLogger(t), path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if err := l.Append(Entry{Method: "SignSchnorrSig", Uid: "01"}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	l.Close()
+
+	count, err := verifyFile(t, path)
+	if err != nil || count != 3 {
+		t.Fatalf("verify got %d, %v, want 3 entries", count, err)
+	}
+}
+
+func TestOpenTruncatePartialLine(t *testing.T) {
+	path := writeTestLog(t, 2)
+
+	valid, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	// the process stopped while writing the entry
+	partial := append(bytes.Clone(valid), []byte(`{"seq":3,"time":"2026-`)...)
+	if err := os.WriteFile(path, partial, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	l, err := Open(zaptest.NewLogger(t), path)
+	if err != nil {
+		t.Fatalf("open with a partial line: %v", err)
+	}
+	defer l.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !bytes.Equal(data, valid) {
+		t.Fatalf("got %q after truncated, want %q", data, valid)
+	}
+
+	if err := l.Append(Entry{Method: "SignEOTS", Uid: "01", Height: 3}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	count, err := verifyFile(t, path)
+	if err != nil || count != 3 {
+		t.Fatalf("verify got %d, %v, want 3 entries", count, err)
+	}
+}
+
+func TestOpenRejectTamperedLastLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+	}{
+		{
+			name: "edited",
+			tamper: func(lines []string) []string {
+				lines[2] = strings.Replace(lines[2], `"height":3`, `"height":9`, 1)
+				return lines
+			},
+		},
+		{
+			name: "not chained",
+			tamper: func(lines []string) []string {
+				return []string{lines[0], lines[2]}
+			},
+		},
+		{
+			name: "not json",
+			tamper: func(lines []string) []string {
+				lines[2] = "edited"
+				return lines
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestLog(t, 3)
+			lines := tt.tamper(readLines(t, path))
+			writeLines(t, path, lines, "\n")
+
+			before, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+
+			if _, err := Open(zaptest.NewLogger(t), path); err == nil || !strings.Contains(err.Error(), "line ") {
+				t.Fatalf("open got %v, want the error of the line", err)
+			}
+
+			// the tampered entry is kept for the investigation
+			after, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if !bytes.Equal(before, after) {
+				t.Fatal("the tampered log should not be changed")
+			}
+		})
+	}
+}
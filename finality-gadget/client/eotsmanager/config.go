@@ -0,0 +1,258 @@
+package eotsmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
+)
+
+const (
+	// ModeRemote connects the eotsd by gRPC.
+	ModeRemote = "remote"
+	// ModeLocal opens the eotsmanager key store and db in the operator process.
+	ModeLocal = "local"
+)
+
+const (
+	defaultLocalKeyringBackend  = "test"
+	defaultLocalDBFileName      = "eots.db"
+	defaultProtectionDBFileName = "eots-protection.db"
+	defaultAuditLogFileName     = "eots-audit.log"
+	defaultHealthCheckInterval  = 10 * time.Second
+	defaultRequestTimeout       = 30 * time.Second
+	defaultMaxRetries           = 3
+	defaultRetryInterval        = time.Second
+)
+
+type Config struct {
+	// The mode of eotsmanager, `remote` or `local`, default is `remote`.
+	Mode string `yaml:"mode,omitempty"`
+	// The config for the `local` mode.
+	Local LocalConfig `yaml:"local,omitempty"`
+
+	// The remote address for eotsmanager
+	RemoteAddr string `yaml:"remote_address,omitempty"`
+	// The remote addresses for eotsmanager, the client will fail over to the next one
+	// if the current one is unavailable, `remote_address` will be the first one if set.
+	RemoteAddrs []string `yaml:"remote_addresses,omitempty"`
+	// The TLS config to connect the eotsmanager.
+	TLS TLSConfig `yaml:"tls,omitempty"`
+	// The bearer token sent by `authorization` metadata, for the auth proxy in front of the eotsmanager.
+	Token string `yaml:"token,omitempty" secret:"true"`
+	// The file of the bearer token, will be read for each request so it can be rotated.
+	TokenFile string `yaml:"token_file,omitempty"`
+	// The HMAC key for the eotsmanager, same as the `HMAC_KEY` of eotsd.
+	HMACKey string `yaml:"hmac_key,omitempty" secret:"true"`
+	// The interval to check the health of each eotsmanager, default is 10s.
+	HealthCheckInterval time.Duration `yaml:"health_check_interval,omitempty"`
+	// The timeout for each request, default is 30s.
+	RequestTimeout time.Duration `yaml:"request_timeout,omitempty"`
+	// The max retries on the transient errors, default is 3.
+	MaxRetries uint64 `yaml:"max_retries,omitempty"`
+	// The interval between the retries, default is 1s.
+	RetryInterval time.Duration `yaml:"retry_interval,omitempty"`
+	// The slashing protection db path, default is `data/eots-protection.db` in finality provider home.
+	ProtectionDBPath string `yaml:"protection_db_path,omitempty"`
+	// The signing audit log path, default is `data/eots-audit.log` in finality provider home.
+	AuditLogPath string `yaml:"audit_log_path,omitempty"`
+}
+
+type TLSConfig struct {
+	// Enable TLS, the system CAs will be used if no `ca_file`.
+	Enabled bool `yaml:"enabled,omitempty"`
+	// The CA file to verify the eotsmanager.
+	CAFile string `yaml:"ca_file,omitempty"`
+	// The client cert and key for mutual-TLS.
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+	// The server name to verify, default is the host of the address.
+	ServerName string `yaml:"server_name,omitempty"`
+}
+
+// LocalConfig is the config to open the eotsmanager in the operator process,
+// it should not be used when the eotsd is running with the same home.
+type LocalConfig struct {
+	// The eotsd home path, which contains the keyring and the `data/eots.db`.
+	HomePath string `yaml:"home_path,omitempty"`
+	// The keyring backend, `test` or `file`, default is `test`.
+	KeyringBackend string `yaml:"keyring_backend,omitempty"`
+	// The eots db path, default is `data/eots.db` in the home path.
+	DBPath string `yaml:"db_path,omitempty"`
+	// The file of the passphrase to unlock the key for the `file` keyring.
+	PassphraseFile string `yaml:"passphrase_file,omitempty"`
+	// The passphrase to unlock the key, only can be set by the env
+	// `FINALITY_GADGET_EOTS_MANAGER_LOCAL_PASSPHRASE`.
+	Passphrase string `yaml:"-"`
+}
+
+func (c *Config) WithEnv() {
+	c.Mode = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_MODE", c.Mode)
+	c.Local.HomePath = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_LOCAL_HOME_PATH", c.Local.HomePath)
+	c.Local.KeyringBackend = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_LOCAL_KEYRING_BACKEND", c.Local.KeyringBackend)
+	c.Local.DBPath = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_LOCAL_DB_PATH", c.Local.DBPath)
+	c.Local.PassphraseFile = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_LOCAL_PASSPHRASE_FILE", c.Local.PassphraseFile)
+	c.Local.Passphrase = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_LOCAL_PASSPHRASE", c.Local.Passphrase)
+	c.RemoteAddr = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_REMOTE_ADDRESS", c.RemoteAddr)
+	c.RemoteAddrs = utils.LookupEnvStrList("FINALITY_GADGET_EOTS_MANAGER_REMOTE_ADDRESSES", c.RemoteAddrs)
+	c.TLS.Enabled = utils.LookupEnvBool("FINALITY_GADGET_EOTS_MANAGER_TLS_ENABLED", c.TLS.Enabled)
+	c.TLS.CAFile = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_TLS_CA_FILE", c.TLS.CAFile)
+	c.TLS.CertFile = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_TLS_CERT_FILE", c.TLS.CertFile)
+	c.TLS.KeyFile = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_TLS_KEY_FILE", c.TLS.KeyFile)
+	c.TLS.ServerName = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_TLS_SERVER_NAME", c.TLS.ServerName)
+	c.Token = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_TOKEN", c.Token)
+	c.TokenFile = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_TOKEN_FILE", c.TokenFile)
+	c.HMACKey = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_HMAC_KEY", c.HMACKey)
+	c.HealthCheckInterval = utils.LookupEnvDuration("FINALITY_GADGET_EOTS_MANAGER_HEALTH_CHECK_INTERVAL", c.HealthCheckInterval)
+	c.RequestTimeout = utils.LookupEnvDuration("FINALITY_GADGET_EOTS_MANAGER_REQUEST_TIMEOUT", c.RequestTimeout)
+	c.MaxRetries = utils.LookupEnvUint64("FINALITY_GADGET_EOTS_MANAGER_MAX_RETRIES", c.MaxRetries)
+	c.RetryInterval = utils.LookupEnvDuration("FINALITY_GADGET_EOTS_MANAGER_RETRY_INTERVAL", c.RetryInterval)
+	c.ProtectionDBPath = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_PROTECTION_DB_PATH", c.ProtectionDBPath)
+	c.AuditLogPath = utils.LookupEnvStr("FINALITY_GADGET_EOTS_MANAGER_AUDIT_LOG_PATH", c.AuditLogPath)
+}
+
+// Endpoints returns all the remote addresses, `remote_address` first.
+func (c *Config) Endpoints() []string {
+	res := make([]string, 0, len(c.RemoteAddrs)+1)
+	if c.RemoteAddr != "" {
+		res = append(res, c.RemoteAddr)
+	}
+
+	for _, addr := range c.RemoteAddrs {
+		if addr != c.RemoteAddr {
+			res = append(res, addr)
+		}
+	}
+
+	return res
+}
+
+func (c *Config) withDefault() {
+	if c.HealthCheckInterval == 0 {
+		c.HealthCheckInterval = defaultHealthCheckInterval
+	}
+
+	if c.RequestTimeout == 0 {
+		c.RequestTimeout = defaultRequestTimeout
+	}
+
+	if c.MaxRetries == 0 {
+		c.MaxRetries = defaultMaxRetries
+	}
+
+	if c.RetryInterval == 0 {
+		c.RetryInterval = defaultRetryInterval
+	}
+}
+
+// IsLocal returns true if the eotsmanager is opened in the operator process.
+func (c *Config) IsLocal() bool {
+	return c.Mode == ModeLocal
+}
+
+func (c *Config) Validate() error {
+	var errs utils.ConfigErrors
+
+	switch c.Mode {
+	case "", ModeRemote:
+	case ModeLocal:
+		errs.Merge("local", c.Local.Validate())
+		return errs.Err()
+	default:
+		errs.Addf("mode", "unknown eotsmanager mode %s", c.Mode)
+		return errs.Err()
+	}
+
+	if len(c.Endpoints()) == 0 {
+		errs.Addf("remote_address", "no eotsmanager remote address")
+	}
+
+	for _, addr := range c.Endpoints() {
+		errs.Add("remote_addresses", utils.CheckHostPort(addr))
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		errs.Addf("tls", "the tls cert_file and key_file should be set together")
+	}
+
+	if c.Token != "" && c.TokenFile != "" {
+		errs.Addf("token", "the token and token_file should not be set together")
+	}
+
+	if (c.Token != "" || c.TokenFile != "") && !c.TLS.Enabled {
+		errs.Addf("token", "the token should be sent by tls, enable the tls")
+	}
+
+	if c.HealthCheckInterval < 0 {
+		errs.Addf("health_check_interval", "should not be negative")
+	}
+
+	if c.RequestTimeout < 0 {
+		errs.Addf("request_timeout", "should not be negative")
+	}
+
+	if c.RetryInterval < 0 {
+		errs.Addf("retry_interval", "should not be negative")
+	}
+
+	return errs.Err()
+}
+
+// ProtectionDBFilePath returns the protection db path, use the default path in home if not set.
+func (c *Config) ProtectionDBFilePath(homePath string) string {
+	if c.ProtectionDBPath != "" {
+		return c.ProtectionDBPath
+	}
+
+	return filepath.Join(homePath, "data", defaultProtectionDBFileName)
+}
+
+// AuditLogFilePath returns the audit log path, use the default path in home if not set.
+func (c *Config) AuditLogFilePath(homePath string) string {
+	if c.AuditLogPath != "" {
+		return c.AuditLogPath
+	}
+
+	return filepath.Join(homePath, "data", defaultAuditLogFileName)
+}
+
+func (c *LocalConfig) withDefault() {
+	if c.KeyringBackend == "" {
+		c.KeyringBackend = defaultLocalKeyringBackend
+	}
+
+	if c.DBPath == "" {
+		c.DBPath = filepath.Join(c.HomePath, "data", defaultLocalDBFileName)
+	}
+}
+
+func (c *LocalConfig) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.HomePath == "" {
+		errs.Addf("home_path", "no eotsmanager home path for the local mode")
+	}
+
+	if c.Passphrase != "" && c.PassphraseFile != "" {
+		errs.Addf("passphrase_file", "the passphrase and passphrase_file should not be set together")
+	}
+
+	return errs.Err()
+}
+
+// LoadPassphrase returns the passphrase from the env or the file.
+func (c *LocalConfig) LoadPassphrase() (string, error) {
+	if c.PassphraseFile == "" {
+		return c.Passphrase, nil
+	}
+
+	data, err := os.ReadFile(c.PassphraseFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the passphrase file %s: %w", c.PassphraseFile, err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
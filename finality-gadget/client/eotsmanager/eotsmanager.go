@@ -1,12 +1,18 @@
 package eotsmanager
 
 import (
+	"context"
+	"encoding/hex"
+	"time"
+
 	fpeotsmanager "github.com/babylonlabs-io/finality-provider/eotsmanager"
-	"github.com/babylonlabs-io/finality-provider/eotsmanager/client"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+
+	"github.com/alt-research/blitz/finality-gadget/client/eotsmanager/audit"
+	"github.com/alt-research/blitz/finality-gadget/client/eotsmanager/protection"
 )
 
 var _ fpeotsmanager.EOTSManager = &EOTSManagerClient{}
@@ -15,31 +21,138 @@ type EOTSManagerClient struct {
 	inner  fpeotsmanager.EOTSManager
 	cfg    Config
 	logger *zap.Logger
-}
 
-type Config struct {
-	// The remote address for eotsmanager
-	RemoteAddr string `yaml:"remote_address"`
+	// protection records every EOTS signature to prevent double sign.
+	protection *protection.DB
+	// auditLog records every signing call in a hash chain.
+	auditLog *audit.Log
+
+	// signGate is not nil if the operator is in active/standby mode,
+	// only the active operator can sign.
+	signGate ISignGate
+	ctx      context.Context
+	cancel   context.CancelFunc
 }
 
-func (c *Config) WithEnv() {
-	// TODO
+// ErrTookOver is returned when the request waited the operator to be the active one,
+// the request may be made by the stale state, so it is refused to retry.
+var ErrTookOver = errors.New("the operator became the active one while the signing waited, retry by the latest state")
+
+// ErrAuditFailed is returned when the signing call can not be recorded by the audit log,
+// the result is dropped so no signature is made without the record.
+var ErrAuditFailed = errors.New("failed to append the eots audit log")
+
+// ISignGate blocks the signing until the operator is allowed to sign.
+type ISignGate interface {
+	IsLeader() bool
+	WaitLeader(ctx context.Context) error
 }
 
-// Create eots manager client
-func NewEOTSManagerClient(logger *zap.Logger, cfg Config) (*EOTSManagerClient, error) {
-	cli, err := client.NewEOTSManagerGRpcClient(cfg.RemoteAddr, "")
+// Create eots manager client, the homePath is the finality provider home for the default protection db path.
+func NewEOTSManagerClient(logger *zap.Logger, cfg Config, homePath string) (*EOTSManagerClient, error) {
+	protectionDB, err := protection.Open(cfg.ProtectionDBFilePath(homePath))
 	if err != nil {
-		return nil, errors.Wrapf(err, "create eotsmanager client failed: %v", cfg.RemoteAddr)
+		return nil, errors.Wrap(err, "open eots protection db failed")
 	}
 
+	auditLog, err := audit.Open(logger, cfg.AuditLogFilePath(homePath))
+	if err != nil {
+		protectionDB.Close()
+		return nil, errors.Wrap(err, "open eots audit log failed")
+	}
+
+	cli, err := newInnerEOTSManager(logger, cfg)
+	if err != nil {
+		protectionDB.Close()
+		auditLog.Close()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &EOTSManagerClient{
-		inner:  cli,
-		cfg:    cfg,
-		logger: logger,
+		inner:      cli,
+		cfg:        cfg,
+		logger:     logger,
+		protection: protectionDB,
+		auditLog:   auditLog,
+		ctx:        ctx,
+		cancel:     cancel,
 	}, nil
 }
 
+func newInnerEOTSManager(logger *zap.Logger, cfg Config) (fpeotsmanager.EOTSManager, error) {
+	var (
+		cli fpeotsmanager.EOTSManager
+		err error
+	)
+	if cfg.IsLocal() {
+		cli, err = newLocalEOTSManager(logger, cfg.Local, defaultRequestTimeout)
+	} else {
+		cli, err = newRemoteEOTSManager(logger, cfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cli, nil
+}
+
+// keyCheckChainID is the chain id to derive the public randomness by CheckKey, no finality provider votes for it.
+var keyCheckChainID = []byte("blitz-eots-key-check")
+
+// keyCheckDBTimeout is the timeout to open the local eots db by CheckKey.
+const keyCheckDBTimeout = 3 * time.Second
+
+// CheckKey checks the eotsmanager holds the eots key of uid by deriving a public randomness, nothing is signed.
+// It opens neither the protection db nor the audit log. In remote mode it can run along with the operator,
+// but in local mode it opens the eots db, which is locked by the running operator, so the check fails then.
+func CheckKey(logger *zap.Logger, cfg Config, uid []byte) error {
+	var cli fpeotsmanager.EOTSManager
+	if cfg.IsLocal() {
+		local, err := newLocalEOTSManager(logger, cfg.Local, keyCheckDBTimeout)
+		if err != nil {
+			return errors.Wrap(err, "failed to open the local eots key store, stop the operator first as it locks the eots db")
+		}
+		cli = local
+	} else {
+		remote, err := newRemoteEOTSManager(logger, cfg)
+		if err != nil {
+			return err
+		}
+		cli = remote
+	}
+	defer cli.Close()
+
+	if _, err := cli.CreateRandomnessPairList(uid, keyCheckChainID, 1, 1); err != nil {
+		return errors.Wrap(err, "failed to derive the public randomness by the eots key")
+	}
+
+	return nil
+}
+
+// SetSignGate makes the client only sign when the gate allows,
+// the signing will block until then.
+func (e *EOTSManagerClient) SetSignGate(gate ISignGate) {
+	e.signGate = gate
+}
+
+// waitSignGate returns nil if the operator can sign now. If the operator is standby, it waits to be
+// the active one, then refuses the request, as the request was made by the state before the previous
+// active operator stopped, the caller should retry by the latest state.
+func (e *EOTSManagerClient) waitSignGate() error {
+	if e.signGate == nil || e.signGate.IsLeader() {
+		return nil
+	}
+
+	e.logger.Info("the operator is standby, wait to be the active one before signing")
+	if err := e.signGate.WaitLeader(e.ctx); err != nil {
+		return errors.Wrap(err, "wait to be the active operator failed")
+	}
+
+	return ErrTookOver
+}
+
 // CreateRandomnessPairList generates a list of Schnorr randomness pairs from
 // startHeight to startHeight+(num-1) where num means the number of public randomness
 // It fails if the finality provider does not exist or a randomness pair has been created before
@@ -47,39 +160,143 @@ func NewEOTSManagerClient(logger *zap.Logger, cfg Config) (*EOTSManagerClient, e
 // NOTE: the randomness is deterministically generated based on the EOTS key, chainID and
 // block height
 func (e *EOTSManagerClient) CreateRandomnessPairList(uid []byte, chainID []byte, startHeight uint64, num uint32) ([]*btcec.FieldVal, error) {
+	entry := audit.Entry{
+		Method:  "CreateRandomnessPairList",
+		Uid:     hex.EncodeToString(uid),
+		ChainId: hex.EncodeToString(chainID),
+		Height:  startHeight,
+		Num:     num,
+	}
+
+	if err := e.waitSignGate(); err != nil {
+		return nil, e.audit(entry, err)
+	}
+
 	res, err := e.inner.CreateRandomnessPairList(uid, chainID, startHeight, num)
 	e.logger.Sugar().Debugf("CreateRandomnessPairList %v %v", startHeight, res)
+	if err := e.audit(entry, err); err != nil {
+		return nil, err
+	}
 
-	return res, err
+	return res, nil
 }
 
 func (e *EOTSManagerClient) CreateRandomnessPairListWithInterval(uid []byte, chainID []byte, startHeight uint64, num uint32, interval uint64) ([]*btcec.FieldVal, error) {
+	entry := audit.Entry{
+		Method:  "CreateRandomnessPairListWithInterval",
+		Uid:     hex.EncodeToString(uid),
+		ChainId: hex.EncodeToString(chainID),
+		Height:  startHeight,
+		Num:     num,
+	}
+
+	if err := e.waitSignGate(); err != nil {
+		return nil, e.audit(entry, err)
+	}
+
 	res, err := e.inner.CreateRandomnessPairListWithInterval(uid, chainID, startHeight, num, interval)
 	e.logger.Sugar().Debugf("CreateRandomnessPairListWithInterval %v %v %v", startHeight, interval, res)
+	if err := e.audit(entry, err); err != nil {
+		return nil, err
+	}
 
-	return res, err
+	return res, nil
 }
 
 // SignEOTS signs an EOTS using the private key of the finality provider and the corresponding
 // secret randomness of the given chain at the given height
 // It fails if the finality provider does not exist or there's no randomness committed to the given height
 // or passPhrase is incorrect
+// It also fails if a different msg was signed at the height before, the signed msg is recorded
+// before signing, so the height is locked to the msg even if the remote signing failed.
 func (e *EOTSManagerClient) SignEOTS(uid []byte, chainID []byte, msg []byte, height uint64) (*btcec.ModNScalar, error) {
-	return e.inner.SignEOTS(uid, chainID, msg, height)
+	return e.signEOTS("SignEOTS", uid, chainID, msg, height, e.inner.SignEOTS)
 }
 
 // UnsafeSignEOTS should only be used in e2e tests for demonstration purposes.
-// Does not offer double sign protection.
+// The eotsmanager does not offer double sign protection for it, so it is checked by the same
+// sign gate, protection db and audit log as SignEOTS here.
 // Use SignEOTS for real operations.
 func (e *EOTSManagerClient) UnsafeSignEOTS(uid []byte, chainID []byte, msg []byte, height uint64) (*btcec.ModNScalar, error) {
-	return e.inner.UnsafeSignEOTS(uid, chainID, msg, height)
+	return e.signEOTS("UnsafeSignEOTS", uid, chainID, msg, height, e.inner.UnsafeSignEOTS)
+}
+
+func (e *EOTSManagerClient) signEOTS(
+	method string,
+	uid []byte, chainID []byte, msg []byte, height uint64,
+	sign func(uid []byte, chainID []byte, msg []byte, height uint64) (*btcec.ModNScalar, error),
+) (*btcec.ModNScalar, error) {
+	entry := audit.Entry{
+		Method:  method,
+		Uid:     hex.EncodeToString(uid),
+		ChainId: hex.EncodeToString(chainID),
+		Height:  height,
+		MsgHash: audit.MsgHash(msg),
+	}
+
+	if err := e.waitSignGate(); err != nil {
+		return nil, e.audit(entry, err)
+	}
+
+	if err := e.protection.CheckAndRecord(uid, chainID, height, msg); err != nil {
+		e.logger.Error("refuse to sign EOTS",
+			zap.String("method", method),
+			zap.String("uid", entry.Uid),
+			zap.Uint64("height", height),
+			zap.Error(err))
+		return nil, e.audit(entry, err)
+	}
+
+	res, err := sign(uid, chainID, msg, height)
+	if err := e.audit(entry, err); err != nil {
+		return nil, err
+	}
+
+	return res, nil
 }
 
 // SignSchnorrSig signs a Schnorr signature using the private key of the finality provider
 // It fails if the finality provider does not exist or the message size is not 32 bytes
 // or passPhrase is incorrect
 func (e *EOTSManagerClient) SignSchnorrSig(uid []byte, msg []byte) (*schnorr.Signature, error) {
-	return e.inner.SignSchnorrSig(uid, msg)
+	entry := audit.Entry{
+		Method:  "SignSchnorrSig",
+		Uid:     hex.EncodeToString(uid),
+		MsgHash: audit.MsgHash(msg),
+	}
+
+	if err := e.waitSignGate(); err != nil {
+		return nil, e.audit(entry, err)
+	}
+
+	res, err := e.inner.SignSchnorrSig(uid, msg)
+	if err := e.audit(entry, err); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// audit appends the signing call to the audit log, including the calls refused by the sign gate
+// and the protection db. It returns the callErr if the call failed, or ErrAuditFailed if the
+// successful call can not be recorded, then the result should be dropped.
+func (e *EOTSManagerClient) audit(entry audit.Entry, callErr error) error {
+	if callErr != nil {
+		entry.Error = callErr.Error()
+	}
+
+	if err := e.auditLog.Append(entry); err != nil {
+		e.logger.Error("append eots audit log failed",
+			zap.String("method", entry.Method),
+			zap.Uint64("height", entry.Height),
+			zap.Error(err))
+
+		if callErr == nil {
+			return errors.Wrap(ErrAuditFailed, err.Error())
+		}
+	}
+
+	return callErr
 }
 
 // Unlock makes the private key for the given EOTS key (uid) accessible in memory using the provided keyring password.
@@ -96,5 +313,15 @@ func (e *EOTSManagerClient) Backup(dbPath string, backupDir string) (string, err
 }
 
 func (e *EOTSManagerClient) Close() error {
+	e.cancel()
+
+	if err := e.protection.Close(); err != nil {
+		e.logger.Error("close eots protection db failed", zap.Error(err))
+	}
+
+	if err := e.auditLog.Close(); err != nil {
+		e.logger.Error("close eots audit log failed", zap.Error(err))
+	}
+
 	return e.inner.Close()
 }
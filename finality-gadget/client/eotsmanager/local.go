@@ -0,0 +1,148 @@
+package eotsmanager
+
+import (
+	"encoding/hex"
+	"path/filepath"
+	"sync"
+	"time"
+
+	fpeotsmanager "github.com/babylonlabs-io/finality-provider/eotsmanager"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const keyringBackendFile = "file"
+
+var _ fpeotsmanager.EOTSManager = &localEOTSManager{}
+
+// localEOTSManager opens the eotsmanager key store and db in the operator process,
+// the key will be unlocked by the passphrase before the first signing.
+type localEOTSManager struct {
+	fpeotsmanager.EOTSManager
+
+	logger     *zap.Logger
+	db         kvdb.Backend
+	passphrase string
+	needUnlock bool
+
+	unlocked map[string]struct{}
+	mu       sync.Mutex
+}
+
+// newLocalEOTSManager opens the eots db, it fails in dbTimeout if the db is locked by another process.
+func newLocalEOTSManager(logger *zap.Logger, cfg LocalConfig, dbTimeout time.Duration) (*localEOTSManager, error) {
+	cfg.withDefault()
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid local eotsmanager config")
+	}
+
+	passphrase, err := cfg.LoadPassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
+		DBPath:     filepath.Dir(cfg.DBPath),
+		DBFileName: filepath.Base(cfg.DBPath),
+		DBTimeout:  dbTimeout,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the eots db %s", cfg.DBPath)
+	}
+
+	logger = logger.With(zap.String("module", "eotsmanager"))
+
+	em, err := fpeotsmanager.NewLocalEOTSManager(cfg.HomePath, cfg.KeyringBackend, db, logger)
+	if err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "failed to create the local eotsmanager")
+	}
+
+	logger.Info("use the local eotsmanager",
+		zap.String("home", cfg.HomePath),
+		zap.String("keyring_backend", cfg.KeyringBackend))
+
+	return &localEOTSManager{
+		EOTSManager: em,
+		logger:      logger,
+		db:          db,
+		passphrase:  passphrase,
+		needUnlock:  cfg.KeyringBackend == keyringBackendFile,
+		unlocked:    make(map[string]struct{}, 4),
+	}, nil
+}
+
+// unlock unlocks the key of uid once, only the `file` keyring need it.
+func (m *localEOTSManager) unlock(uid []byte) error {
+	if !m.needUnlock {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := hex.EncodeToString(uid)
+	if _, ok := m.unlocked[key]; ok {
+		return nil
+	}
+
+	if err := m.EOTSManager.Unlock(uid, m.passphrase); err != nil {
+		return errors.Wrapf(err, "failed to unlock the eots key %s", key)
+	}
+
+	m.logger.Info("unlocked the eots key", zap.String("uid", key))
+	m.unlocked[key] = struct{}{}
+
+	return nil
+}
+
+func (m *localEOTSManager) CreateRandomnessPairList(uid []byte, chainID []byte, startHeight uint64, num uint32) ([]*btcec.FieldVal, error) {
+	if err := m.unlock(uid); err != nil {
+		return nil, err
+	}
+
+	return m.EOTSManager.CreateRandomnessPairList(uid, chainID, startHeight, num)
+}
+
+func (m *localEOTSManager) CreateRandomnessPairListWithInterval(uid []byte, chainID []byte, startHeight uint64, num uint32, interval uint64) ([]*btcec.FieldVal, error) {
+	if err := m.unlock(uid); err != nil {
+		return nil, err
+	}
+
+	return m.EOTSManager.CreateRandomnessPairListWithInterval(uid, chainID, startHeight, num, interval)
+}
+
+func (m *localEOTSManager) SignEOTS(uid []byte, chainID []byte, msg []byte, height uint64) (*btcec.ModNScalar, error) {
+	if err := m.unlock(uid); err != nil {
+		return nil, err
+	}
+
+	return m.EOTSManager.SignEOTS(uid, chainID, msg, height)
+}
+
+func (m *localEOTSManager) UnsafeSignEOTS(uid []byte, chainID []byte, msg []byte, height uint64) (*btcec.ModNScalar, error) {
+	if err := m.unlock(uid); err != nil {
+		return nil, err
+	}
+
+	return m.EOTSManager.UnsafeSignEOTS(uid, chainID, msg, height)
+}
+
+func (m *localEOTSManager) SignSchnorrSig(uid []byte, msg []byte) (*schnorr.Signature, error) {
+	if err := m.unlock(uid); err != nil {
+		return nil, err
+	}
+
+	return m.EOTSManager.SignSchnorrSig(uid, msg)
+}
+
+func (m *localEOTSManager) Close() error {
+	if err := m.EOTSManager.Close(); err != nil {
+		m.logger.Error("close the local eotsmanager failed", zap.Error(err))
+	}
+
+	return m.db.Close()
+}
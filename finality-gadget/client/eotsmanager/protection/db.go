@@ -0,0 +1,153 @@
+package protection
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/pkg/errors"
+)
+
+const defaultDBTimeout = 10 * time.Second
+
+var (
+	// the signed msg hashes, by uid -> chain id -> height.
+	signedBucketName = []byte("eots-signed")
+
+	// ErrDoubleSign is returned when signing a different message at a height already signed.
+	ErrDoubleSign = errors.New("refuse to sign a different message at the signed height")
+)
+
+// DB is the slashing protection db, it records the msg hash of every EOTS signature,
+// to make sure we never sign two different messages at the same height.
+type DB struct {
+	db kvdb.Backend
+}
+
+// Open opens or creates the protection db by the file path.
+func Open(path string) (*DB, error) {
+	db, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
+		DBPath:     filepath.Dir(path),
+		DBFileName: filepath.Base(path),
+		DBTimeout:  defaultDBTimeout,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the protection db %s", path)
+	}
+
+	err = kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(signedBucketName)
+		return err
+	}, func() {})
+	if err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "failed to create the protection db bucket")
+	}
+
+	return &DB{db: db}, nil
+}
+
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// CheckAndRecord records the msg hash for (uid, chainID, height) before signing,
+// returns ErrDoubleSign if a different msg was signed at the height.
+// Signing the same msg again is allowed, so the retries will not be refused.
+func (d *DB) CheckAndRecord(uid, chainID []byte, height uint64, msg []byte) error {
+	msgHash := sha256.Sum256(msg)
+
+	return kvdb.Update(d.db, func(tx kvdb.RwTx) error {
+		bucket, err := heightsBucket(tx, uid, chainID)
+		if err != nil {
+			return err
+		}
+
+		return checkAndPut(bucket, height, msgHash[:])
+	}, func() {})
+}
+
+// SignedMsgHash returns the msg hash signed at the height, nil if not signed.
+func (d *DB) SignedMsgHash(uid, chainID []byte, height uint64) ([]byte, error) {
+	var res []byte
+
+	err := kvdb.View(d.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(signedBucketName)
+		if bucket == nil {
+			return nil
+		}
+
+		if bucket = bucket.NestedReadBucket(uid); bucket == nil {
+			return nil
+		}
+
+		if bucket = bucket.NestedReadBucket(chainID); bucket == nil {
+			return nil
+		}
+
+		if v := bucket.Get(heightKey(height)); v != nil {
+			res = append([]byte{}, v...)
+		}
+
+		return nil
+	}, func() { res = nil })
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func heightsBucket(tx kvdb.RwTx, uid, chainID []byte) (kvdb.RwBucket, error) {
+	bucket := tx.ReadWriteBucket(signedBucketName)
+	if bucket == nil {
+		return nil, errors.New("no protection db bucket")
+	}
+
+	bucket, err := bucket.CreateBucketIfNotExists(uid)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the uid bucket")
+	}
+
+	bucket, err = bucket.CreateBucketIfNotExists(chainID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the chain id bucket")
+	}
+
+	return bucket, nil
+}
+
+func checkAndPut(bucket kvdb.RwBucket, height uint64, msgHash []byte) error {
+	key := heightKey(height)
+
+	if signed := bucket.Get(key); signed != nil {
+		if bytes.Equal(signed, msgHash) {
+			return nil
+		}
+
+		return errors.Wrapf(ErrDoubleSign,
+			"height %d signed %s, requested %s",
+			height, hex.EncodeToString(signed), hex.EncodeToString(msgHash))
+	}
+
+	return bucket.Put(key, msgHash)
+}
+
+func heightKey(height uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, height)
+	return key
+}
+
+func parseHeightKey(key []byte) (uint64, error) {
+	if len(key) != 8 {
+		return 0, fmt.Errorf("invalid height key %x", key)
+	}
+
+	return binary.BigEndian.Uint64(key), nil
+}
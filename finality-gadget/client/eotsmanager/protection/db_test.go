@@ -0,0 +1,176 @@
+package protection
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var (
+	testUid     = bytes.Repeat([]byte{0x01}, 32)
+	testChainID = []byte("blitz-chain")
+)
+
+func openTestDB(t *testing.T) *DB {
+	db, err := Open(filepath.Join(t.TempDir(), "protection.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestCheckAndRecord(t *testing.T) {
+	db := openTestDB(t)
+
+	if err := db.CheckAndRecord(testUid, testChainID, 100, []byte("block 100 a")); err != nil {
+		t.Fatalf("first sign: %v", err)
+	}
+
+	// the retry of the same msg is allowed
+	if err := db.CheckAndRecord(testUid, testChainID, 100, []byte("block 100 a")); err != nil {
+		t.Fatalf("re-sign the same msg: %v", err)
+	}
+
+	// a different msg at the same height is refused
+	err := db.CheckAndRecord(testUid, testChainID, 100, []byte("block 100 b"))
+	if !errors.Is(err, ErrDoubleSign) {
+		t.Fatalf("got %v, want ErrDoubleSign", err)
+	}
+
+	// the other heights, chains and uids are independent
+	if err := db.CheckAndRecord(testUid, testChainID, 101, []byte("block 100 b")); err != nil {
+		t.Fatalf("sign other height: %v", err)
+	}
+	if err := db.CheckAndRecord(testUid, []byte("other-chain"), 100, []byte("block 100 b")); err != nil {
+		t.Fatalf("sign other chain: %v", err)
+	}
+
+	msgHash := sha256.Sum256([]byte("block 100 a"))
+	signed, err := db.SignedMsgHash(testUid, testChainID, 100)
+	if err != nil {
+		t.Fatalf("signed msg hash: %v", err)
+	}
+	if !bytes.Equal(signed, msgHash[:]) {
+		t.Fatalf("got signed %x, want %x", signed, msgHash)
+	}
+
+	if signed, err := db.SignedMsgHash(testUid, testChainID, 102); err != nil || signed != nil {
+		t.Fatalf("got signed %x, %v at the unsigned height", signed, err)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	src := openTestDB(t)
+	for height := uint64(1); height <= 3; height++ {
+		if err := src.CheckAndRecord(testUid, testChainID, height, []byte{byte(height)}); err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := src.Export(&buf); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	exported := buf.String()
+
+	dst := openTestDB(t)
+	if err := dst.CheckAndRecord(testUid, testChainID, 2, []byte{2}); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	n, err := dst.Import(strings.NewReader(exported))
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("imported %d records, want 3", n)
+	}
+
+	// the imported history protects the new host
+	if err := dst.CheckAndRecord(testUid, testChainID, 3, []byte{4}); !errors.Is(err, ErrDoubleSign) {
+		t.Fatalf("got %v, want ErrDoubleSign", err)
+	}
+
+	buf.Reset()
+	if err := dst.Export(&buf); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if buf.String() != exported {
+		t.Fatalf("round trip got %s, want %s", buf.String(), exported)
+	}
+}
+
+func TestImportConflict(t *testing.T) {
+	dst := openTestDB(t)
+	if err := dst.CheckAndRecord(testUid, testChainID, 2, []byte{2}); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	msgHash := sha256.Sum256([]byte{3})
+	input := interchangeJson(
+		record(1, hex.EncodeToString(msgHash[:])),
+		record(2, hex.EncodeToString(msgHash[:])),
+	)
+
+	if _, err := dst.Import(strings.NewReader(input)); !errors.Is(err, ErrDoubleSign) {
+		t.Fatalf("got %v, want ErrDoubleSign", err)
+	}
+
+	// nothing is imported
+	if signed, err := dst.SignedMsgHash(testUid, testChainID, 1); err != nil || signed != nil {
+		t.Fatalf("got signed %x, %v after the failed import", signed, err)
+	}
+}
+
+func TestImportInvalidRecord(t *testing.T) {
+	msgHash := sha256.Sum256([]byte{1})
+	valid := hex.EncodeToString(msgHash[:])
+
+	tests := []struct {
+		name   string
+		record SignRecord
+	}{
+		{name: "short msg hash", record: SignRecord{Uid: hex.EncodeToString(testUid), ChainId: "01", Height: 1, MsgHash: valid[:62]}},
+		{name: "long msg hash", record: SignRecord{Uid: hex.EncodeToString(testUid), ChainId: "01", Height: 1, MsgHash: valid + "00"}},
+		{name: "empty msg hash", record: SignRecord{Uid: hex.EncodeToString(testUid), ChainId: "01", Height: 1}},
+		{name: "invalid hex", record: SignRecord{Uid: hex.EncodeToString(testUid), ChainId: "01", Height: 1, MsgHash: "zz"}},
+		{name: "zero height", record: SignRecord{Uid: hex.EncodeToString(testUid), ChainId: "01", MsgHash: valid}},
+		{name: "no chain id", record: SignRecord{Uid: hex.EncodeToString(testUid), Height: 1, MsgHash: valid}},
+		{name: "no uid", record: SignRecord{ChainId: "01", Height: 1, MsgHash: valid}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+
+			if _, err := db.Import(strings.NewReader(interchangeJson(tt.record))); err == nil {
+				t.Fatal("the invalid record should be rejected")
+			}
+		})
+	}
+}
+
+func record(height uint64, msgHash string) SignRecord {
+	return SignRecord{
+		Uid:     hex.EncodeToString(testUid),
+		ChainId: hex.EncodeToString(testChainID),
+		Height:  height,
+		MsgHash: msgHash,
+	}
+}
+
+func interchangeJson(records ...SignRecord) string {
+	data, err := json.Marshal(&Interchange{Version: interchangeVersion, Records: records})
+	if err != nil {
+		panic(err)
+	}
+
+	return string(data)
+}
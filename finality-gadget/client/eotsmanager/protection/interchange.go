@@ -0,0 +1,151 @@
+package protection
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/pkg/errors"
+)
+
+const interchangeVersion = 1
+
+// Interchange is the json format to export and import the signing history,
+// used to migrate the operator to a new host.
+type Interchange struct {
+	Version uint32       `json:"version"`
+	Records []SignRecord `json:"records"`
+}
+
+// SignRecord is a signed msg hash, the bytes are encoded in hex.
+type SignRecord struct {
+	Uid     string `json:"uid"`
+	ChainId string `json:"chain_id"`
+	Height  uint64 `json:"height"`
+	MsgHash string `json:"msg_hash"`
+}
+
+// Export writes all the signing history into w.
+func (d *DB) Export(w io.Writer) error {
+	res := Interchange{
+		Version: interchangeVersion,
+		Records: make([]SignRecord, 0, 1024),
+	}
+
+	err := kvdb.View(d.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(signedBucketName)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(uid, _ []byte) error {
+			uidBucket := bucket.NestedReadBucket(uid)
+			if uidBucket == nil {
+				return nil
+			}
+
+			return uidBucket.ForEach(func(chainID, _ []byte) error {
+				chainBucket := uidBucket.NestedReadBucket(chainID)
+				if chainBucket == nil {
+					return nil
+				}
+
+				return chainBucket.ForEach(func(k, v []byte) error {
+					height, err := parseHeightKey(k)
+					if err != nil {
+						return err
+					}
+
+					res.Records = append(res.Records, SignRecord{
+						Uid:     hex.EncodeToString(uid),
+						ChainId: hex.EncodeToString(chainID),
+						Height:  height,
+						MsgHash: hex.EncodeToString(v),
+					})
+					return nil
+				})
+			})
+		})
+	}, func() { res.Records = res.Records[:0] })
+	if err != nil {
+		return errors.Wrap(err, "failed to read the signing history")
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return errors.Wrap(encoder.Encode(&res), "failed to encode the signing history")
+}
+
+// Import merges the signing history from r, all the records are imported in one tx,
+// nothing will be imported if any record conflicts with the local history.
+func (d *DB) Import(r io.Reader) (int, error) {
+	var data Interchange
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return 0, errors.Wrap(err, "failed to decode the signing history")
+	}
+
+	if data.Version != interchangeVersion {
+		return 0, fmt.Errorf("unsupported signing history version %d", data.Version)
+	}
+
+	err := kvdb.Update(d.db, func(tx kvdb.RwTx) error {
+		for i, record := range data.Records {
+			uid, chainID, msgHash, err := record.decode()
+			if err != nil {
+				return errors.Wrapf(err, "invalid record %d", i)
+			}
+
+			bucket, err := heightsBucket(tx, uid, chainID)
+			if err != nil {
+				return err
+			}
+
+			if err := checkAndPut(bucket, record.Height, msgHash); err != nil {
+				return errors.Wrapf(err, "failed to import record %d", i)
+			}
+		}
+
+		return nil
+	}, func() {})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(data.Records), nil
+}
+
+// decode decodes and checks the record, a corrupt record should not be imported
+// as it would weaken the double sign check.
+func (r *SignRecord) decode() (uid, chainID, msgHash []byte, err error) {
+	if uid, err = hex.DecodeString(r.Uid); err != nil {
+		return nil, nil, nil, errors.Wrap(err, "invalid uid")
+	}
+	if len(uid) == 0 {
+		return nil, nil, nil, errors.New("no uid")
+	}
+
+	if chainID, err = hex.DecodeString(r.ChainId); err != nil {
+		return nil, nil, nil, errors.Wrap(err, "invalid chain id")
+	}
+	if len(chainID) == 0 {
+		return nil, nil, nil, errors.New("no chain id")
+	}
+
+	// no block is signed at the genesis
+	if r.Height == 0 {
+		return nil, nil, nil, errors.New("invalid height 0")
+	}
+
+	if msgHash, err = hex.DecodeString(r.MsgHash); err != nil {
+		return nil, nil, nil, errors.Wrap(err, "invalid msg hash")
+	}
+	if len(msgHash) != sha256.Size {
+		return nil, nil, nil, fmt.Errorf("invalid msg hash length %d, should be %d", len(msgHash), sha256.Size)
+	}
+
+	return uid, chainID, msgHash, nil
+}
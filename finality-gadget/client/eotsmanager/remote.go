@@ -0,0 +1,467 @@
+package eotsmanager
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	fpeotsmanager "github.com/babylonlabs-io/finality-provider/eotsmanager"
+	"github.com/babylonlabs-io/finality-provider/eotsmanager/client"
+	"github.com/babylonlabs-io/finality-provider/eotsmanager/proto"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/alt-research/blitz/finality-gadget/metrics"
+)
+
+var (
+	// ErrUnavailable is returned when no eotsmanager can be reached after the retries.
+	ErrUnavailable = errors.New("eotsmanager unavailable")
+	// ErrRefusedToSign is returned when the eotsmanager is reachable but refused the signing request.
+	ErrRefusedToSign = errors.New("eotsmanager refused to sign")
+	// ErrRejected is returned when the eotsmanager is reachable but rejected the other request, like unlock and backup.
+	ErrRejected = errors.New("eotsmanager rejected the request")
+)
+
+// signMethods are the requests which sign by the key, the others are the key management requests.
+var signMethods = map[string]struct{}{
+	"CreateRandomnessPairList": {},
+	"SignEOTS":                 {},
+	"UnsafeSignEOTS":           {},
+	"SignSchnorrSig":           {},
+}
+
+var _ fpeotsmanager.EOTSManager = &remoteEOTSManager{}
+
+type remoteEndpoint struct {
+	addr    string
+	conn    *grpc.ClientConn
+	client  proto.EOTSManagerClient
+	healthy atomic.Bool
+}
+
+// remoteEOTSManager calls the eotsmanagers by gRPC, it retries on the transient errors
+// and fails over to the next endpoint if the current one is unavailable.
+type remoteEOTSManager struct {
+	logger  *zap.Logger
+	cfg     Config
+	metrics *metrics.EotsMetrics
+
+	endpoints []*remoteEndpoint
+	// the index of the endpoint succeeded last time, will be tried first
+	preferred atomic.Int32
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newRemoteEOTSManager(logger *zap.Logger, cfg Config) (*remoteEOTSManager, error) {
+	cfg.withDefault()
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid eotsmanager config")
+	}
+
+	opts, err := cfg.dialOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &remoteEOTSManager{
+		logger:  logger.With(zap.String("module", "eotsmanager")),
+		cfg:     cfg,
+		metrics: metrics.NewEotsMetrics(),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	for _, addr := range cfg.Endpoints() {
+		conn, err := grpc.NewClient(addr, opts...)
+		if err != nil {
+			m.closeConns()
+			cancel()
+			return nil, errors.Wrapf(err, "create eotsmanager client failed: %v", addr)
+		}
+
+		m.endpoints = append(m.endpoints, &remoteEndpoint{
+			addr:   addr,
+			conn:   conn,
+			client: proto.NewEOTSManagerClient(conn),
+		})
+	}
+
+	// the unreachable eotsmanager is reported by the metrics and readiness,
+	// so the operator can start before the eotsmanager.
+	m.checkHealth()
+	metrics.RegisterReadinessCheck("eotsmanager", m.Ready)
+
+	m.wg.Add(1)
+	go m.healthCheckLoop()
+
+	return m, nil
+}
+
+func (c *Config) dialOptions() ([]grpc.DialOption, error) {
+	opts := make([]grpc.DialOption, 0, 4)
+
+	if c.TLS.Enabled {
+		tlsCfg, err := c.TLS.load()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	if c.Token != "" || c.TokenFile != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(&tokenCredentials{
+			token:     c.Token,
+			tokenFile: c.TokenFile,
+		}))
+	}
+
+	if c.HMACKey != "" {
+		opts = append(opts, grpc.WithUnaryInterceptor(client.HMACUnaryClientInterceptor(c.HMACKey)))
+	}
+
+	return opts, nil
+}
+
+func (c *TLSConfig) load() (*tls.Config, error) {
+	res := &tls.Config{
+		ServerName: c.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if c.CAFile != "" {
+		ca, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the ca file %s", c.CAFile)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.Errorf("no certificate found in the ca file %s", c.CAFile)
+		}
+		res.RootCAs = pool
+	}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load the client cert")
+		}
+		res.Certificates = []tls.Certificate{cert}
+	}
+
+	return res, nil
+}
+
+// tokenCredentials sends the bearer token by the `authorization` metadata.
+type tokenCredentials struct {
+	token     string
+	tokenFile string
+}
+
+func (t *tokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	token := t.token
+	if t.tokenFile != "" {
+		data, err := os.ReadFile(t.tokenFile)
+		if err != nil {
+			// the token file may be rotating, it is not refused by the eotsmanager, so retry it
+			return nil, status.Errorf(codes.Unavailable, "failed to read the token file %s: %v", t.tokenFile, err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+// RequireTransportSecurity is true, so the token will never be sent in plaintext.
+func (t *tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+// Ready returns an error if all the eotsmanagers are unavailable.
+func (m *remoteEOTSManager) Ready() error {
+	for _, ep := range m.endpoints {
+		if ep.healthy.Load() {
+			return nil
+		}
+	}
+
+	return ErrUnavailable
+}
+
+func (m *remoteEOTSManager) healthCheckLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.checkHealth()
+		}
+	}
+}
+
+func (m *remoteEOTSManager) checkHealth() {
+	available := false
+
+	for i, ep := range m.endpoints {
+		ctx, cancel := context.WithTimeout(m.ctx, m.cfg.RequestTimeout)
+		_, err := ep.client.Ping(ctx, &proto.PingRequest{})
+		cancel()
+
+		m.setHealthy(ep, err == nil, err)
+		if err == nil {
+			available = true
+			if !m.endpoints[m.preferred.Load()].healthy.Load() {
+				m.preferred.Store(int32(i))
+			}
+		}
+	}
+
+	m.metrics.RecordAvailable(available)
+}
+
+func (m *remoteEOTSManager) setHealthy(ep *remoteEndpoint, healthy bool, err error) {
+	if ep.healthy.Swap(healthy) != healthy {
+		if healthy {
+			m.logger.Info("eotsmanager is available", zap.String("endpoint", ep.addr))
+		} else {
+			m.logger.Error("eotsmanager is unavailable", zap.String("endpoint", ep.addr), zap.Error(err))
+		}
+	}
+
+	m.metrics.RecordEndpointUp(ep.addr, healthy)
+}
+
+// call calls f with the endpoints from the preferred one, retries on the transient errors.
+func (m *remoteEOTSManager) call(method string, f func(ctx context.Context, c proto.EOTSManagerClient) error) error {
+	var lastErr error
+
+	for retry := uint64(0); retry <= m.cfg.MaxRetries; retry++ {
+		if retry > 0 {
+			select {
+			case <-m.ctx.Done():
+				return errors.Wrapf(ErrUnavailable, "%s: client closed", method)
+			case <-time.After(m.cfg.RetryInterval):
+			}
+		}
+
+		preferred := int(m.preferred.Load())
+		for i := range m.endpoints {
+			idx := (preferred + i) % len(m.endpoints)
+			ep := m.endpoints[idx]
+
+			ctx, cancel := context.WithTimeout(m.ctx, m.cfg.RequestTimeout)
+			err := f(ctx, ep.client)
+			cancel()
+
+			if err == nil {
+				m.preferred.Store(int32(idx))
+				m.setHealthy(ep, true, nil)
+				m.metrics.RecordRequest(method, metrics.EotsResultOk)
+				return nil
+			}
+
+			if isRefusedErr(err) {
+				m.metrics.RecordRequest(method, metrics.EotsResultRefused)
+				refused := ErrRejected
+				if _, ok := signMethods[method]; ok {
+					refused = ErrRefusedToSign
+				}
+				return errors.Wrapf(refused, "%s by %s: %v", method, ep.addr, err)
+			}
+
+			m.logger.Warn("eotsmanager request failed, try next",
+				zap.String("method", method), zap.String("endpoint", ep.addr), zap.Error(err))
+			m.setHealthy(ep, false, err)
+			lastErr = err
+		}
+	}
+
+	m.metrics.RecordRequest(method, metrics.EotsResultUnavailable)
+	return errors.Wrapf(ErrUnavailable, "%s failed after %d retries: %v", method, m.cfg.MaxRetries, lastErr)
+}
+
+// isRefusedErr returns true if the error is a gRPC status returned by the eotsmanager which should not be retried,
+// the errors not from the eotsmanager, like the dial and the ctx errors, are retried.
+func isRefusedErr(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Canceled:
+		return false
+	default:
+		return true
+	}
+}
+
+func (m *remoteEOTSManager) CreateRandomnessPairList(uid []byte, chainID []byte, startHeight uint64, num uint32) ([]*btcec.FieldVal, error) {
+	var res *proto.CreateRandomnessPairListResponse
+
+	err := m.call("CreateRandomnessPairList", func(ctx context.Context, c proto.EOTSManagerClient) (err error) {
+		res, err = c.CreateRandomnessPairList(ctx, &proto.CreateRandomnessPairListRequest{
+			Uid:         uid,
+			ChainId:     chainID,
+			StartHeight: startHeight,
+			Num:         num,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pubRandFieldValList := make([]*btcec.FieldVal, 0, len(res.PubRandList))
+	for _, r := range res.PubRandList {
+		var fieldVal btcec.FieldVal
+		fieldVal.SetByteSlice(r)
+		pubRandFieldValList = append(pubRandFieldValList, &fieldVal)
+	}
+
+	return pubRandFieldValList, nil
+}
+
+// CreateRandomnessPairListWithInterval creates the randomness at the heights startHeight + i*interval,
+// the randomness is deterministic by the height, so it is the same as creating one by one.
+func (m *remoteEOTSManager) CreateRandomnessPairListWithInterval(uid []byte, chainID []byte, startHeight uint64, num uint32, interval uint64) ([]*btcec.FieldVal, error) {
+	if interval <= 1 {
+		return m.CreateRandomnessPairList(uid, chainID, startHeight, num)
+	}
+
+	res := make([]*btcec.FieldVal, 0, num)
+	for i := uint64(0); i < uint64(num); i++ {
+		pubRand, err := m.CreateRandomnessPairList(uid, chainID, startHeight+i*interval, 1)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, pubRand...)
+	}
+
+	return res, nil
+}
+
+func (m *remoteEOTSManager) SignEOTS(uid []byte, chainID []byte, msg []byte, height uint64) (*btcec.ModNScalar, error) {
+	return m.signEOTS("SignEOTS", uid, chainID, msg, height, proto.EOTSManagerClient.SignEOTS)
+}
+
+func (m *remoteEOTSManager) UnsafeSignEOTS(uid []byte, chainID []byte, msg []byte, height uint64) (*btcec.ModNScalar, error) {
+	return m.signEOTS("UnsafeSignEOTS", uid, chainID, msg, height, proto.EOTSManagerClient.UnsafeSignEOTS)
+}
+
+func (m *remoteEOTSManager) signEOTS(
+	method string,
+	uid []byte, chainID []byte, msg []byte, height uint64,
+	sign func(c proto.EOTSManagerClient, ctx context.Context, req *proto.SignEOTSRequest, opts ...grpc.CallOption) (*proto.SignEOTSResponse, error),
+) (*btcec.ModNScalar, error) {
+	var res *proto.SignEOTSResponse
+
+	err := m.call(method, func(ctx context.Context, c proto.EOTSManagerClient) (err error) {
+		res, err = sign(c, ctx, &proto.SignEOTSRequest{
+			Uid:     uid,
+			ChainId: chainID,
+			Msg:     msg,
+			Height:  height,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var s btcec.ModNScalar
+	s.SetByteSlice(res.Sig)
+
+	return &s, nil
+}
+
+func (m *remoteEOTSManager) SignSchnorrSig(uid []byte, msg []byte) (*schnorr.Signature, error) {
+	var res *proto.SignSchnorrSigResponse
+
+	err := m.call("SignSchnorrSig", func(ctx context.Context, c proto.EOTSManagerClient) (err error) {
+		res, err = c.SignSchnorrSig(ctx, &proto.SignSchnorrSigRequest{
+			Uid: uid,
+			Msg: msg,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := schnorr.ParseSignature(res.Sig)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid schnorr signature from eotsmanager")
+	}
+
+	return sig, nil
+}
+
+func (m *remoteEOTSManager) Unlock(uid []byte, passphrase string) error {
+	return m.call("Unlock", func(ctx context.Context, c proto.EOTSManagerClient) error {
+		_, err := c.UnlockKey(ctx, &proto.UnlockKeyRequest{
+			Uid:        uid,
+			Passphrase: passphrase,
+		})
+		return err
+	})
+}
+
+func (m *remoteEOTSManager) Backup(dbPath string, backupDir string) (string, error) {
+	var res *proto.BackupResponse
+
+	err := m.call("Backup", func(ctx context.Context, c proto.EOTSManagerClient) (err error) {
+		res, err = c.Backup(ctx, &proto.BackupRequest{
+			DbPath:    dbPath,
+			BackupDir: backupDir,
+		})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return res.BackupName, nil
+}
+
+func (m *remoteEOTSManager) Close() error {
+	m.cancel()
+	m.wg.Wait()
+
+	return m.closeConns()
+}
+
+func (m *remoteEOTSManager) closeConns() error {
+	var res error
+	for _, ep := range m.endpoints {
+		if err := ep.conn.Close(); err != nil {
+			res = errors.Wrapf(err, "close eotsmanager connection %s failed", ep.addr)
+		}
+	}
+
+	return res
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/pkg/errors"
+
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
 )
 
 type L2EthClient struct {
@@ -42,6 +44,18 @@ func (c *Config) WithEnv() {
 	}
 }
 
+func (c *Config) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.EthRpcUrl == "" {
+		errs.Add("eth_rpc_url", errors.New("required"))
+	} else {
+		errs.Add("eth_rpc_url", utils.CheckURL(c.EthRpcUrl, "http", "https", "ws", "wss"))
+	}
+
+	return errs.Err()
+}
+
 func NewL2EthClient(ctx context.Context, cfg *Config) (*L2EthClient, error) {
 	// Create L2 client
 	cli, err := ethclient.Dial(cfg.EthRpcUrl)
@@ -1,15 +1,21 @@
 package configs
 
-import "os"
+import (
+	"os"
+
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
+)
 
 type CommonConfig struct {
 	// The service name
 	Name string `yaml:"name"`
 	// used to set the logger level (true = info, false = debug)
-	Production             bool     `yaml:"production"`
+	Production             bool     `yaml:"production" reload:"hot"`
 	RpcServerIpPortAddress string   `yaml:"rpc_server_ip_port_address"`
-	RpcVhosts              []string `yaml:"rpc_vhosts"`
-	RpcCors                []string `yaml:"rpc_cors"`
+	RpcVhosts              []string `yaml:"rpc_vhosts" reload:"hot"`
+	RpcCors                []string `yaml:"rpc_cors" reload:"hot"`
+	// The max entries of each rpc query cache, default is 4096.
+	RpcCacheSize int `yaml:"rpc_cache_size,omitempty" reload:"hot"`
 }
 
 // use the env config first for some keys
@@ -24,3 +30,17 @@ func (c *CommonConfig) WithEnv() {
 		c.Name = name
 	}
 }
+
+func (c *CommonConfig) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.RpcServerIpPortAddress != "" {
+		errs.Add("rpc_server_ip_port_address", utils.CheckHostPort(c.RpcServerIpPortAddress))
+	}
+
+	if c.RpcCacheSize < 0 {
+		errs.Addf("rpc_cache_size", "should not be negative")
+	}
+
+	return errs.Err()
+}
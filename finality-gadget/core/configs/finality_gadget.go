@@ -1,16 +1,20 @@
 package configs
 
 import (
+	"github.com/pkg/errors"
+
 	"github.com/alt-research/blitz/finality-gadget/core/utils"
 )
 
+const babylonAddressPrefix = "bbn"
+
 type Config struct {
 	BBNChainID        string `yaml:"bbnchainid" description:"BabylonChain chain ID"`
 	BBNRPCAddress     string `yaml:"bbnrpcaddress" description:"BabylonChain chain RPC address"`
 	DBFilePath        string `yaml:"dbfilepath" description:"path to the DB file"`
 	BitcoinRPCHost    string `yaml:"bitcoinrpchost" description:"rpc host address of the bitcoin node"`
 	BitcoinRPCUser    string `yaml:"bitcoinrpcuser" description:"rpc user of the bitcoin node"`
-	BitcoinRPCPass    string `yaml:"bitcoinrpcpass" description:"rpc password of the bitcoin node"`
+	BitcoinRPCPass    string `yaml:"bitcoinrpcpass" description:"rpc password of the bitcoin node" secret:"true"`
 	BitcoinDisableTLS bool   `yaml:"bitcoindisabletls" description:"disable TLS for RPC connections"`
 	FGContractAddress string `yaml:"fgcontractaddress" description:"BabylonChain op finality gadget contract address"`
 }
@@ -24,3 +28,53 @@ func (c *BabylonConfig) WithEnv() {
 	c.FinalityGadgetCfg.BBNChainID = utils.LookupEnvStr("FINALITY_GADGET_BABYLON_CHAINID", c.FinalityGadgetCfg.BBNChainID)
 	c.FinalityGadgetCfg.BBNRPCAddress = utils.LookupEnvStr("FINALITY_GADGET_BABYLON_RPC_ADDR", c.FinalityGadgetCfg.BBNRPCAddress)
 }
+
+func (c *Config) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.BBNRPCAddress != "" {
+		errs.Add("bbnrpcaddress", utils.CheckURL(c.BBNRPCAddress, "http", "https", "tcp"))
+	}
+
+	if c.FGContractAddress != "" {
+		errs.Add("fgcontractaddress", utils.CheckBech32Address(c.FGContractAddress, babylonAddressPrefix))
+	}
+
+	if c.BitcoinRPCPass != "" && c.BitcoinRPCUser == "" {
+		errs.Add("bitcoinrpcuser", errors.New("the bitcoin rpc user is required with the password"))
+	}
+
+	return errs.Err()
+}
+
+// ValidateForQuery checks the fields required to query the finalized state from babylon.
+func (c *Config) ValidateForQuery() error {
+	var errs utils.ConfigErrors
+
+	if c.BBNChainID == "" {
+		errs.Add("bbnchainid", errors.New("required"))
+	}
+
+	if c.BBNRPCAddress == "" {
+		errs.Add("bbnrpcaddress", errors.New("required"))
+	}
+
+	if c.BitcoinRPCHost == "" {
+		errs.Add("bitcoinrpchost", errors.New("required"))
+	}
+
+	if c.FGContractAddress == "" {
+		errs.Add("fgcontractaddress", errors.New("required"))
+	}
+
+	errs.Merge("", c.Validate())
+
+	return errs.Err()
+}
+
+func (c *BabylonConfig) Validate() error {
+	var errs utils.ConfigErrors
+	errs.Merge("finality_gadget", c.FinalityGadgetCfg.Validate())
+
+	return errs.Err()
+}
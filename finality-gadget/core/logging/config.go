@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
+)
+
+// The formats of the logs.
+const (
+	FormatConsole = "console"
+	FormatJson    = "json"
+)
+
+// The outputs not as the file paths.
+const (
+	OutputStderr = "stderr"
+	OutputStdout = "stdout"
+)
+
+type Config struct {
+	// The global level: `debug`, `info`, `warn` or `error`,
+	// default is `info` in production and `debug` if not.
+	Level string `yaml:"level,omitempty" reload:"hot"`
+	// The format: `console` or `json`, default is `console`.
+	Format string `yaml:"format,omitempty"`
+	// The outputs: `stderr`, `stdout` or the file paths, default is `stderr`.
+	Outputs []string `yaml:"outputs,omitempty"`
+	// The rotation of the file outputs.
+	Rotation RotationConfig `yaml:"rotation,omitempty"`
+	// The sampling of the logs with the same level and message.
+	Sampling SamplingConfig `yaml:"sampling,omitempty"`
+	// The levels by the `module` field of the loggers, like `l2BlockHandler: warn`,
+	// the global level is used for the modules not set.
+	Modules map[string]string `yaml:"modules,omitempty" reload:"hot"`
+}
+
+type RotationConfig struct {
+	// The max size in megabytes before rotated, default is 100.
+	MaxSizeMB int `yaml:"max_size_mb,omitempty"`
+	// The max days to keep the rotated files, default is not removed by age.
+	MaxAgeDays int `yaml:"max_age_days,omitempty"`
+	// The max number of the rotated files to keep, default is all kept.
+	MaxBackups int `yaml:"max_backups,omitempty"`
+	// Compress the rotated files by gzip.
+	Compress bool `yaml:"compress,omitempty"`
+}
+
+// SamplingConfig logs the first `initial` entries with the same level and message in each tick,
+// and then every `thereafter`th entry, the sampling is disabled if `thereafter` is 0.
+type SamplingConfig struct {
+	Initial    int           `yaml:"initial,omitempty"`
+	Thereafter int           `yaml:"thereafter,omitempty"`
+	Tick       time.Duration `yaml:"tick,omitempty"`
+}
+
+// Enabled returns true if the sampling is set.
+func (c *SamplingConfig) Enabled() bool {
+	return c.Thereafter > 0
+}
+
+func (c *Config) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.Level != "" {
+		if _, err := zapcore.ParseLevel(c.Level); err != nil {
+			errs.Add("level", err)
+		}
+	}
+
+	switch c.Format {
+	case "", FormatConsole, FormatJson:
+	default:
+		errs.Addf("format", "unknown format %s", c.Format)
+	}
+
+	for _, output := range c.Outputs {
+		if output == "" {
+			errs.Addf("outputs", "empty output")
+		}
+	}
+
+	if c.Rotation.MaxSizeMB < 0 {
+		errs.Addf("rotation.max_size_mb", "should not be negative")
+	}
+	if c.Rotation.MaxAgeDays < 0 {
+		errs.Addf("rotation.max_age_days", "should not be negative")
+	}
+	if c.Rotation.MaxBackups < 0 {
+		errs.Addf("rotation.max_backups", "should not be negative")
+	}
+
+	if c.Sampling.Initial < 0 || c.Sampling.Thereafter < 0 || c.Sampling.Tick < 0 {
+		errs.Addf("sampling", "should not be negative")
+	}
+
+	for module, level := range c.Modules {
+		if _, err := zapcore.ParseLevel(level); err != nil {
+			errs.Addf("modules", "invalid level of %s: %v", module, err)
+		}
+	}
+
+	return errs.Err()
+}
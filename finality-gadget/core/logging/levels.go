@@ -0,0 +1,163 @@
+package logging
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+// moduleField is the field set by the loggers of the modules, like `logger.With("module", "l2BlockHandler")`.
+const moduleField = "module"
+
+// moduleLevels is the levels by the module, the global logLevel is used for the modules not in it.
+var (
+	moduleLevels   atomic.Pointer[map[string]zapcore.Level]
+	moduleLevelsMu sync.Mutex
+)
+
+// Levels is the global and the module levels of the loggers.
+type Levels struct {
+	Level   string            `json:"level"`
+	Modules map[string]string `json:"modules,omitempty"`
+}
+
+// CurrentLevels returns the levels in use.
+func CurrentLevels() Levels {
+	res := Levels{
+		Level: logLevel.Level().String(),
+	}
+
+	if levels := moduleLevels.Load(); levels != nil && len(*levels) > 0 {
+		res.Modules = make(map[string]string, len(*levels))
+		for module, level := range *levels {
+			res.Modules[module] = level.String()
+		}
+	}
+
+	return res
+}
+
+// SetLevel changes the level of the module at runtime, the global level is changed if the module is empty,
+// and the level of the module is removed to use the global one if the level is empty.
+func SetLevel(module, level string) error {
+	if module == "" {
+		if level == "" {
+			return errors.New("the global level is required")
+		}
+
+		l, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return errors.Wrap(err, "invalid level")
+		}
+
+		logLevel.SetLevel(l)
+		return nil
+	}
+
+	moduleLevelsMu.Lock()
+	defer moduleLevelsMu.Unlock()
+
+	levels := make(map[string]zapcore.Level)
+	if old := moduleLevels.Load(); old != nil {
+		maps.Copy(levels, *old)
+	}
+
+	if level == "" {
+		delete(levels, module)
+	} else {
+		l, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return errors.Wrapf(err, "invalid level of %s", module)
+		}
+		levels[module] = l
+	}
+
+	moduleLevels.Store(&levels)
+	return nil
+}
+
+// ApplyLevels sets the global and the module levels by the config, the levels changed by SetLevel are reset.
+func ApplyLevels(cfg *Config, isProduction bool) error {
+	global := zapcore.InfoLevel
+	if !isProduction {
+		global = zapcore.DebugLevel
+	}
+	if cfg.Level != "" {
+		l, err := zapcore.ParseLevel(cfg.Level)
+		if err != nil {
+			return errors.Wrap(err, "invalid level")
+		}
+		global = l
+	}
+
+	levels := make(map[string]zapcore.Level, len(cfg.Modules))
+	for module, level := range cfg.Modules {
+		l, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return errors.Wrapf(err, "invalid level of %s", module)
+		}
+		levels[module] = l
+	}
+
+	moduleLevelsMu.Lock()
+	defer moduleLevelsMu.Unlock()
+
+	logLevel.SetLevel(global)
+	moduleLevels.Store(&levels)
+
+	return nil
+}
+
+// levelOf returns the level of the module, or the global level if not set.
+func levelOf(module string) zapcore.Level {
+	if module != "" {
+		if levels := moduleLevels.Load(); levels != nil {
+			if l, ok := (*levels)[module]; ok {
+				return l
+			}
+		}
+	}
+
+	return logLevel.Level()
+}
+
+// moduleCore filters the entries by the level of the module in the fields,
+// the inner core should enable all the levels.
+type moduleCore struct {
+	zapcore.Core
+	module string
+}
+
+func (c *moduleCore) Enabled(level zapcore.Level) bool {
+	return level >= levelOf(c.module)
+}
+
+func (c *moduleCore) With(fields []zapcore.Field) zapcore.Core {
+	module := c.module
+	for _, f := range fields {
+		if f.Key == moduleField && f.Type == zapcore.StringType {
+			module = f.String
+		}
+	}
+
+	return &moduleCore{
+		Core:   c.Core.With(fields),
+		module: module,
+	}
+}
+
+func (c *moduleCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) {
+		return checked
+	}
+
+	return c.Core.Check(entry, checked)
+}
+
+// Level makes zap.Logger.Level return the level of the module.
+func (c *moduleCore) Level() zapcore.Level {
+	return levelOf(c.module)
+}
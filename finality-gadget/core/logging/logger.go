@@ -1,5 +1,7 @@
 package logging
 
+import "go.uber.org/zap"
+
 type Logger interface {
 	Debug(msg string, tags ...any)
 	Info(msg string, tags ...any)
@@ -14,4 +16,7 @@ type Logger interface {
 	Fatalf(template string, args ...interface{})
 
 	With(tags ...any) Logger
+
+	// Inner returns the *zap.Logger with the same outputs and fields, for the libraries which require zap.
+	Inner() *zap.Logger
 }
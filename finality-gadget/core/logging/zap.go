@@ -1,80 +1,145 @@
 package logging
 
 import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-type LogLevel string
-
-const (
-	Development LogLevel = "development" // prints debug and above
-	Production  LogLevel = "production"  // prints info and above
-)
-
+// ZapLogger is the Logger by zap, the *zap.Logger of it can be used by the libraries which require zap.
 type ZapLogger struct {
+	// logger skips the caller of the wrapper methods
 	logger *zap.Logger
+	inner  *zap.Logger
 }
 
 var _ Logger = (*ZapLogger)(nil)
 
-func NewLogLevel(isProduction bool) LogLevel {
-	if isProduction {
-		return Production
-	} else {
-		return Development
+// logLevel is shared by all the loggers, so the level can be changed when the config is reloaded.
+var logLevel = zap.NewAtomicLevel()
+
+// New creates the root logger of the process by the config, it should be called once in the main,
+// and the loggers of the components are derived from it by With, so all the logs share the outputs and the fields.
+func New(cfg *Config, isProduction bool, fields ...zap.Field) (*ZapLogger, error) {
+	if err := ApplyLevels(cfg, isProduction); err != nil {
+		return nil, err
 	}
-}
 
-// TODO: add a zap inner for logger interface.
-func NewZapLogger(env LogLevel) (*ZapLogger, error) {
-	config := zap.NewProductionConfig()
-	if env == Development {
-		config = zap.NewDevelopmentConfig()
+	sink, err := newOutput(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	if !isProduction {
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
 	}
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
 
-	config.DisableStacktrace = true
-	config.Encoding = "console"
-	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	config.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	var encoder zapcore.Encoder
+	if cfg.Format == FormatJson {
+		encoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	} else {
+		// the colors are only for the terminal
+		if !isProduction && isTerminalOnly(cfg.Outputs) {
+			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	}
 
-	if env == Development {
-		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	// the levels are checked by the moduleCore, so the inner enables all
+	core := zapcore.NewCore(encoder, sink, zapcore.DebugLevel)
+
+	switch {
+	case cfg.Sampling.Enabled():
+		tick := cfg.Sampling.Tick
+		if tick == 0 {
+			tick = time.Second
+		}
+		core = zapcore.NewSamplerWithOptions(core, tick, cfg.Sampling.Initial, cfg.Sampling.Thereafter)
+	case isProduction:
+		// same as the zap production config
+		core = zapcore.NewSamplerWithOptions(core, time.Second, 100, 100)
 	}
 
-	logger, err := config.Build(zap.AddCallerSkip(1))
-	if err != nil {
-		panic(err)
+	opts := []zap.Option{zap.AddCaller(), zap.ErrorOutput(zapcore.Lock(os.Stderr))}
+	if !isProduction {
+		opts = append(opts, zap.Development())
 	}
+
+	return NewFromZap(zap.New(&moduleCore{Core: core}, opts...).With(fields...)), nil
+}
+
+// NewFromZap wraps the *zap.Logger as the Logger.
+func NewFromZap(logger *zap.Logger) *ZapLogger {
 	return &ZapLogger{
-		logger: logger,
-	}, nil
+		logger: logger.WithOptions(zap.AddCallerSkip(1)),
+		inner:  logger,
+	}
 }
 
-func NewZapLoggerInner(env LogLevel) (*zap.Logger, error) {
-	config := zap.NewProductionConfig()
-	if env == Development {
-		config = zap.NewDevelopmentConfig()
+// ServiceFields returns the fields attached to all the logs of the service, the chain id is omitted if unknown.
+func ServiceFields(service string, chainId uint64) []zap.Field {
+	fields := []zap.Field{zap.String("service", service)}
+	if chainId != 0 {
+		fields = append(fields, zap.Uint64("chain_id", chainId))
 	}
+	return fields
+}
 
-	config.DisableStacktrace = true
-	config.Encoding = "console"
-	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	config.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+// newOutput opens the outputs of the config.
+func newOutput(cfg *Config) (zapcore.WriteSyncer, error) {
+	outputs := cfg.Outputs
+	if len(outputs) == 0 {
+		outputs = []string{OutputStderr}
+	}
 
-	if env == Development {
-		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	syncers := make([]zapcore.WriteSyncer, 0, len(outputs))
+	for _, o := range outputs {
+		switch o {
+		case OutputStderr:
+			syncers = append(syncers, zapcore.Lock(os.Stderr))
+		case OutputStdout:
+			syncers = append(syncers, zapcore.Lock(os.Stdout))
+		default:
+			if err := os.MkdirAll(filepath.Dir(o), 0o755); err != nil {
+				return nil, errors.Wrapf(err, "failed to create the dir of %s", o)
+			}
+
+			syncers = append(syncers, zapcore.AddSync(&lumberjack.Logger{
+				Filename:   o,
+				MaxSize:    cfg.Rotation.MaxSizeMB,
+				MaxAge:     cfg.Rotation.MaxAgeDays,
+				MaxBackups: cfg.Rotation.MaxBackups,
+				Compress:   cfg.Rotation.Compress,
+				LocalTime:  true,
+			}))
+		}
 	}
 
-	logger, err := config.Build()
-	if err != nil {
-		panic(err)
+	return zapcore.NewMultiWriteSyncer(syncers...), nil
+}
+
+// isTerminalOnly returns true if the outputs are only stderr or stdout.
+func isTerminalOnly(outputs []string) bool {
+	for _, o := range outputs {
+		if o != OutputStderr && o != OutputStdout {
+			return false
+		}
 	}
-	return logger, nil
+	return true
 }
 
+// Inner returns the *zap.Logger without the caller skip of the wrapper.
 func (z *ZapLogger) Inner() *zap.Logger {
-	return z.logger
+	return z.inner
 }
 
 func (z *ZapLogger) Debug(msg string, tags ...any) {
@@ -118,7 +183,10 @@ func (z *ZapLogger) Fatalf(template string, args ...interface{}) {
 }
 
 func (z *ZapLogger) With(tags ...any) Logger {
-	return &ZapLogger{
-		logger: z.logger.Sugar().With(tags...).Desugar(),
-	}
+	return NewFromZap(z.inner.Sugar().With(tags...).Desugar())
+}
+
+// Sync flushes the buffered logs, it should be called before the process exits.
+func (z *ZapLogger) Sync() error {
+	return z.inner.Sync()
 }
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	"gopkg.in/yaml.v3"
+)
+
+var redactedFlag = cli.BoolFlag{
+	Name:  "redacted",
+	Usage: "replace the secrets and the passwords in urls by `REDACTED`",
+}
+
+// ConfigLoader reads the config with env and validates it, it should return a pointer to the config.
+type ConfigLoader func(cliCtx *cli.Context) (any, error)
+
+// NewConfigCommand returns the `config` command for the binary.
+func NewConfigCommand(defaultPath string, load ConfigLoader) cli.Command {
+	return cli.Command{
+		Name:  "config",
+		Usage: "subcommand for the config file",
+		Subcommands: []cli.Command{
+			{
+				Name:  "validate",
+				Usage: "validate the config with env, exit with non-zero if invalid",
+				Action: func(cliCtx *cli.Context) error {
+					path := ConfigPath(cliCtx, defaultPath)
+					if _, err := load(cliCtx); err != nil {
+						PrintConfigErrors(os.Stderr, err)
+						return errors.Errorf("the config %s is invalid", path)
+					}
+
+					fmt.Printf("the config %s is valid\n", path)
+					return nil
+				},
+			},
+			{
+				Name:  "print",
+				Usage: "print the effective config merged with env",
+				Flags: []cli.Flag{redactedFlag},
+				Action: func(cliCtx *cli.Context) error {
+					config, err := load(cliCtx)
+					if err != nil {
+						PrintConfigErrors(os.Stderr, err)
+						return errors.Errorf("the config %s is invalid", ConfigPath(cliCtx, defaultPath))
+					}
+
+					if cliCtx.Bool(redactedFlag.Name) {
+						Redact(config)
+					}
+
+					out, err := yaml.Marshal(config)
+					if err != nil {
+						return errors.Wrap(err, "failed to marshal the config")
+					}
+
+					fmt.Print(string(out))
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// PrintConfigErrors prints each field error in a line.
+func PrintConfigErrors(w io.Writer, err error) {
+	if err == nil {
+		return
+	}
+
+	var cfgErrs ConfigErrors
+	if !errors.As(err, &cfgErrs) {
+		fmt.Fprintln(w, err)
+		return
+	}
+
+	for _, fe := range cfgErrs {
+		fmt.Fprintln(w, fe.Error())
+	}
+}
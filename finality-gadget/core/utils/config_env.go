@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func LookupEnvStr(name string, default_value string) string {
@@ -28,3 +30,48 @@ func LookupEnvUint64(name string, default_value uint64) uint64 {
 
 	return default_value
 }
+
+func LookupEnvBool(name string, default_value bool) bool {
+	v, ok := os.LookupEnv(name)
+	if ok && v != "" {
+		vb, err := strconv.ParseBool(v)
+		if err != nil {
+			panic(fmt.Sprintf("parse %s with value %s to bool error: %v", name, v, err))
+		}
+
+		return vb
+	}
+
+	return default_value
+}
+
+func LookupEnvDuration(name string, default_value time.Duration) time.Duration {
+	v, ok := os.LookupEnv(name)
+	if ok && v != "" {
+		vd, err := time.ParseDuration(v)
+		if err != nil {
+			panic(fmt.Sprintf("parse %s with value %s to duration error: %v", name, v, err))
+		}
+
+		return vd
+	}
+
+	return default_value
+}
+
+// LookupEnvStrList parses the env value split by `,`.
+func LookupEnvStrList(name string, default_value []string) []string {
+	v, ok := os.LookupEnv(name)
+	if ok && v != "" {
+		res := make([]string, 0, 4)
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				res = append(res, s)
+			}
+		}
+
+		return res
+	}
+
+	return default_value
+}
@@ -0,0 +1,290 @@
+package utils
+
+import (
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// EnvPrefix is the prefix of the env for the config fields.
+	EnvPrefix = "FINALITY_GADGET"
+
+	// envFileSuffix is the suffix of the env to read the value from a file, e.g. the secrets mounted by Kubernetes.
+	envFileSuffix = "_FILE"
+
+	// secretTag marks the field which should be redacted when printing, like `secret:"true"`.
+	secretTag = "secret"
+
+	redactedValue = "REDACTED"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ApplyEnv sets the config fields from the env, the env name is the prefix with the yaml path
+// in upper snake case, e.g. `FINALITY_GADGET_LAYER2_ETH_RPC_URL` for `layer2.eth_rpc_url`.
+// The value can also be read from the file in `<NAME>_FILE`.
+// The lists of structs and the maps are not supported.
+func ApplyEnv(prefix string, o any) error {
+	v := reflect.ValueOf(o)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("the config should be a pointer to struct, got %T", o)
+	}
+
+	var errs ConfigErrors
+	applyEnvToStruct(v.Elem(), prefix, &errs)
+
+	return errs.Err()
+}
+
+// EnvName returns the env name for the yaml key.
+func EnvName(prefix, key string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	b.WriteByte('_')
+
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == '.':
+			b.WriteByte('_')
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])):
+			b.WriteByte('_')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+
+	return b.String()
+}
+
+// yamlKey returns the yaml key of the field, and if it is inlined.
+func yamlKey(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false
+	}
+
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "inline" {
+			return "", true
+		}
+	}
+
+	if parts[0] != "" {
+		return parts[0], false
+	}
+
+	// the default key of yaml.v3
+	return strings.ToLower(f.Name), false
+}
+
+func applyEnvToStruct(v reflect.Value, prefix string, errs *ConfigErrors) {
+	t := v.Type()
+
+	// the names of the fields, the `_FILE` variant is not used if it is a field too, like `token` and `token_file`
+	names := make(map[string]struct{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if key, _ := yamlKey(t.Field(i)); key != "" {
+			names[EnvName(prefix, key)] = struct{}{}
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		key, inline := yamlKey(f)
+		fv := v.Field(i)
+
+		if inline {
+			if fv.Kind() == reflect.Struct {
+				applyEnvToStruct(fv, prefix, errs)
+			}
+			continue
+		}
+
+		if key == "" {
+			continue
+		}
+
+		name := EnvName(prefix, key)
+		if fv.Kind() == reflect.Struct && fv.Type() != durationType {
+			applyEnvToStruct(fv, name, errs)
+			continue
+		}
+
+		_, noFile := names[name+envFileSuffix]
+		value, ok, err := lookupEnvValue(name, !noFile)
+		if err != nil {
+			errs.Add(name, err)
+			continue
+		}
+
+		if ok {
+			errs.Add(name, setFieldValue(fv, value))
+		}
+	}
+}
+
+// lookupEnvValue returns the value of the env, or the content of the file in `<NAME>_FILE`.
+func lookupEnvValue(name string, withFile bool) (string, bool, error) {
+	value, ok := os.LookupEnv(name)
+	ok = ok && value != ""
+
+	if !withFile {
+		return value, ok, nil
+	}
+
+	path, fileOk := os.LookupEnv(name + envFileSuffix)
+	if !fileOk || path == "" {
+		return value, ok, nil
+	}
+
+	if ok {
+		return "", false, errors.Errorf("%s and %s%s should not be set together", name, name, envFileSuffix)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, errors.Wrapf(err, "failed to read %s%s", name, envFileSuffix)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
+
+func setFieldValue(fv reflect.Value, value string) error {
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid duration %s", value)
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid bool %s", value)
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return errors.Wrapf(err, "invalid int %s", value)
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return errors.Wrapf(err, "invalid uint %s", value)
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return errors.Wrapf(err, "invalid float %s", value)
+		}
+		fv.SetFloat(n)
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.String {
+			// the lists of structs can only be set in the config file
+			return nil
+		}
+
+		list := make([]string, 0, 4)
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				list = append(list, s)
+			}
+		}
+
+		res := reflect.MakeSlice(fv.Type(), len(list), len(list))
+		for i, s := range list {
+			res.Index(i).SetString(s)
+		}
+		fv.Set(res)
+	}
+
+	return nil
+}
+
+// Redact replaces the values of the fields tagged by `secret:"true"` and the passwords in the urls,
+// the config is modified in place, so it should only be used for printing.
+func Redact(o any) {
+	redactValue(reflect.ValueOf(o), false)
+}
+
+func redactValue(v reflect.Value, secret bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			redactValue(v.Elem(), secret)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				redactValue(v.Field(i), t.Field(i).Tag.Get(secretTag) == "true")
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			redactValue(v.Index(i), secret)
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			return
+		}
+
+		iter := v.MapRange()
+		for iter.Next() {
+			s := redactString(iter.Value().String(), secret)
+			v.SetMapIndex(iter.Key(), reflect.ValueOf(s).Convert(v.Type().Elem()))
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(redactString(v.String(), secret))
+		}
+	}
+}
+
+func redactString(s string, secret bool) string {
+	if s == "" {
+		return s
+	}
+
+	if secret {
+		return redactedValue
+	}
+
+	if !strings.Contains(s, "://") {
+		return s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redactedValue)
+		return u.String()
+	}
+
+	return s
+}
@@ -1,7 +1,8 @@
 package utils
 
 import (
-	"log"
+	"bytes"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -11,15 +12,17 @@ import (
 	"github.com/urfave/cli"
 )
 
-func ReadConfig(cliCtx *cli.Context, defaultPath string, o interface{}) error {
-	configFilePath := cliCtx.GlobalString(ConfigFileFlag.Name)
-
-	path := configFilePath
-	if configFilePath == "" {
-		path = defaultPath
+// ConfigPath returns the config path from the flag, or the default path if not set.
+func ConfigPath(cliCtx *cli.Context, defaultPath string) string {
+	if configFilePath := cliCtx.GlobalString(ConfigFileFlag.Name); configFilePath != "" {
+		return configFilePath
 	}
 
-	return readYamlConfig(path, o)
+	return defaultPath
+}
+
+func ReadConfig(cliCtx *cli.Context, defaultPath string, o interface{}) error {
+	return readYamlConfig(ConfigPath(cliCtx, defaultPath), o)
 }
 
 func readFile(path string) ([]byte, error) {
@@ -30,18 +33,22 @@ func readFile(path string) ([]byte, error) {
 	return b, nil
 }
 
+// readYamlConfig decodes the yaml file into o, the unknown fields are rejected.
 func readYamlConfig(path string, o interface{}) error {
-	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
-		log.Fatal("Path ", path, " does not exist")
-	}
 	b, err := readFile(path)
 	if err != nil {
-		return err
+		if errors.Is(err, os.ErrNotExist) {
+			return errors.Errorf("the config file %s does not exist", path)
+		}
+		return errors.Wrapf(err, "failed to read the config file %s", path)
 	}
 
-	err = yaml.Unmarshal(b, o)
-	if err != nil {
-		log.Fatalf("unable to parse file with error %#v", err)
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+
+	// an empty file is an empty config
+	if err := decoder.Decode(o); err != nil && !errors.Is(err, io.EOF) {
+		return errors.Wrapf(err, "failed to parse the config file %s", path)
 	}
 
 	return nil
@@ -0,0 +1,230 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// reloadTag marks the field which can be reloaded without restart, like `reload:"hot"`,
+	// all the sub fields of a hot struct are hot too, the fields not marked require a restart.
+	reloadTag = "reload"
+	reloadHot = "hot"
+)
+
+// ConfigChange is a changed field between two configs.
+type ConfigChange struct {
+	// Field is the yaml path like `common.rpc_cors`.
+	Field string
+	Old   string
+	New   string
+	// Hot is true if the field can be reloaded without restart.
+	Hot bool
+}
+
+func (c ConfigChange) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Field, c.Old, c.New)
+}
+
+// DiffConfig returns the changed fields from old to new, the secrets are redacted.
+// The old and new should be the pointers to the same config struct.
+func DiffConfig(old, new any) []ConfigChange {
+	var changes []ConfigChange
+	diffValue("", reflect.ValueOf(old), reflect.ValueOf(new), false, false, &changes)
+	return changes
+}
+
+func diffValue(path string, a, b reflect.Value, hot, secret bool, changes *[]ConfigChange) {
+	if a.Kind() == reflect.Pointer && b.Kind() == reflect.Pointer && !a.IsNil() && !b.IsNil() {
+		diffValue(path, a.Elem(), b.Elem(), hot, secret, changes)
+		return
+	}
+
+	if a.Kind() == reflect.Struct && a.Type() != durationType {
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+
+			key, inline := yamlKey(f)
+			fieldPath := path
+			switch {
+			case inline:
+			case key == "":
+				continue
+			case path == "":
+				fieldPath = key
+			default:
+				fieldPath = path + "." + key
+			}
+
+			diffValue(fieldPath, a.Field(i), b.Field(i), hot || f.Tag.Get(reloadTag) == reloadHot,
+				f.Tag.Get(secretTag) == "true", changes)
+		}
+		return
+	}
+
+	if reflect.DeepEqual(a.Interface(), b.Interface()) {
+		return
+	}
+
+	*changes = append(*changes, ConfigChange{
+		Field: path,
+		Old:   formatConfigValue(a, secret),
+		New:   formatConfigValue(b, secret),
+		Hot:   hot,
+	})
+}
+
+func formatConfigValue(v reflect.Value, secret bool) string {
+	switch v.Kind() {
+	case reflect.String:
+		return redactString(v.String(), secret)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			// the items may contain secrets
+			return fmt.Sprintf("(%d items)", v.Len())
+		}
+
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = redactString(v.Index(i).String(), secret)
+		}
+		return fmt.Sprint(items)
+	case reflect.Map:
+		return fmt.Sprintf("(%d items)", v.Len())
+	}
+
+	if secret {
+		return redactedValue
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+// mergeHot sets the hot fields of dst by src.
+func mergeHot(dst, src reflect.Value, hot bool) {
+	if dst.Kind() == reflect.Struct && dst.Type() != durationType {
+		t := dst.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				mergeHot(dst.Field(i), src.Field(i), hot || t.Field(i).Tag.Get(reloadTag) == reloadHot)
+			}
+		}
+		return
+	}
+
+	if hot {
+		dst.Set(src)
+	}
+}
+
+// ConfigReloader reloads the config on SIGHUP or by Reload, only the hot fields are applied,
+// the changes of the other fields are logged and ignored until restart.
+type ConfigReloader[T any] struct {
+	logger   *zap.Logger
+	load     func() (*T, error)
+	current  atomic.Pointer[T]
+	handlers []func(cfg *T)
+	mu       sync.Mutex
+}
+
+// NewConfigReloader creates the reloader with the config in use, the load should read and validate the config.
+func NewConfigReloader[T any](logger *zap.Logger, cfg *T, load func() (*T, error)) *ConfigReloader[T] {
+	r := &ConfigReloader[T]{
+		logger: logger.With(zap.String("module", "config")),
+		load:   load,
+	}
+	r.current.Store(cfg)
+
+	return r
+}
+
+// Current returns the config with the hot fields reloaded.
+func (r *ConfigReloader[T]) Current() *T {
+	return r.current.Load()
+}
+
+// OnReload adds the handler to apply the hot fields, it is called after the config is validated,
+// so it should not fail.
+func (r *ConfigReloader[T]) OnReload(handler func(cfg *T)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers = append(r.handlers, handler)
+}
+
+// Reload loads the config and applies the changed hot fields, the current config is kept if the new one is invalid.
+func (r *ConfigReloader[T]) Reload() ([]ConfigChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.load()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the config")
+	}
+
+	current := r.current.Load()
+	changes := DiffConfig(current, next)
+
+	hotChanged := false
+	for _, c := range changes {
+		if c.Hot {
+			hotChanged = true
+			r.logger.Info("config changed", zap.String("field", c.Field), zap.String("old", c.Old), zap.String("new", c.New))
+		} else {
+			r.logger.Warn("config changed but requires restart, ignored",
+				zap.String("field", c.Field), zap.String("old", c.Old), zap.String("new", c.New))
+		}
+	}
+
+	if !hotChanged {
+		r.logger.Info("no hot reloadable config changed")
+		return changes, nil
+	}
+
+	merged := *current
+	mergeHot(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(next).Elem(), false)
+	r.current.Store(&merged)
+
+	for _, handler := range r.handlers {
+		handler(&merged)
+	}
+
+	r.logger.Info("config reloaded")
+
+	return changes, nil
+}
+
+// WatchSignal reloads the config when receiving SIGHUP until ctx is done.
+func (r *ConfigReloader[T]) WatchSignal(ctx context.Context) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sigs)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigs:
+				r.logger.Info("reload config by SIGHUP")
+				if _, err := r.Reload(); err != nil {
+					r.logger.Error("reload config failed, keep the current config", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
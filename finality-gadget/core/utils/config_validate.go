@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/pkg/errors"
+)
+
+// FieldError is the validation error of a config field, the field is the yaml path like `layer2.eth_rpc_url`.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigErrors collects all the field errors of a config, so all of them can be fixed at once.
+type ConfigErrors []*FieldError
+
+func (e ConfigErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Add adds the error for the field, nothing will be added if err is nil.
+func (e *ConfigErrors) Add(field string, err error) {
+	if err != nil {
+		*e = append(*e, &FieldError{Field: field, Err: err})
+	}
+}
+
+func (e *ConfigErrors) Addf(field string, format string, args ...any) {
+	e.Add(field, fmt.Errorf(format, args...))
+}
+
+// Merge adds the errors from the Validate of a sub config, the fields will be prefixed by the yaml key of it.
+func (e *ConfigErrors) Merge(prefix string, err error) {
+	if err == nil {
+		return
+	}
+
+	var subErrs ConfigErrors
+	if !errors.As(err, &subErrs) {
+		e.Add(prefix, err)
+		return
+	}
+
+	for _, fe := range subErrs {
+		field := fe.Field
+		if prefix != "" {
+			field = prefix + "." + field
+		}
+		*e = append(*e, &FieldError{Field: field, Err: fe.Err})
+	}
+}
+
+// Err returns nil if no error, so it can be returned by Validate directly.
+func (e ConfigErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// CheckURL checks the url is absolute with the scheme in schemes, any scheme is ok if schemes is empty.
+func CheckURL(rawURL string, schemes ...string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.Wrapf(err, "invalid url %s", rawURL)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return errors.Errorf("invalid url %s, should be like `scheme://host:port`", rawURL)
+	}
+
+	if len(schemes) == 0 {
+		return nil
+	}
+
+	for _, s := range schemes {
+		if strings.EqualFold(u.Scheme, s) {
+			return nil
+		}
+	}
+
+	return errors.Errorf("invalid url %s, the scheme should be one of %s", rawURL, strings.Join(schemes, ","))
+}
+
+// CheckHostPort checks the address is `host:port`, the host can be empty for listen.
+func CheckHostPort(address string) error {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Wrapf(err, "invalid address %s, should be like `host:port`", address)
+	}
+
+	if p, err := strconv.ParseUint(port, 10, 16); err != nil || p == 0 {
+		return errors.Errorf("invalid port %s in address %s", port, address)
+	}
+
+	return nil
+}
+
+// CheckBech32Address checks the address is a valid bech32 address, with the prefix if it is not empty.
+func CheckBech32Address(address string, prefix string) error {
+	hrp, _, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return errors.Wrapf(err, "invalid bech32 address %s", address)
+	}
+
+	if prefix != "" && hrp != prefix {
+		return errors.Errorf("the address %s should start with %s", address, prefix)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// AlertingMetrics is the metrics of the incidents and the notifications of the stall detector.
+type AlertingMetrics struct {
+	incidentsFiring *prometheus.GaugeVec
+	notifications   *prometheus.CounterVec
+	probeErrors     *prometheus.CounterVec
+}
+
+var alertingMetricsRegisterOnce sync.Once
+
+var alertingMetricsInstance *AlertingMetrics
+
+// NewAlertingMetrics initializes and registers the metrics, using sync.Once to ensure it's done only once
+func NewAlertingMetrics() *AlertingMetrics {
+	alertingMetricsRegisterOnce.Do(func() {
+		alertingMetricsInstance = &AlertingMetrics{
+			incidentsFiring: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "alerting_incidents_firing",
+				Help: "The number of the firing incidents by the alert",
+			}, []string{"alert"}),
+			notifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "alerting_notifications_total",
+				Help: "The number of the notifications sent to each webhook, by the result: success or failed",
+			}, []string{"webhook", "result"}),
+			probeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "alerting_probe_errors_total",
+				Help: "The number of the failed checks by the alert, the incidents are kept unchanged",
+			}, []string{"alert"}),
+		}
+
+		prometheus.MustRegister(
+			alertingMetricsInstance.incidentsFiring,
+			alertingMetricsInstance.notifications,
+			alertingMetricsInstance.probeErrors,
+		)
+	})
+	return alertingMetricsInstance
+}
+
+// SetIncidentsFiring sets the number of the firing incidents of the alert.
+func (am *AlertingMetrics) SetIncidentsFiring(alert string, count int) {
+	am.incidentsFiring.WithLabelValues(alert).Set(float64(count))
+}
+
+// RecordNotification records a notification sent to the webhook.
+func (am *AlertingMetrics) RecordNotification(webhook string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failed"
+	}
+	am.notifications.WithLabelValues(webhook, result).Inc()
+}
+
+// RecordProbeError records a failed check of the alert.
+func (am *AlertingMetrics) RecordProbeError(alert string) {
+	am.probeErrors.WithLabelValues(alert).Inc()
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/carlmjohnson/versioninfo"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// processStartTime is the time the package initialized, as the process start time.
+var processStartTime = time.Now()
+
+var buildInfoRegisterOnce sync.Once
+
+// registerBuildInfo registers the build info and the start time gauges, the `process_start_time_seconds`
+// of the process collector is only available on linux, so the start time is exported by blitz too.
+func registerBuildInfo() {
+	buildInfoRegisterOnce.Do(func() {
+		buildInfo := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "blitz_build_info",
+			Help: "The build info of the binary, always 1",
+		}, []string{"version", "commit", "dirty", "go_version"})
+		startTime := prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "blitz_start_time_seconds",
+			Help: "The unix time the process started",
+		})
+
+		dirty := "false"
+		if versioninfo.DirtyBuild {
+			dirty = "true"
+		}
+
+		buildInfo.WithLabelValues(versioninfo.Version, versioninfo.Revision, dirty, runtime.Version()).Set(1)
+		startTime.Set(float64(processStartTime.Unix()))
+
+		prometheus.MustRegister(buildInfo, startTime)
+	})
+}
@@ -15,9 +15,24 @@ const (
 )
 
 type Config struct {
-	Host           string        `yaml:"host"`
-	Port           int           `yaml:"port"`
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+	// Deprecated: not used, kept to load the old configs.
 	UpdateInterval time.Duration `yaml:"updateinterval"`
+
+	// Serve the `/debug/pprof` endpoints, protected by the auth if set.
+	EnablePprof bool `yaml:"enable_pprof,omitempty"`
+	// Serve the read-only html status page in `/status`, protected by the auth if set.
+	EnableStatusPage bool `yaml:"enable_status_page,omitempty"`
+	// The basic auth for all the endpoints except `/ready`, enabled if the username is set.
+	BasicAuthUsername string `yaml:"basic_auth_username,omitempty"`
+	BasicAuthPassword string `yaml:"basic_auth_password,omitempty" secret:"true"`
+	// The bearer token for all the endpoints except `/ready`, enabled if set,
+	// the requests with either the basic auth or the token are allowed.
+	BearerToken string `yaml:"bearer_token,omitempty" secret:"true"`
+	// The server cert and key to enable TLS.
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
 }
 
 func (c *Config) WithEnv() {
@@ -26,16 +41,44 @@ func (c *Config) WithEnv() {
 }
 
 func (cfg *Config) Validate() error {
+	var errs utils.ConfigErrors
+
 	if cfg.Port < 0 || cfg.Port > 65535 {
-		return fmt.Errorf("invalid port: %d", cfg.Port)
+		errs.Addf("port", "invalid port: %d", cfg.Port)
 	}
 
 	ip := net.ParseIP(cfg.Host)
 	if ip == nil {
-		return fmt.Errorf("invalid host: %v", cfg.Host)
+		errs.Addf("host", "invalid host: %v", cfg.Host)
+	}
+
+	if cfg.UpdateInterval < 0 {
+		errs.Addf("updateinterval", "should not be negative")
+	}
+
+	if cfg.BasicAuthUsername != "" && cfg.BasicAuthPassword == "" {
+		errs.Addf("basic_auth_password", "required by the basic auth")
+	}
+
+	if cfg.BasicAuthUsername == "" && cfg.BasicAuthPassword != "" {
+		errs.Addf("basic_auth_username", "required by the basic auth")
+	}
+
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		errs.Addf("cert_file", "the cert_file and key_file should be set together")
 	}
 
-	return nil
+	return errs.Err()
+}
+
+// AuthEnabled returns true if the basic auth or the bearer token is set.
+func (cfg *Config) AuthEnabled() bool {
+	return cfg.BasicAuthUsername != "" || cfg.BearerToken != ""
+}
+
+// TLSEnabled returns true if the server cert is set.
+func (cfg *Config) TLSEnabled() bool {
+	return cfg.CertFile != ""
 }
 
 func (cfg *Config) Address() (string, error) {
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	EotsResultOk          = "ok"
+	EotsResultUnavailable = "unavailable"
+	EotsResultRefused     = "refused"
+)
+
+// EotsMetrics is the metrics for the connections to the eotsmanager.
+type EotsMetrics struct {
+	endpointUp *prometheus.GaugeVec
+	available  prometheus.Gauge
+	requests   *prometheus.CounterVec
+}
+
+var eotsMetricsRegisterOnce sync.Once
+
+var eotsMetricsInstance *EotsMetrics
+
+// NewEotsMetrics initializes and registers the metrics, using sync.Once to ensure it's done only once
+func NewEotsMetrics() *EotsMetrics {
+	eotsMetricsRegisterOnce.Do(func() {
+		eotsMetricsInstance = &EotsMetrics{
+			endpointUp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "eots_manager_endpoint_up",
+				Help: "1 if the eotsmanager endpoint passed the last health check",
+			}, []string{"endpoint"}),
+			available: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "eots_manager_available",
+				Help: "1 if any eotsmanager endpoint is available",
+			}),
+			requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "eots_manager_requests_total",
+				Help: "The number of requests to the eotsmanager, by the result: ok, unavailable or refused",
+			}, []string{"method", "result"}),
+		}
+
+		prometheus.MustRegister(
+			eotsMetricsInstance.endpointUp,
+			eotsMetricsInstance.available,
+			eotsMetricsInstance.requests,
+		)
+	})
+	return eotsMetricsInstance
+}
+
+func (em *EotsMetrics) RecordEndpointUp(endpoint string, up bool) {
+	if up {
+		em.endpointUp.WithLabelValues(endpoint).Set(1)
+	} else {
+		em.endpointUp.WithLabelValues(endpoint).Set(0)
+	}
+}
+
+func (em *EotsMetrics) RecordAvailable(available bool) {
+	if available {
+		em.available.Set(1)
+	} else {
+		em.available.Set(0)
+	}
+}
+
+func (em *EotsMetrics) RecordRequest(method, result string) {
+	em.requests.WithLabelValues(method, result).Inc()
+}
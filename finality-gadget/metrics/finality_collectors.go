@@ -0,0 +1,232 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The upstreams queried by the finality provider.
+const (
+	UpstreamL2       = "l2"
+	UpstreamBabylon  = "babylon"
+	UpstreamCosmWasm = "cosmwasm"
+	UpstreamBitcoind = "bitcoind"
+)
+
+// FinalityMetrics is the metrics for the finality lookups of the rpc provider.
+type FinalityMetrics struct {
+	l2Head          prometheus.Gauge
+	finalizedHead   prometheus.Gauge
+	lagBlocks       prometheus.Gauge
+	lagSeconds      prometheus.Gauge
+	timeToFinality  prometheus.Histogram
+	votedPowerRatio prometheus.Gauge
+	bisectionSteps  prometheus.Histogram
+	upstreamLatency *prometheus.HistogramVec
+	upstreamErrors  *prometheus.CounterVec
+
+	// the height of the block recorded in votedPowerRatio, to keep the most recent one
+	votedPowerRatioHeight uint64
+	mu                    sync.Mutex
+
+	// the last heads and the upstream requests, for the status page
+	heads     *FinalityHeads
+	upstreams map[string]*UpstreamHealth
+	statusMu  sync.Mutex
+}
+
+// FinalityHeads is the heads of the last finality lookup.
+type FinalityHeads struct {
+	L2Head             uint64    `json:"l2_head"`
+	FinalizedHead      uint64    `json:"finalized_head"`
+	LagBlocks          uint64    `json:"lag_blocks"`
+	FinalizedBlockTime time.Time `json:"finalized_block_time"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// UpstreamHealth is the requests to an upstream since started, the upstream is healthy if the last request succeeded.
+type UpstreamHealth struct {
+	Upstream        string    `json:"upstream"`
+	Healthy         bool      `json:"healthy"`
+	Requests        uint64    `json:"requests"`
+	Errors          uint64    `json:"errors"`
+	LastSuccess     time.Time `json:"last_success,omitzero"`
+	LastError       time.Time `json:"last_error,omitzero"`
+	LastErrorMethod string    `json:"last_error_method,omitempty"`
+}
+
+var finalityMetricsRegisterOnce sync.Once
+
+var finalityMetricsInstance *FinalityMetrics
+
+// NewFinalityMetrics initializes and registers the metrics, using sync.Once to ensure it's done only once
+func NewFinalityMetrics() *FinalityMetrics {
+	finalityMetricsRegisterOnce.Do(func() {
+		finalityMetricsInstance = &FinalityMetrics{
+			l2Head: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "finality_l2_head_height",
+				Help: "The l2 head height of the last finality lookup",
+			}),
+			finalizedHead: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "finality_babylon_finalized_height",
+				Help: "The l2 height finalized by babylon of the last finality lookup",
+			}),
+			lagBlocks: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "finality_lag_blocks",
+				Help: "The number of blocks between the l2 head and the babylon finalized block",
+			}),
+			lagSeconds: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "finality_lag_seconds",
+				Help: "The seconds between now and the timestamp of the babylon finalized block",
+			}),
+			timeToFinality: prometheus.NewHistogram(prometheus.HistogramOpts{
+				Name:    "finality_time_to_finality_seconds",
+				Help:    "The seconds from the block timestamp to the first observed quorum, for each block newly finalized",
+				Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+			}),
+			votedPowerRatio: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "finality_voted_power_ratio",
+				Help: "The voted power / total power of the most recent block checked",
+			}),
+			bisectionSteps: prometheus.NewHistogram(prometheus.HistogramOpts{
+				Name:    "finality_bisection_steps",
+				Help:    "The number of bisection steps per finality lookup",
+				Buckets: []float64{0, 1, 2, 4, 8, 12, 16, 24, 32},
+			}),
+			upstreamLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Name:    "finality_upstream_request_duration_seconds",
+				Help:    "The latency of the requests to the upstreams: l2, babylon, cosmwasm or bitcoind",
+				Buckets: prometheus.DefBuckets,
+			}, []string{"upstream", "method"}),
+			upstreamErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "finality_upstream_errors_total",
+				Help: "The number of failed requests to the upstreams: l2, babylon, cosmwasm or bitcoind",
+			}, []string{"upstream", "method"}),
+			upstreams: make(map[string]*UpstreamHealth, 4),
+		}
+
+		prometheus.MustRegister(
+			finalityMetricsInstance.l2Head,
+			finalityMetricsInstance.finalizedHead,
+			finalityMetricsInstance.lagBlocks,
+			finalityMetricsInstance.lagSeconds,
+			finalityMetricsInstance.timeToFinality,
+			finalityMetricsInstance.votedPowerRatio,
+			finalityMetricsInstance.bisectionSteps,
+			finalityMetricsInstance.upstreamLatency,
+			finalityMetricsInstance.upstreamErrors,
+		)
+	})
+	return finalityMetricsInstance
+}
+
+// RecordFinalizedHead records the heads of a lookup, the finalizedTime is the timestamp of the finalized block.
+func (fm *FinalityMetrics) RecordFinalizedHead(l2Head, finalized uint64, finalizedTime time.Time) {
+	fm.l2Head.Set(float64(l2Head))
+	fm.finalizedHead.Set(float64(finalized))
+
+	if l2Head >= finalized {
+		fm.lagBlocks.Set(float64(l2Head - finalized))
+	} else {
+		fm.lagBlocks.Set(0)
+	}
+
+	fm.lagSeconds.Set(time.Since(finalizedTime).Seconds())
+
+	heads := &FinalityHeads{
+		L2Head:             l2Head,
+		FinalizedHead:      finalized,
+		FinalizedBlockTime: finalizedTime,
+		UpdatedAt:          time.Now(),
+	}
+	if l2Head >= finalized {
+		heads.LagBlocks = l2Head - finalized
+	}
+
+	fm.statusMu.Lock()
+	defer fm.statusMu.Unlock()
+	fm.heads = heads
+}
+
+// Heads returns the heads of the last finality lookup, nil if no lookup finished.
+func (fm *FinalityMetrics) Heads() *FinalityHeads {
+	fm.statusMu.Lock()
+	defer fm.statusMu.Unlock()
+
+	if fm.heads == nil {
+		return nil
+	}
+	heads := *fm.heads
+	return &heads
+}
+
+// RecordTimeToFinality records the time from the block timestamp to now, it should be called once for each block newly finalized.
+func (fm *FinalityMetrics) RecordTimeToFinality(blockTime time.Time) {
+	fm.timeToFinality.Observe(time.Since(blockTime).Seconds())
+}
+
+// RecordVotedPower records the voted power ratio of the block, only if it is not older than the last recorded.
+func (fm *FinalityMetrics) RecordVotedPower(height uint64, votedPower, totalPower uint64) {
+	if totalPower == 0 {
+		return
+	}
+
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	if height < fm.votedPowerRatioHeight {
+		return
+	}
+
+	fm.votedPowerRatioHeight = height
+	fm.votedPowerRatio.Set(float64(votedPower) / float64(totalPower))
+}
+
+func (fm *FinalityMetrics) RecordBisectionSteps(steps int) {
+	fm.bisectionSteps.Observe(float64(steps))
+}
+
+// RecordUpstreamRequest records the latency from start, and the error if not nil.
+func (fm *FinalityMetrics) RecordUpstreamRequest(upstream, method string, start time.Time, err error) {
+	fm.upstreamLatency.WithLabelValues(upstream, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		fm.upstreamErrors.WithLabelValues(upstream, method).Inc()
+	}
+
+	fm.statusMu.Lock()
+	defer fm.statusMu.Unlock()
+
+	health, ok := fm.upstreams[upstream]
+	if !ok {
+		health = &UpstreamHealth{Upstream: upstream}
+		fm.upstreams[upstream] = health
+	}
+
+	// the error is not kept, it may contain the upstream url with the token
+	health.Requests++
+	health.Healthy = err == nil
+	if err != nil {
+		health.Errors++
+		health.LastError = time.Now()
+		health.LastErrorMethod = method
+	} else {
+		health.LastSuccess = time.Now()
+	}
+}
+
+// UpstreamHealth returns the health of the upstreams requested, sorted by the name.
+func (fm *FinalityMetrics) UpstreamHealth() []UpstreamHealth {
+	fm.statusMu.Lock()
+	defer fm.statusMu.Unlock()
+
+	res := make([]UpstreamHealth, 0, len(fm.upstreams))
+	for _, health := range fm.upstreams {
+		res = append(res, *health)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Upstream < res[j].Upstream })
+
+	return res
+}
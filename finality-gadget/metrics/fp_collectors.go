@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -8,6 +9,29 @@ import (
 
 type FpMetrics struct {
 	fpBabylonAddressBalances *prometheus.GaugeVec
+	fpInstanceRunning        *prometheus.GaugeVec
+	accountBalance           *prometheus.GaugeVec
+	accountBalanceDaysLeft   *prometheus.GaugeVec
+	accountBalanceSpendDaily *prometheus.GaugeVec
+
+	// the last balances recorded by the account name, for the alerts and the status
+	balances map[string]AccountBalance
+	mu       sync.Mutex
+}
+
+// AccountBalance is the balance of a monitored account, in the unit after the decimals.
+type AccountBalance struct {
+	Name    string  `json:"name"`
+	Address string  `json:"address"`
+	Denom   string  `json:"denom"`
+	Balance float64 `json:"balance"`
+	// The spend per day observed, nil if not observed long enough.
+	DailySpend *float64 `json:"daily_spend,omitempty"`
+	// The days left by the daily spend, nil if unknown or nothing spent.
+	DaysLeft *float64 `json:"days_left,omitempty"`
+
+	WarningThreshold  float64 `json:"warning_threshold,omitempty"`
+	CriticalThreshold float64 `json:"critical_threshold,omitempty"`
 }
 
 // Declare a package-level variable for sync.Once to ensure metrics are registered only once
@@ -24,10 +48,31 @@ func NewFpMetrics() *FpMetrics {
 				Name: "fp_babylon_address_balances",
 				Help: "Current Balance of a finality provider 's babylon address",
 			}, []string{"fp_address"}),
+			fpInstanceRunning: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "fp_instance_running",
+				Help: "Whether the finality provider instance is running in the operator (1) or not (0)",
+			}, []string{"fp_btc_pk"}),
+			accountBalance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "fp_account_balance",
+				Help: "The balance of a monitored account, in the unit after the decimals",
+			}, []string{"name", "address", "denom"}),
+			accountBalanceDaysLeft: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "fp_account_balance_days_left",
+				Help: "The days the balance of a monitored account can pay by the spend observed",
+			}, []string{"name", "address", "denom"}),
+			accountBalanceSpendDaily: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "fp_account_balance_daily_spend",
+				Help: "The spend per day observed of a monitored account, in the unit after the decimals",
+			}, []string{"name", "address", "denom"}),
+			balances: make(map[string]AccountBalance, 1),
 		}
 
 		// Register the metrics with Prometheus
 		prometheus.MustRegister(fpMetricsInstance.fpBabylonAddressBalances)
+		prometheus.MustRegister(fpMetricsInstance.fpInstanceRunning)
+		prometheus.MustRegister(fpMetricsInstance.accountBalance)
+		prometheus.MustRegister(fpMetricsInstance.accountBalanceDaysLeft)
+		prometheus.MustRegister(fpMetricsInstance.accountBalanceSpendDaily)
 	})
 	return fpMetricsInstance
 }
@@ -35,3 +80,60 @@ func NewFpMetrics() *FpMetrics {
 func (fm *FpMetrics) RecordFpBalance(address string, balance float64) {
 	fm.fpBabylonAddressBalances.WithLabelValues(address).Set(balance)
 }
+
+// RecordAccountBalance records the balance of a monitored account,
+// the days left and the daily spend are removed if unknown.
+func (fm *FpMetrics) RecordAccountBalance(b AccountBalance) {
+	labels := prometheus.Labels{"name": b.Name, "address": b.Address, "denom": b.Denom}
+	fm.accountBalance.With(labels).Set(b.Balance)
+
+	if b.DailySpend != nil {
+		fm.accountBalanceSpendDaily.With(labels).Set(*b.DailySpend)
+	} else {
+		fm.accountBalanceSpendDaily.Delete(labels)
+	}
+
+	if b.DaysLeft != nil {
+		fm.accountBalanceDaysLeft.With(labels).Set(*b.DaysLeft)
+	} else {
+		fm.accountBalanceDaysLeft.Delete(labels)
+	}
+
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	fm.balances[b.Name] = b
+}
+
+// RemoveAccountBalance removes the balance of the account not monitored any more.
+func (fm *FpMetrics) RemoveAccountBalance(name string) {
+	labels := prometheus.Labels{"name": name}
+	fm.accountBalance.DeletePartialMatch(labels)
+	fm.accountBalanceSpendDaily.DeletePartialMatch(labels)
+	fm.accountBalanceDaysLeft.DeletePartialMatch(labels)
+
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+	delete(fm.balances, name)
+}
+
+// AccountBalances returns the last balances recorded of the monitored accounts, sorted by the name.
+func (fm *FpMetrics) AccountBalances() []AccountBalance {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	res := make([]AccountBalance, 0, len(fm.balances))
+	for _, b := range fm.balances {
+		res = append(res, b)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res
+}
+
+func (fm *FpMetrics) RecordFpInstanceRunning(fpBtcPk string, running bool) {
+	v := 0.0
+	if running {
+		v = 1
+	}
+	fm.fpInstanceRunning.WithLabelValues(fpBtcPk).Set(v)
+}
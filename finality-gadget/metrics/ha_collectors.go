@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HaMetrics is the metrics for the active/standby mode of the operator.
+type HaMetrics struct {
+	isLeader         prometheus.Gauge
+	leaseTransitions prometheus.Counter
+	leaseErrors      prometheus.Counter
+}
+
+var haMetricsRegisterOnce sync.Once
+
+var haMetricsInstance *HaMetrics
+
+// NewHaMetrics initializes and registers the metrics, using sync.Once to ensure it's done only once
+func NewHaMetrics() *HaMetrics {
+	haMetricsRegisterOnce.Do(func() {
+		haMetricsInstance = &HaMetrics{
+			isLeader: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "ha_is_leader",
+				Help: "1 if the operator holds the lease and is the active one, 0 for standby",
+			}),
+			leaseTransitions: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "ha_lease_transitions_total",
+				Help: "The number of times the operator changed between active and standby",
+			}),
+			leaseErrors: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "ha_lease_errors_total",
+				Help: "The number of errors when acquiring or renewing the lease",
+			}),
+		}
+
+		prometheus.MustRegister(
+			haMetricsInstance.isLeader,
+			haMetricsInstance.leaseTransitions,
+			haMetricsInstance.leaseErrors,
+		)
+	})
+	return haMetricsInstance
+}
+
+func (hm *HaMetrics) RecordLeader(isLeader bool) {
+	if isLeader {
+		hm.isLeader.Set(1)
+	} else {
+		hm.isLeader.Set(0)
+	}
+}
+
+func (hm *HaMetrics) RecordLeaseTransition() {
+	hm.leaseTransitions.Inc()
+}
+
+func (hm *HaMetrics) RecordLeaseError() {
+	hm.leaseErrors.Inc()
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ParticipationMetrics is the metrics for the votes of each finality provider on the recent blocks.
+type ParticipationMetrics struct {
+	votingPower       *prometheus.GaugeVec
+	votedLastBlock    *prometheus.GaugeVec
+	participationRate *prometheus.GaugeVec
+	lastVotedHeight   *prometheus.GaugeVec
+}
+
+var participationMetricsRegisterOnce sync.Once
+
+var participationMetricsInstance *ParticipationMetrics
+
+// NewParticipationMetrics initializes and registers the metrics, using sync.Once to ensure it's done only once
+func NewParticipationMetrics() *ParticipationMetrics {
+	participationMetricsRegisterOnce.Do(func() {
+		participationMetricsInstance = &ParticipationMetrics{
+			votingPower: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "finality_fp_voting_power",
+				Help: "The voting power of the finality provider at the btc height of the most recent block recorded",
+			}, []string{"fp_btc_pk"}),
+			votedLastBlock: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "finality_fp_voted_last_block",
+				Help: "Whether the finality provider voted the most recent block recorded (1) or not (0)",
+			}, []string{"fp_btc_pk"}),
+			participationRate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "finality_fp_participation_rate",
+				Help: "The rate of the recent blocks recorded which the finality provider voted, in the blocks it has voting power",
+			}, []string{"fp_btc_pk"}),
+			lastVotedHeight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "finality_fp_last_voted_height",
+				Help: "The last l2 height recorded which the finality provider voted",
+			}, []string{"fp_btc_pk"}),
+		}
+
+		prometheus.MustRegister(
+			participationMetricsInstance.votingPower,
+			participationMetricsInstance.votedLastBlock,
+			participationMetricsInstance.participationRate,
+			participationMetricsInstance.lastVotedHeight,
+		)
+	})
+	return participationMetricsInstance
+}
+
+func (pm *ParticipationMetrics) RecordFpParticipation(fpBtcPk string, power uint64, votedLastBlock bool, rate float64, lastVotedHeight uint64) {
+	pm.votingPower.WithLabelValues(fpBtcPk).Set(float64(power))
+	if votedLastBlock {
+		pm.votedLastBlock.WithLabelValues(fpBtcPk).Set(1)
+	} else {
+		pm.votedLastBlock.WithLabelValues(fpBtcPk).Set(0)
+	}
+	pm.participationRate.WithLabelValues(fpBtcPk).Set(rate)
+	pm.lastVotedHeight.WithLabelValues(fpBtcPk).Set(float64(lastVotedHeight))
+}
+
+// DeleteFp removes the metrics of the finality provider which is not in the recent blocks.
+func (pm *ParticipationMetrics) DeleteFp(fpBtcPk string) {
+	pm.votingPower.DeleteLabelValues(fpBtcPk)
+	pm.votedLastBlock.DeleteLabelValues(fpBtcPk)
+	pm.participationRate.DeleteLabelValues(fpBtcPk)
+	pm.lastVotedHeight.DeleteLabelValues(fpBtcPk)
+}
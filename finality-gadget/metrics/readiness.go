@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ReadinessCheck returns an error if the component is not ready.
+type ReadinessCheck func() error
+
+var (
+	readinessChecks   = make(map[string]ReadinessCheck, 4)
+	readinessChecksMu sync.RWMutex
+)
+
+// RegisterReadinessCheck registers the check for `/ready` of the metrics server,
+// the check with the same name will be replaced.
+func RegisterReadinessCheck(name string, check ReadinessCheck) {
+	readinessChecksMu.Lock()
+	defer readinessChecksMu.Unlock()
+
+	readinessChecks[name] = check
+}
+
+// CheckReadiness runs all the checks, returns the errors by the check name.
+func CheckReadiness() map[string]string {
+	readinessChecksMu.RLock()
+	defer readinessChecksMu.RUnlock()
+
+	names := make([]string, 0, len(readinessChecks))
+	for name := range readinessChecks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := make(map[string]string, len(names))
+	for _, name := range names {
+		if err := readinessChecks[name](); err != nil {
+			res[name] = err.Error()
+		}
+	}
+
+	return res
+}
+
+func readyHandler(w http.ResponseWriter, _ *http.Request) {
+	failures := CheckReadiness()
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(failures) != 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"ready":    len(failures) == 0,
+		"failures": failures,
+	})
+}
@@ -2,8 +2,15 @@ package metrics
 
 import (
 	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"net"
 	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 )
@@ -14,14 +21,64 @@ type Server struct {
 	logger     *zap.Logger
 }
 
-func Start(addr string, logger *zap.Logger) *Server {
+// Start listens the address of the config and serves the metrics in a goroutine,
+// the listen errors are returned.
+func Start(cfg *Config, logger *zap.Logger) (*Server, error) {
+	addr, err := cfg.Address()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid metrics config")
+	}
+
+	registerBuildInfo()
+
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc(statusPathPrefix, statusHandler)
+
+	if cfg.EnableStatusPage {
+		mux.HandleFunc(statusPagePath, statusPageHandler)
+	}
+
+	if cfg.EnablePprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+
+	var handler http.Handler = mux
+	if cfg.AuthEnabled() {
+		handler = authHandler(cfg, mux)
+	}
+
+	// the readiness probes may not support the auth
+	root := http.NewServeMux()
+	root.HandleFunc("/ready", readyHandler)
+	root.Handle("/", handler)
 
 	// Create the HTTP server with the custom ServeMux as the handler
 	server := &http.Server{
-		Addr:    addr,
-		Handler: mux,
+		Addr:              addr,
+		Handler:           root,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if cfg.TLSEnabled() {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load the metrics server cert")
+		}
+
+		server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to listen metrics server on %s", addr)
 	}
 
 	// Store the logger in the server struct
@@ -32,13 +89,52 @@ func Start(addr string, logger *zap.Logger) *Server {
 
 	// Start the metrics server in a goroutine.
 	go func() {
-		s.logger.Info("Metrics server is starting", zap.String("addr", addr))
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			s.logger.Fatal("Metrics server failed to start", zap.Error(err))
+		s.logger.Info("Metrics server is starting",
+			zap.String("addr", addr), zap.Bool("tls", cfg.TLSEnabled()), zap.Bool("auth", cfg.AuthEnabled()))
+
+		var err error
+		if cfg.TLSEnabled() {
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+
+		if err != nil && err != http.ErrServerClosed {
+			s.logger.Error("Metrics server stopped by error", zap.Error(err))
 		}
 	}()
 
-	return s
+	return s, nil
+}
+
+// authHandler allows the requests with the basic auth or the bearer token in the config.
+func authHandler(cfg *Config, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cfg.BasicAuthUsername != "" {
+			username, password, ok := r.BasicAuth()
+			if ok && secureEqual(username, cfg.BasicAuthUsername) && secureEqual(password, cfg.BasicAuthPassword) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		if cfg.BearerToken != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if ok && secureEqual(token, cfg.BearerToken) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		if cfg.BasicAuthUsername != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="metrics"`)
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
 }
 
 // Stop gracefully shuts down the metrics server.
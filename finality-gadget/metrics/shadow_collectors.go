@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ShadowMetrics is the metrics for the operator in shadow mode, it records the votes
+// which would have been submitted and compares them with the votes on chain.
+type ShadowMetrics struct {
+	shadowVotes           *prometheus.CounterVec
+	shadowVoteMatches     *prometheus.CounterVec
+	shadowVoteMismatches  *prometheus.CounterVec
+	shadowPubRandCommits  *prometheus.CounterVec
+	shadowComparedHeight  *prometheus.GaugeVec
+	shadowPendingCompares prometheus.Gauge
+}
+
+var shadowMetricsRegisterOnce sync.Once
+
+var shadowMetricsInstance *ShadowMetrics
+
+// NewShadowMetrics initializes and registers the metrics, using sync.Once to ensure it's done only once
+func NewShadowMetrics() *ShadowMetrics {
+	shadowMetricsRegisterOnce.Do(func() {
+		shadowMetricsInstance = &ShadowMetrics{
+			shadowVotes: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "shadow_votes_total",
+				Help: "The number of finality votes signed but not broadcast in shadow mode",
+			}, []string{"fp_btc_pk"}),
+			shadowVoteMatches: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "shadow_vote_matches_total",
+				Help: "The number of shadow votes matched with the votes of the live finality provider on chain",
+			}, []string{"fp_btc_pk"}),
+			shadowVoteMismatches: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "shadow_vote_mismatches_total",
+				Help: "The number of shadow votes not matched with the votes of the live finality provider on chain",
+			}, []string{"fp_btc_pk"}),
+			shadowPubRandCommits: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "shadow_pub_rand_commits_total",
+				Help: "The number of public randomness commits built but not broadcast in shadow mode",
+			}, []string{"fp_btc_pk"}),
+			shadowComparedHeight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "shadow_last_compared_height",
+				Help: "The last l2 height compared the shadow vote with the chain",
+			}, []string{"fp_btc_pk"}),
+			shadowPendingCompares: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "shadow_pending_compares",
+				Help: "The number of shadow votes waiting to compare with the chain",
+			}),
+		}
+
+		prometheus.MustRegister(
+			shadowMetricsInstance.shadowVotes,
+			shadowMetricsInstance.shadowVoteMatches,
+			shadowMetricsInstance.shadowVoteMismatches,
+			shadowMetricsInstance.shadowPubRandCommits,
+			shadowMetricsInstance.shadowComparedHeight,
+			shadowMetricsInstance.shadowPendingCompares,
+		)
+	})
+	return shadowMetricsInstance
+}
+
+func (sm *ShadowMetrics) RecordShadowVote(fpBtcPk string) {
+	sm.shadowVotes.WithLabelValues(fpBtcPk).Inc()
+}
+
+func (sm *ShadowMetrics) RecordShadowPubRandCommit(fpBtcPk string) {
+	sm.shadowPubRandCommits.WithLabelValues(fpBtcPk).Inc()
+}
+
+func (sm *ShadowMetrics) RecordShadowCompare(fpBtcPk string, height uint64, matched bool) {
+	if matched {
+		sm.shadowVoteMatches.WithLabelValues(fpBtcPk).Inc()
+	} else {
+		sm.shadowVoteMismatches.WithLabelValues(fpBtcPk).Inc()
+	}
+	sm.shadowComparedHeight.WithLabelValues(fpBtcPk).Set(float64(height))
+}
+
+func (sm *ShadowMetrics) RecordShadowPendingCompares(count int) {
+	sm.shadowPendingCompares.Set(float64(count))
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const statusPathPrefix = "/status/"
+
+// StatusProvider returns the status to be encoded as json.
+type StatusProvider func() any
+
+var (
+	statusProviders   = make(map[string]StatusProvider, 4)
+	statusProvidersMu sync.RWMutex
+)
+
+// RegisterStatusProvider registers the provider for `/status/<name>` of the metrics server,
+// the provider with the same name will be replaced.
+func RegisterStatusProvider(name string, provider StatusProvider) {
+	statusProvidersMu.Lock()
+	defer statusProvidersMu.Unlock()
+
+	statusProviders[name] = provider
+}
+
+func statusHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, statusPathPrefix)
+
+	statusProvidersMu.RLock()
+	provider, ok := statusProviders[name]
+	statusProvidersMu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "status not found"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(provider())
+}
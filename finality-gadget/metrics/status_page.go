@@ -0,0 +1,290 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carlmjohnson/versioninfo"
+)
+
+const (
+	statusPagePath = "/status"
+	// statusPageRefresh is the seconds to reload the page, by the meta refresh as no script is used.
+	statusPageRefresh = 30
+)
+
+// statusPageOrder is the order of the known status sections, the others follow by the name.
+var statusPageOrder = []string{"operator", "finality", "finality-providers", "incidents", "balances"}
+
+// statusPageTemplate is self-contained, no script or external asset, so it works in the air-gapped networks.
+var statusPageTemplate = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<title>blitz status</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; font-size: 14px; margin: 24px; color: #1f2328; }
+h1 { font-size: 20px; margin-bottom: 4px; }
+h2 { font-size: 16px; margin-top: 28px; border-bottom: 1px solid #d0d7de; padding-bottom: 4px; }
+table { border-collapse: collapse; margin: 4px 0; }
+th, td { border: 1px solid #d0d7de; padding: 3px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; font-weight: 600; }
+td { font-family: ui-monospace, Menlo, Consolas, monospace; word-break: break-all; max-width: 640px; }
+.muted { color: #656d76; }
+.ok { color: #1a7f37; font-weight: 600; }
+.bad { color: #cf222e; font-weight: 600; }
+</style>
+</head>
+<body>
+<h1>blitz status</h1>
+<div class="muted">version {{.Version}}, started {{.StartedAt}}, rendered {{.Now}}, reloaded every {{.Refresh}}s</div>
+
+<h2>readiness</h2>
+{{if .Failures}}<table>{{range $name, $err := .Failures}}<tr><th>{{$name}}</th><td class="bad">{{$err}}</td></tr>{{end}}</table>
+{{else}}<div class="ok">ready</div>{{end}}
+
+{{range .Sections}}
+<h2>{{.Name}} <a class="muted" href="{{.Path}}">json</a></h2>
+{{.Body}}
+{{end}}
+</body>
+</html>
+`))
+
+type statusPageSection struct {
+	Name string
+	Path string
+	Body template.HTML
+}
+
+type statusPageData struct {
+	Version   string
+	StartedAt string
+	Now       string
+	Refresh   int
+	Failures  map[string]string
+	Sections  []statusPageSection
+}
+
+// statusPageHandler renders all the status providers as a read-only html page.
+func statusPageHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data := statusPageData{
+		Version:   versioninfo.Short(),
+		StartedAt: processStartTime.UTC().Format(time.RFC3339),
+		Now:       time.Now().UTC().Format(time.RFC3339),
+		Refresh:   statusPageRefresh,
+		Failures:  CheckReadiness(),
+	}
+
+	for _, name := range statusPageNames() {
+		statusProvidersMu.RLock()
+		provider := statusProviders[name]
+		statusProvidersMu.RUnlock()
+
+		data.Sections = append(data.Sections, statusPageSection{
+			Name: name,
+			Path: statusPathPrefix + name,
+			Body: renderStatus(provider()),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := statusPageTemplate.Execute(&buf, data); err != nil {
+		http.Error(w, "render status page failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	_, _ = buf.WriteTo(w)
+}
+
+// statusPageNames returns the names of the status providers, the known ones first.
+func statusPageNames() []string {
+	statusProvidersMu.RLock()
+	defer statusProvidersMu.RUnlock()
+
+	rank := func(name string) int {
+		for i, n := range statusPageOrder {
+			if n == name {
+				return i
+			}
+		}
+		return len(statusPageOrder)
+	}
+
+	names := make([]string, 0, len(statusProviders))
+	for name := range statusProviders {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ri, rj := rank(names[i]), rank(names[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+// renderStatus renders the status by its json, the objects as the key value tables,
+// and the lists of the objects as the tables with a row for each.
+func renderStatus(status any) template.HTML {
+	raw, err := json.Marshal(status)
+	if err != nil {
+		return template.HTML(`<div class="bad">` + template.HTMLEscapeString(err.Error()) + `</div>`)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	value, err := decodeOrdered(dec)
+	if err != nil {
+		return template.HTML(`<div class="bad">` + template.HTMLEscapeString(err.Error()) + `</div>`)
+	}
+
+	var sb strings.Builder
+	renderValue(&sb, value)
+	return template.HTML(sb.String())
+}
+
+// orderedField is a field of a json object, the objects are decoded as the fields
+// to keep the order of the struct fields.
+type orderedField struct {
+	Key   string
+	Value any
+}
+
+// decodeOrdered decodes the next json value, the objects as []orderedField.
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		fields := make([]orderedField, 0)
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, orderedField{Key: key.(string), Value: value})
+		}
+		_, err = dec.Token()
+		return fields, err
+	default:
+		values := make([]any, 0)
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		_, err = dec.Token()
+		return values, err
+	}
+}
+
+func renderValue(w io.StringWriter, value any) {
+	switch v := value.(type) {
+	case nil:
+		_, _ = w.WriteString(`<span class="muted">-</span>`)
+	case bool:
+		_, _ = w.WriteString(strconv.FormatBool(v))
+	case []orderedField:
+		_, _ = w.WriteString("<table>")
+		for _, f := range v {
+			_, _ = w.WriteString("<tr><th>" + template.HTMLEscapeString(f.Key) + "</th><td>")
+			renderValue(w, f.Value)
+			_, _ = w.WriteString("</td></tr>")
+		}
+		_, _ = w.WriteString("</table>")
+	case []any:
+		renderList(w, v)
+	case json.Number:
+		_, _ = w.WriteString(v.String())
+	case string:
+		_, _ = w.WriteString(template.HTMLEscapeString(v))
+	}
+}
+
+// renderList renders the list of the objects as a table, and the others as a comma separated list.
+func renderList(w io.StringWriter, values []any) {
+	if len(values) == 0 {
+		_, _ = w.WriteString(`<span class="muted">none</span>`)
+		return
+	}
+
+	var columns []string
+	seen := make(map[string]struct{})
+	for _, value := range values {
+		fields, ok := value.([]orderedField)
+		if !ok {
+			for i, value := range values {
+				if i != 0 {
+					_, _ = w.WriteString(", ")
+				}
+				renderValue(w, value)
+			}
+			return
+		}
+
+		for _, f := range fields {
+			if _, ok := seen[f.Key]; !ok {
+				seen[f.Key] = struct{}{}
+				columns = append(columns, f.Key)
+			}
+		}
+	}
+
+	_, _ = w.WriteString("<table><tr>")
+	for _, c := range columns {
+		_, _ = w.WriteString("<th>" + template.HTMLEscapeString(c) + "</th>")
+	}
+	_, _ = w.WriteString("</tr>")
+
+	for _, value := range values {
+		row := make(map[string]any, len(columns))
+		for _, f := range value.([]orderedField) {
+			row[f.Key] = f.Value
+		}
+
+		_, _ = w.WriteString("<tr>")
+		for _, c := range columns {
+			_, _ = w.WriteString("<td>")
+			if v, ok := row[c]; ok {
+				renderValue(w, v)
+			}
+			_, _ = w.WriteString("</td>")
+		}
+		_, _ = w.WriteString("</tr>")
+	}
+	_, _ = w.WriteString("</table>")
+}
@@ -0,0 +1,194 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The tx types for the fee metrics.
+const (
+	TxTypeFinalitySigs = "finality_sigs"
+	TxTypePubRand      = "pub_rand"
+)
+
+// VoteMetrics is the metrics for the finality signatures and public randomness submitted by the operator.
+type VoteMetrics struct {
+	finalitySigsSubmitted *prometheus.CounterVec
+	finalitySigsFailures  *prometheus.CounterVec
+	finalitySigsBatchSize prometheus.Histogram
+	finalitySigsLatency   prometheus.Histogram
+	lastVotedHeight       *prometheus.GaugeVec
+	pubRandCommits        *prometheus.CounterVec
+	pubRandFailures       *prometheus.CounterVec
+	pubRandCommitted      *prometheus.GaugeVec
+	pubRandRemaining      *prometheus.GaugeVec
+	txFees                *prometheus.HistogramVec
+
+	// the committed and the last voted heights, to calculate the remaining randomness
+	committed map[string]uint64
+	voted     map[string]uint64
+	mu        sync.Mutex
+}
+
+var voteMetricsRegisterOnce sync.Once
+
+var voteMetricsInstance *VoteMetrics
+
+// NewVoteMetrics initializes and registers the metrics, using sync.Once to ensure it's done only once
+func NewVoteMetrics() *VoteMetrics {
+	voteMetricsRegisterOnce.Do(func() {
+		voteMetricsInstance = &VoteMetrics{
+			finalitySigsSubmitted: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "fp_finality_sigs_submitted_total",
+				Help: "The number of finality signatures submitted to the finality contract",
+			}, []string{"fp_btc_pk"}),
+			finalitySigsFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "fp_finality_sigs_failures_total",
+				Help: "The number of failed finality signatures submissions, by the error class",
+			}, []string{"fp_btc_pk", "error_class"}),
+			finalitySigsBatchSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+				Name:    "fp_finality_sigs_batch_size",
+				Help:    "The number of finality signatures in a submission",
+				Buckets: []float64{1, 2, 4, 8, 16, 32, 64, 128},
+			}),
+			finalitySigsLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+				Name:    "fp_finality_sigs_submit_duration_seconds",
+				Help:    "The latency of the finality signatures submission, until the tx is included",
+				Buckets: prometheus.ExponentialBuckets(0.5, 2, 10),
+			}),
+			lastVotedHeight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "fp_last_voted_height",
+				Help: "The last l2 height voted by the finality provider",
+			}, []string{"fp_btc_pk"}),
+			pubRandCommits: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "fp_pub_rand_commits_total",
+				Help: "The number of public randomness commits submitted to the finality contract",
+			}, []string{"fp_btc_pk"}),
+			pubRandFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "fp_pub_rand_commit_failures_total",
+				Help: "The number of failed public randomness commits, by the error class",
+			}, []string{"fp_btc_pk", "error_class"}),
+			pubRandCommitted: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "fp_pub_rand_committed_height",
+				Help: "The last l2 height the public randomness committed to",
+			}, []string{"fp_btc_pk"}),
+			pubRandRemaining: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "fp_pub_rand_remaining_blocks",
+				Help: "The number of blocks the committed public randomness can still vote, from the last voted height",
+			}, []string{"fp_btc_pk"}),
+			txFees: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Name:    "fp_tx_fee_bbn",
+				Help:    "The fee in bbn paid by each tx, by the tx type: finality_sigs or pub_rand",
+				Buckets: prometheus.ExponentialBuckets(0.0001, 4, 10),
+			}, []string{"fp_btc_pk", "tx_type"}),
+			committed: make(map[string]uint64, 4),
+			voted:     make(map[string]uint64, 4),
+		}
+
+		prometheus.MustRegister(
+			voteMetricsInstance.finalitySigsSubmitted,
+			voteMetricsInstance.finalitySigsFailures,
+			voteMetricsInstance.finalitySigsBatchSize,
+			voteMetricsInstance.finalitySigsLatency,
+			voteMetricsInstance.lastVotedHeight,
+			voteMetricsInstance.pubRandCommits,
+			voteMetricsInstance.pubRandFailures,
+			voteMetricsInstance.pubRandCommitted,
+			voteMetricsInstance.pubRandRemaining,
+			voteMetricsInstance.txFees,
+		)
+	})
+	return voteMetricsInstance
+}
+
+// RecordFinalitySigs records a finality signatures submission, the errorClass is used if failed.
+func (vm *VoteMetrics) RecordFinalitySigs(fpBtcPk string, heights []uint64, start time.Time, errorClass string) {
+	vm.finalitySigsBatchSize.Observe(float64(len(heights)))
+	vm.finalitySigsLatency.Observe(time.Since(start).Seconds())
+
+	if errorClass != "" {
+		vm.finalitySigsFailures.WithLabelValues(fpBtcPk, errorClass).Inc()
+		return
+	}
+
+	vm.finalitySigsSubmitted.WithLabelValues(fpBtcPk).Add(float64(len(heights)))
+
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+
+	for _, h := range heights {
+		if h > vm.voted[fpBtcPk] {
+			vm.voted[fpBtcPk] = h
+		}
+	}
+	vm.lastVotedHeight.WithLabelValues(fpBtcPk).Set(float64(vm.voted[fpBtcPk]))
+	vm.updateRemaining(fpBtcPk)
+}
+
+// RecordPubRandCommit records a public randomness commit from the start height, the errorClass is used if failed.
+func (vm *VoteMetrics) RecordPubRandCommit(fpBtcPk string, startHeight, num uint64, errorClass string) {
+	if errorClass != "" {
+		vm.pubRandFailures.WithLabelValues(fpBtcPk, errorClass).Inc()
+		return
+	}
+
+	vm.pubRandCommits.WithLabelValues(fpBtcPk).Inc()
+	vm.RecordLastPubRandCommit(fpBtcPk, startHeight, num)
+}
+
+// RecordLastPubRandCommit records the public randomness committed from the start height, without counting a commit,
+// it is used to seed the runway by the last commit in the contract when the fp started.
+func (vm *VoteMetrics) RecordLastPubRandCommit(fpBtcPk string, startHeight, num uint64) {
+	if num == 0 {
+		return
+	}
+
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+
+	end := startHeight + num - 1
+	if end > vm.committed[fpBtcPk] {
+		vm.committed[fpBtcPk] = end
+	}
+	vm.pubRandCommitted.WithLabelValues(fpBtcPk).Set(float64(vm.committed[fpBtcPk]))
+	vm.updateRemaining(fpBtcPk)
+}
+
+func (vm *VoteMetrics) updateRemaining(fpBtcPk string) {
+	committed, ok := vm.committed[fpBtcPk]
+	if !ok {
+		// unknown until the last commit is queried or the first commit after started
+		return
+	}
+
+	remaining := 0.0
+	if voted := vm.voted[fpBtcPk]; committed > voted {
+		remaining = float64(committed - voted)
+	}
+	vm.pubRandRemaining.WithLabelValues(fpBtcPk).Set(remaining)
+}
+
+// PubRandRunway returns the blocks the committed public randomness can still vote of each fp,
+// the fps without the commit known are skipped.
+func (vm *VoteMetrics) PubRandRunway() map[string]uint64 {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+
+	res := make(map[string]uint64, len(vm.committed))
+	for fpBtcPk, committed := range vm.committed {
+		var remaining uint64
+		if voted := vm.voted[fpBtcPk]; committed > voted {
+			remaining = committed - voted
+		}
+		res[fpBtcPk] = remaining
+	}
+
+	return res
+}
+
+// RecordTxFee records the fee in bbn paid by a tx.
+func (vm *VoteMetrics) RecordTxFee(fpBtcPk, txType string, fee float64) {
+	vm.txFees.WithLabelValues(fpBtcPk, txType).Observe(fee)
+}
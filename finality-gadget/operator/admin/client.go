@@ -0,0 +1,157 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alt-research/blitz/finality-gadget/core/logging"
+)
+
+// Client is the client for the operator admin api.
+type Client struct {
+	httpClient *http.Client
+	baseUrl    string
+}
+
+func NewClient(listenAddress string) (*Client, error) {
+	network, address, err := ParseListenAddress(listenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &http.Transport{}
+	baseUrl := fmt.Sprintf("http://%s", address)
+	if network == "unix" {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", address)
+		}
+		baseUrl = "http://admin"
+	}
+
+	return &Client{
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   30 * time.Second,
+		},
+		baseUrl: baseUrl,
+	}, nil
+}
+
+func (c *Client) Status(ctx context.Context) (*Status, error) {
+	return c.do(ctx, http.MethodGet, PathStatus)
+}
+
+func (c *Client) Pause(ctx context.Context) (*Status, error) {
+	return c.do(ctx, http.MethodPost, PathPause)
+}
+
+func (c *Client) Resume(ctx context.Context) (*Status, error) {
+	return c.do(ctx, http.MethodPost, PathResume)
+}
+
+func (c *Client) RefreshBalance(ctx context.Context) (*Status, error) {
+	return c.do(ctx, http.MethodPost, PathRefreshBalance)
+}
+
+func (c *Client) ReloadConfig(ctx context.Context) (*Status, error) {
+	return c.do(ctx, http.MethodPost, PathReloadConfig)
+}
+
+// StartFp starts the finality provider instance by the btc pk.
+func (c *Client) StartFp(ctx context.Context, btcPk string) (*Status, error) {
+	var status Status
+	if err := c.call(ctx, http.MethodPost, PathStartFp, &FpRequest{BtcPk: btcPk}, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// StopFp stops the finality provider instance by the btc pk.
+func (c *Client) StopFp(ctx context.Context, btcPk string) (*Status, error) {
+	var status Status
+	if err := c.call(ctx, http.MethodPost, PathStopFp, &FpRequest{BtcPk: btcPk}, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// LogLevels returns the global and the module log levels.
+func (c *Client) LogLevels(ctx context.Context) (*logging.Levels, error) {
+	var levels logging.Levels
+	if err := c.call(ctx, http.MethodGet, PathLogLevel, nil, &levels); err != nil {
+		return nil, err
+	}
+	return &levels, nil
+}
+
+// SetLogLevel changes the level of the module, or the global level if the module is empty.
+func (c *Client) SetLogLevel(ctx context.Context, module, level string) (*logging.Levels, error) {
+	var levels logging.Levels
+	req := &SetLogLevelRequest{Module: module, Level: level}
+	if err := c.call(ctx, http.MethodPost, PathLogLevel, req, &levels); err != nil {
+		return nil, err
+	}
+	return &levels, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string) (*Status, error) {
+	var status Status
+	if err := c.call(ctx, method, path, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// call requests the admin api with the json body if in is not nil, and unmarshals the response into out.
+func (c *Client) call(ctx context.Context, method, path string, in, out any) error {
+	var reqBody io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return errors.Wrap(err, "marshal request failed")
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseUrl+path, reqBody)
+	if err != nil {
+		return errors.Wrap(err, "new request failed")
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "request admin api %s failed", path)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "read response failed")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+		if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error != "" {
+			return errors.Errorf("admin api %s failed: %s", path, errResp.Error)
+		}
+		return errors.Errorf("admin api %s failed with status %d", path, resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return errors.Wrap(err, "unmarshal response failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
+)
+
+const (
+	unixAddressPrefix = "unix://"
+
+	defaultStateFileName = "operator-state.json"
+)
+
+type Config struct {
+	// The listen address for admin api, can be a unix socket like `unix:///fpd/admin.sock`
+	// or a localhost address like `127.0.0.1:8291`, if empty, the admin api will not start.
+	ListenAddress string `yaml:"listen_address,omitempty"`
+	// The file to persist the operator state (e.g. paused), default is `operator-state.json`
+	// in the finality provider home path.
+	StateFile string `yaml:"state_file,omitempty"`
+}
+
+// StateFilePath returns the path to persist the operator state.
+func (c *Config) StateFilePath(homePath string) string {
+	if c.StateFile != "" {
+		return c.StateFile
+	}
+
+	return filepath.Join(homePath, defaultStateFileName)
+}
+
+// ParseListenAddress returns the network and address for listen.
+func ParseListenAddress(address string) (string, string, error) {
+	if address == "" {
+		return "", "", errors.New("the admin listen address is empty")
+	}
+
+	if strings.HasPrefix(address, unixAddressPrefix) {
+		return "unix", strings.TrimPrefix(address, unixAddressPrefix), nil
+	}
+
+	return "tcp", address, nil
+}
+
+func (c *Config) WithEnv() {
+	c.ListenAddress = utils.LookupEnvStr("FINALITY_GADGET_ADMIN_LISTEN_ADDRESS", c.ListenAddress)
+	c.StateFile = utils.LookupEnvStr("FINALITY_GADGET_ADMIN_STATE_FILE", c.StateFile)
+}
+
+func (c *Config) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.ListenAddress != "" {
+		network, address, err := ParseListenAddress(c.ListenAddress)
+		switch {
+		case err != nil:
+			errs.Add("listen_address", err)
+		case network == "unix" && address == "":
+			errs.Addf("listen_address", "no path for the unix socket")
+		case network == "tcp":
+			errs.Add("listen_address", utils.CheckHostPort(address))
+		}
+	}
+
+	return errs.Err()
+}
@@ -0,0 +1,279 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/alt-research/blitz/finality-gadget/core/logging"
+	"github.com/alt-research/blitz/finality-gadget/metrics"
+)
+
+const (
+	PathStatus         = "/status"
+	PathPause          = "/pause"
+	PathResume         = "/resume"
+	PathRefreshBalance = "/balance/refresh"
+	PathReloadConfig   = "/config/reload"
+	PathLogLevel       = "/log/level"
+	PathStartFp        = "/fp/start"
+	PathStopFp         = "/fp/stop"
+)
+
+// Backend is the operator which the admin api controls.
+type Backend interface {
+	// Pause pauses the finality signature submission, the public randomness will still be committed.
+	Pause() error
+	// Resume resumes the finality signature submission.
+	Resume() error
+	// Status returns the status of the operator.
+	Status(ctx context.Context) (*Status, error)
+	// RefreshBalance refreshes the balance metrics.
+	RefreshBalance(ctx context.Context) error
+	// ReloadConfig reloads the config file, only the hot reloadable fields are applied.
+	ReloadConfig(ctx context.Context) error
+	// StartFp starts the finality provider instance by the btc pk, it keeps running after the request.
+	StartFp(ctx context.Context, btcPk string) error
+	// StopFp stops the finality provider instance by the btc pk, the others keep running.
+	StopFp(ctx context.Context, btcPk string) error
+}
+
+type Status struct {
+	Paused bool `json:"paused"`
+	// Heads is the l2 head and the babylon finalized head looked up by the operator, nil if the lookup failed.
+	Heads             *metrics.FinalityHeads `json:"heads,omitempty"`
+	FinalityProviders []FpStatus             `json:"finality_providers"`
+}
+
+type FpStatus struct {
+	BtcPk           string   `json:"btc_pk"`
+	Running         bool     `json:"running"`
+	Status          string   `json:"status"`
+	LastVotedHeight uint64   `json:"last_voted_height"`
+	PendingHeights  []uint64 `json:"pending_heights"`
+	// PubRandRunway is the blocks the committed public randomness can still vote,
+	// nil before the first commit since the operator started.
+	PubRandRunway *uint64 `json:"pub_rand_runway,omitempty"`
+}
+
+// FpRequest is the finality provider to start or stop.
+type FpRequest struct {
+	BtcPk string `json:"btc_pk"`
+}
+
+// SetLogLevelRequest changes the level of the module, or the global level if the module is empty,
+// the level of the module is reset to the global one if the level is empty.
+type SetLogLevelRequest struct {
+	Module string `json:"module,omitempty"`
+	Level  string `json:"level"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server is the local admin api server for operator.
+type Server struct {
+	logger     *zap.Logger
+	backend    Backend
+	network    string
+	address    string
+	httpServer *http.Server
+}
+
+func NewServer(logger *zap.Logger, listenAddress string, backend Backend) (*Server, error) {
+	network, address, err := ParseListenAddress(listenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if network == "tcp" {
+		if err := checkLocalAddress(address); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &Server{
+		logger:  logger.With(zap.String("module", "admin")),
+		backend: backend,
+		network: network,
+		address: address,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(PathStatus, s.handleStatus)
+	mux.HandleFunc(PathPause, s.handlePost(func(ctx context.Context) error { return backend.Pause() }))
+	mux.HandleFunc(PathResume, s.handlePost(func(ctx context.Context) error { return backend.Resume() }))
+	mux.HandleFunc(PathRefreshBalance, s.handlePost(backend.RefreshBalance))
+	mux.HandleFunc(PathReloadConfig, s.handlePost(backend.ReloadConfig))
+	mux.HandleFunc(PathLogLevel, s.handleLogLevel)
+	mux.HandleFunc(PathStartFp, s.handleFp(backend.StartFp))
+	mux.HandleFunc(PathStopFp, s.handleFp(backend.StopFp))
+
+	s.httpServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s, nil
+}
+
+// Start listens the address and serves the admin api in a goroutine.
+func (s *Server) Start() error {
+	if s.network == "unix" {
+		// remove the socket left by the last run
+		if err := os.Remove(s.address); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to remove the old admin socket %s", s.address)
+		}
+	}
+
+	listener, err := net.Listen(s.network, s.address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen admin api on %s", s.address)
+	}
+
+	if s.network == "unix" {
+		if err := os.Chmod(s.address, 0o600); err != nil {
+			listener.Close()
+			return errors.Wrapf(err, "failed to chmod the admin socket %s", s.address)
+		}
+	}
+
+	go func() {
+		s.logger.Info("Admin server is starting", zap.String("network", s.network), zap.String("addr", s.address))
+		if err := s.httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("Admin server stopped by error", zap.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+// Stop gracefully shuts down the admin server.
+func (s *Server) Stop(ctx context.Context) {
+	s.logger.Info("Stopping admin server")
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		s.logger.Error("Admin server shutdown failed", zap.Error(err))
+	}
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJson(w, http.StatusMethodNotAllowed, &errorResponse{Error: "method not allowed"})
+		return
+	}
+
+	status, err := s.backend.Status(r.Context())
+	if err != nil {
+		s.logger.Error("get status failed", zap.Error(err))
+		writeJson(w, http.StatusInternalServerError, &errorResponse{Error: err.Error()})
+		return
+	}
+
+	writeJson(w, http.StatusOK, status)
+}
+
+func (s *Server) handlePost(action func(ctx context.Context) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJson(w, http.StatusMethodNotAllowed, &errorResponse{Error: "method not allowed"})
+			return
+		}
+
+		s.logger.Info("handle admin request", zap.String("path", r.URL.Path))
+		if err := action(r.Context()); err != nil {
+			s.logger.Error("admin request failed", zap.String("path", r.URL.Path), zap.Error(err))
+			writeJson(w, http.StatusInternalServerError, &errorResponse{Error: err.Error()})
+			return
+		}
+
+		status, err := s.backend.Status(r.Context())
+		if err != nil {
+			writeJson(w, http.StatusInternalServerError, &errorResponse{Error: err.Error()})
+			return
+		}
+
+		writeJson(w, http.StatusOK, status)
+	}
+}
+
+// handleFp starts or stops the finality provider in the request body.
+func (s *Server) handleFp(action func(ctx context.Context, btcPk string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeJson(w, http.StatusMethodNotAllowed, &errorResponse{Error: "method not allowed"})
+			return
+		}
+
+		var req FpRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJson(w, http.StatusBadRequest, &errorResponse{Error: fmt.Sprintf("invalid request: %v", err)})
+			return
+		}
+
+		if req.BtcPk == "" {
+			writeJson(w, http.StatusBadRequest, &errorResponse{Error: "the btc_pk is required"})
+			return
+		}
+
+		s.handlePost(func(ctx context.Context) error { return action(ctx, req.BtcPk) })(w, r)
+	}
+}
+
+// handleLogLevel returns the log levels by GET, and changes the level by POST,
+// the changes are reset to the config when it is reloaded.
+func (s *Server) handleLogLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req SetLogLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJson(w, http.StatusBadRequest, &errorResponse{Error: fmt.Sprintf("invalid request: %v", err)})
+			return
+		}
+
+		if err := logging.SetLevel(req.Module, req.Level); err != nil {
+			writeJson(w, http.StatusBadRequest, &errorResponse{Error: err.Error()})
+			return
+		}
+
+		s.logger.Info("log level changed", zap.String("target", req.Module), zap.String("level", req.Level))
+	default:
+		writeJson(w, http.StatusMethodNotAllowed, &errorResponse{Error: "method not allowed"})
+		return
+	}
+
+	writeJson(w, http.StatusOK, logging.CurrentLevels())
+}
+
+func writeJson(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// checkLocalAddress make sure the admin api only listen on the loopback address.
+func checkLocalAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Wrapf(err, "invalid admin listen address %s", address)
+	}
+
+	if host == "localhost" {
+		return nil
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsLoopback() {
+		return errors.Errorf("the admin api should listen on a loopback address, got %s", address)
+	}
+
+	return nil
+}
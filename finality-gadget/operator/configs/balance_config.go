@@ -0,0 +1,120 @@
+package configs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
+)
+
+const (
+	// DefaultBalanceDenom is the denom of the fees on babylon.
+	DefaultBalanceDenom = "ubbn"
+	// DefaultBalanceDecimals is the decimals from `ubbn` to `bbn`.
+	DefaultBalanceDecimals = 6
+	// SubmitterAccountName is the name of the submitter account monitored by default.
+	SubmitterAccountName = "submitter"
+
+	defaultBalanceInterval    = 5 * time.Minute
+	defaultBalanceSpendWindow = 24 * time.Hour
+	maxBalanceDecimals        = 18
+)
+
+type BalanceConfig struct {
+	// The interval to query the balances, default is 5m.
+	Interval time.Duration `yaml:"interval,omitempty"`
+	// The window of the spend observed to estimate the days left, default is 24h.
+	SpendWindow time.Duration `yaml:"spend_window,omitempty"`
+	// The accounts to monitor, default is the submitter address in `ubbn` without thresholds.
+	Accounts []BalanceAccountConfig `yaml:"accounts,omitempty"`
+}
+
+type BalanceAccountConfig struct {
+	// The name in the metrics and the alerts, like `submitter` or `fee_granter`, should be unique.
+	Name string `yaml:"name"`
+	// The bech32 address, the submitter address of the operator if empty.
+	Address string `yaml:"address,omitempty"`
+	// The denom to query, default is `ubbn`.
+	Denom string `yaml:"denom,omitempty"`
+	// The decimals from the denom to the unit of the balance and the thresholds, like 6 from `ubbn` to `bbn`,
+	// default is 6 for `ubbn` and 0 for the others.
+	Decimals uint `yaml:"decimals,omitempty"`
+	// Raise a warning alert if the balance is less than it, disabled if 0.
+	WarningThreshold float64 `yaml:"warning_threshold,omitempty"`
+	// Raise a critical alert if the balance is less than it, disabled if 0.
+	CriticalThreshold float64 `yaml:"critical_threshold,omitempty"`
+}
+
+// WithDefault fills the defaults of the unset fields.
+func (c *BalanceConfig) WithDefault() {
+	if c.Interval == 0 {
+		c.Interval = defaultBalanceInterval
+	}
+
+	if c.SpendWindow == 0 {
+		c.SpendWindow = defaultBalanceSpendWindow
+	}
+
+	if len(c.Accounts) == 0 {
+		c.Accounts = []BalanceAccountConfig{{Name: SubmitterAccountName}}
+	}
+
+	for i := range c.Accounts {
+		if c.Accounts[i].Denom == "" {
+			c.Accounts[i].Denom = DefaultBalanceDenom
+		}
+
+		if c.Accounts[i].Denom == DefaultBalanceDenom && c.Accounts[i].Decimals == 0 {
+			c.Accounts[i].Decimals = DefaultBalanceDecimals
+		}
+	}
+}
+
+func (c *BalanceConfig) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.Interval < 0 {
+		errs.Addf("interval", "should not be negative")
+	}
+
+	if c.SpendWindow < 0 {
+		errs.Addf("spend_window", "should not be negative")
+	}
+
+	names := make(map[string]struct{}, len(c.Accounts))
+	for i, account := range c.Accounts {
+		field := fmt.Sprintf("accounts[%d]", i)
+		if account.Name == "" {
+			errs.Addf(field+".name", "required")
+		} else if _, ok := names[account.Name]; ok {
+			errs.Addf(field+".name", "the account name %s is duplicated", account.Name)
+		}
+		names[account.Name] = struct{}{}
+
+		errs.Merge(field, account.Validate())
+	}
+
+	return errs.Err()
+}
+
+func (c *BalanceAccountConfig) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.Decimals > maxBalanceDecimals {
+		errs.Addf("decimals", "should not be more than %d", maxBalanceDecimals)
+	}
+
+	if c.WarningThreshold < 0 {
+		errs.Addf("warning_threshold", "should not be negative")
+	}
+
+	if c.CriticalThreshold < 0 {
+		errs.Addf("critical_threshold", "should not be negative")
+	}
+
+	if c.WarningThreshold != 0 && c.CriticalThreshold > c.WarningThreshold {
+		errs.Addf("critical_threshold", "should not be more than the warning threshold %v", c.WarningThreshold)
+	}
+
+	return errs.Err()
+}
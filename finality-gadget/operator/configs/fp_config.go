@@ -1 +1,53 @@
 package configs
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// AllStoredFps means start all the finality providers stored in the fp db.
+const AllStoredFps = "all"
+
+// FpPkList is the list of the BTC PKs of the finality providers, it can be
+// a single string (split by `,`) or a list of strings in yaml.
+type FpPkList []string
+
+func ParseFpPkList(s string) FpPkList {
+	res := make(FpPkList, 0, 1)
+	for _, pk := range strings.Split(s, ",") {
+		pk = strings.TrimSpace(pk)
+		if pk != "" {
+			res = append(res, pk)
+		}
+	}
+
+	return res
+}
+
+func (l *FpPkList) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		*l = ParseFpPkList(value.Value)
+	case yaml.SequenceNode:
+		var pks []string
+		if err := value.Decode(&pks); err != nil {
+			return errors.Wrap(err, "failed to decode btc pk list")
+		}
+		*l = ParseFpPkList(strings.Join(pks, ","))
+	default:
+		return errors.Errorf("btc_pk should be a string or a list, line %d", value.Line)
+	}
+
+	return nil
+}
+
+// IsAll returns true if need start all the stored finality providers.
+func (l FpPkList) IsAll() bool {
+	return len(l) == 1 && strings.EqualFold(l[0], AllStoredFps)
+}
+
+func (l FpPkList) String() string {
+	return strings.Join(l, ",")
+}
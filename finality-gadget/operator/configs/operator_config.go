@@ -1,11 +1,32 @@
 package configs
 
 import (
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alt-research/blitz/finality-gadget/alerting"
 	"github.com/alt-research/blitz/finality-gadget/client/eotsmanager"
 	"github.com/alt-research/blitz/finality-gadget/client/l2eth"
 	"github.com/alt-research/blitz/finality-gadget/core/configs"
+	"github.com/alt-research/blitz/finality-gadget/core/logging"
 	"github.com/alt-research/blitz/finality-gadget/core/utils"
 	"github.com/alt-research/blitz/finality-gadget/metrics"
+	"github.com/alt-research/blitz/finality-gadget/operator/admin"
+	"github.com/alt-research/blitz/finality-gadget/operator/ha"
+	"github.com/alt-research/blitz/finality-gadget/operator/processers"
+	"github.com/alt-research/blitz/finality-gadget/tracing"
+)
+
+const bip340PubKeyLen = 32
+
+const (
+	// OperatorModeNormal will sign and broadcast the votes and public randomness.
+	OperatorModeNormal = "normal"
+	// OperatorModeShadow will build and log the votes and public randomness, but never broadcast them.
+	OperatorModeShadow = "shadow"
 )
 
 type OperatorConfig struct {
@@ -13,12 +34,24 @@ type OperatorConfig struct {
 	Layer2            l2eth.Config          `yaml:"layer2,omitempty"`
 	Babylon           configs.BabylonConfig `yaml:"babylon,omitempty"`
 	EOTSManagerConfig eotsmanager.Config    `yaml:"eotsManager,omitempty"`
+	Logging           logging.Config        `yaml:"logging,omitempty"`
 	MetricsConfig     metrics.Config        `yaml:"metrics,omitempty"`
+	Tracing           tracing.Config        `yaml:"tracing,omitempty"`
+	Alerting          alerting.Config       `yaml:"alerting,omitempty" reload:"hot"`
+	Balance           BalanceConfig         `yaml:"balance,omitempty" reload:"hot"`
+	Processers        processers.Config     `yaml:"processers,omitempty"`
+	Admin             admin.Config          `yaml:"admin,omitempty"`
+	Shadow            ShadowConfig          `yaml:"shadow,omitempty" reload:"hot"`
+	HA                ha.Config             `yaml:"ha,omitempty"`
+
+	// The operator mode, `normal` or `shadow`, default is `normal`.
+	Mode string `yaml:"mode,omitempty"`
 
 	// fp home root path create by fpd.
 	FinalityProviderHomePath string `yaml:"finalityProviderHomePath,omitempty"`
-	// btc_pk is the BTC secp256k1 PK of the finality provider encoded in BIP-340 spec
-	BtcPk string `yaml:"btc_pk,omitempty"`
+	// btc_pk is the BTC secp256k1 PKs of the finality providers encoded in BIP-340 spec,
+	// can be a single PK, a list of PKs, or `all` to start all the stored finality providers
+	BtcPk FpPkList `yaml:"btc_pk,omitempty"`
 }
 
 // use the env config first for some keys
@@ -28,8 +61,94 @@ func (c *OperatorConfig) WithEnv() {
 	c.Babylon.WithEnv()
 	c.EOTSManagerConfig.WithEnv()
 	c.MetricsConfig.WithEnv()
+	c.Admin.WithEnv()
+	c.HA.WithEnv()
 
+	c.Mode = utils.LookupEnvStr("FINALITY_GADGET_OPERATOR_MODE", c.Mode)
 	c.FinalityProviderHomePath = utils.LookupEnvStr("FINALITY_PROVIDER_HOME_PATH", c.FinalityProviderHomePath)
-	c.BtcPk = utils.LookupEnvStr("FINALITY_PROVIDER_BTC_PK", c.BtcPk)
+	c.BtcPk = ParseFpPkList(utils.LookupEnvStr("FINALITY_PROVIDER_BTC_PK", c.BtcPk.String()))
+}
+
+func (c *OperatorConfig) Validate() error {
+	var errs utils.ConfigErrors
+
+	errs.Merge("common", c.Common.Validate())
+	errs.Merge("layer2", c.Layer2.Validate())
+	errs.Merge("babylon", c.Babylon.Validate())
+	errs.Merge("eotsManager", c.EOTSManagerConfig.Validate())
+	errs.Merge("logging", c.Logging.Validate())
+	errs.Merge("metrics", c.MetricsConfig.Validate())
+	errs.Merge("tracing", c.Tracing.Validate())
+	errs.Merge("alerting", c.Alerting.Validate())
+	errs.Merge("balance", c.Balance.Validate())
+	errs.Merge("processers", c.Processers.Validate())
+	errs.Merge("admin", c.Admin.Validate())
+	errs.Merge("shadow", c.Shadow.Validate())
+
+	// the defaults are filled when the elector is created
+	ha := c.HA
+	ha.WithDefault()
+	errs.Merge("ha", ha.Validate())
+
+	switch c.Mode {
+	case "", OperatorModeNormal, OperatorModeShadow:
+	default:
+		errs.Addf("mode", "unknown operator mode %s", c.Mode)
+	}
+
+	if c.Babylon.FinalityGadgetCfg.DBFilePath == "" {
+		errs.Addf("babylon.finality_gadget.dbfilepath", "required")
+	}
+
+	if c.FinalityProviderHomePath == "" {
+		errs.Addf("finalityProviderHomePath", "required")
+	}
+
+	if !c.BtcPk.IsAll() {
+		for _, pk := range c.BtcPk {
+			errs.Add("btc_pk", validateBtcPk(pk))
+		}
+	}
+
+	return errs.Err()
+}
+
+// validateBtcPk checks the pk is a hex BIP-340 public key.
+func validateBtcPk(pk string) error {
+	data, err := hex.DecodeString(strings.TrimPrefix(pk, "0x"))
+	if err != nil {
+		return errors.Wrapf(err, "invalid btc pk %s", pk)
+	}
+
+	if len(data) != bip340PubKeyLen {
+		return errors.Errorf("invalid btc pk %s, should be %d bytes", pk, bip340PubKeyLen)
+	}
+
+	return nil
+}
+
+// IsShadowMode returns true if the operator should not broadcast any tx.
+func (c *OperatorConfig) IsShadowMode() bool {
+	return c.Mode == OperatorModeShadow
+}
+
+type ShadowConfig struct {
+	// The delay before comparing the shadow vote with the chain, to wait for the live fp vote.
+	CompareDelay time.Duration `yaml:"compare_delay,omitempty"`
+	// The timeout to wait for the live fp vote, the shadow vote will be a mismatch after timeout.
+	CompareTimeout time.Duration `yaml:"compare_timeout,omitempty"`
+}
+
+func (c *ShadowConfig) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.CompareDelay < 0 {
+		errs.Addf("compare_delay", "should not be negative")
+	}
+
+	if c.CompareTimeout < 0 {
+		errs.Addf("compare_timeout", "should not be negative")
+	}
 
+	return errs.Err()
 }
@@ -0,0 +1,380 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	bbnclient "github.com/babylonlabs-io/babylon/v3/client/client"
+	bbntypes "github.com/babylonlabs-io/babylon/v3/types"
+	"github.com/babylonlabs-io/finality-gadget/btcclient"
+	"github.com/babylonlabs-io/finality-gadget/cwclient"
+	rollupfpcfg "github.com/babylonlabs-io/finality-provider/bsn/rollup/config"
+	"github.com/babylonlabs-io/finality-provider/finality-provider/store"
+
+	"github.com/alt-research/blitz/finality-gadget/client/eotsmanager"
+	"github.com/alt-research/blitz/finality-gadget/client/l2eth"
+	"github.com/alt-research/blitz/finality-gadget/operator/configs"
+	"github.com/alt-research/blitz/finality-gadget/operator/fp/controllers"
+)
+
+const mockBtcClientHost = "mock-btc-client"
+
+// Doctor runs the preflight checks for the operator deployment.
+type Doctor struct {
+	cfg    *configs.OperatorConfig
+	fpCfg  *rollupfpcfg.RollupFPConfig
+	db     kvdb.Backend
+	logger *zap.Logger
+
+	// the min balance of the submitter, in bbn
+	minBalance float64
+
+	bbnOnce   sync.Once
+	bbnClient *bbnclient.Client
+	bbnErr    error
+}
+
+func NewDoctor(
+	cfg *configs.OperatorConfig,
+	fpCfg *rollupfpcfg.RollupFPConfig,
+	db kvdb.Backend,
+	minBalance float64,
+	logger *zap.Logger,
+) *Doctor {
+	return &Doctor{
+		cfg:        cfg,
+		fpCfg:      fpCfg,
+		db:         db,
+		minBalance: minBalance,
+		logger:     logger,
+	}
+}
+
+// Checks returns all the checks, the finality provider checks are for each btc pk.
+func (d *Doctor) Checks() ([]Check, error) {
+	pks, err := d.fpPks()
+	if err != nil {
+		return nil, err
+	}
+
+	checks := []Check{
+		{Name: "l2", Run: d.checkL2},
+		{Name: "babylon", Run: d.checkBabylon},
+		{Name: "finality contract", Run: d.checkFinalityContract},
+	}
+
+	for _, pk := range pks {
+		pk := pk
+		short := pk
+		if len(short) > 16 {
+			short = short[:16]
+		}
+
+		checks = append(checks,
+			Check{
+				Name: fmt.Sprintf("consumer id (%s)", short),
+				Run:  func(ctx context.Context) (string, error) { return d.checkConsumerId(ctx, pk) },
+			},
+			Check{
+				Name: fmt.Sprintf("eots key (%s)", short),
+				Run:  func(ctx context.Context) (string, error) { return d.checkEotsKey(ctx, pk) },
+			},
+			Check{
+				Name: fmt.Sprintf("fp store (%s)", short),
+				Run:  func(ctx context.Context) (string, error) { return d.checkFpStore(ctx, pk) },
+			},
+		)
+	}
+
+	checks = append(checks,
+		Check{Name: "bitcoind", Run: d.checkBitcoind},
+		Check{Name: "submitter balance", Run: d.checkBalance},
+	)
+
+	return checks, nil
+}
+
+func (d *Doctor) Close() {
+	if d.bbnClient != nil && d.bbnClient.IsRunning() {
+		if err := d.bbnClient.Stop(); err != nil {
+			d.logger.Error("stop babylon client failed", zap.Error(err))
+		}
+	}
+}
+
+// fpPks returns the btc pks in config, or all the stored finality providers.
+func (d *Doctor) fpPks() ([]string, error) {
+	if len(d.cfg.BtcPk) != 0 && !d.cfg.BtcPk.IsAll() {
+		return d.cfg.BtcPk, nil
+	}
+
+	fpStore, err := store.NewFinalityProviderStore(d.db)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to initiate finality provider store")
+	}
+
+	storedFps, err := fpStore.GetAllStoredFinalityProviders()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get all stored finality providers")
+	}
+
+	if len(storedFps) == 0 {
+		return nil, errors.New("no btc_pk in config and no finality provider stored in the fp db")
+	}
+
+	res := make([]string, 0, len(storedFps))
+	for _, sfp := range storedFps {
+		res = append(res, bbntypes.NewBIP340PubKeyFromBTCPK(sfp.BtcPk).MarshalHex())
+	}
+
+	return res, nil
+}
+
+func (d *Doctor) babylon() (*bbnclient.Client, error) {
+	d.bbnOnce.Do(func() {
+		babylonConfig := d.fpCfg.GetBabylonConfig()
+		if err := babylonConfig.Validate(); err != nil {
+			d.bbnErr = errors.Wrap(err, "invalid config for Babylon client")
+			return
+		}
+
+		d.bbnClient, d.bbnErr = bbnclient.New(&babylonConfig, d.logger)
+		if d.bbnErr != nil {
+			d.bbnErr = errors.Wrap(d.bbnErr, "failed to create Babylon client")
+		}
+	})
+
+	return d.bbnClient, d.bbnErr
+}
+
+func (d *Doctor) checkL2(ctx context.Context) (string, error) {
+	// NewL2EthClient checks the chain id if it is set in config
+	l2Client, err := l2eth.NewL2EthClient(ctx, &d.cfg.Layer2)
+	if err != nil {
+		return "", err
+	}
+	defer l2Client.Close()
+
+	head, err := l2Client.BlockNumber(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the l2 head")
+	}
+
+	return fmt.Sprintf("chain id %d, head %d", d.cfg.Layer2.ChainId, head), nil
+}
+
+func (d *Doctor) checkBabylon(ctx context.Context) (string, error) {
+	bc, err := d.babylon()
+	if err != nil {
+		return "", err
+	}
+
+	status, err := bc.RPCClient.Status(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the babylon node status")
+	}
+
+	chainID := status.NodeInfo.Network
+	if expected := d.fpCfg.GetBabylonConfig().ChainID; chainID != expected {
+		return "", errors.Errorf("the chain id expected %s in fpd config, got %s", expected, chainID)
+	}
+
+	if expected := d.cfg.Babylon.FinalityGadgetCfg.BBNChainID; expected != "" && chainID != expected {
+		return "", errors.Errorf("the chain id expected %s in operator config, got %s", expected, chainID)
+	}
+
+	detail := fmt.Sprintf("chain id %s, height %d", chainID, status.SyncInfo.LatestBlockHeight)
+	if status.SyncInfo.CatchingUp {
+		detail += ", catching up"
+	}
+
+	return detail, nil
+}
+
+func (d *Doctor) checkFinalityContract(ctx context.Context) (string, error) {
+	bc, err := d.babylon()
+	if err != nil {
+		return "", err
+	}
+
+	address := d.fpCfg.FinalityContractAddress
+	if address == "" {
+		return "", errors.New("no finality contract address in fpd config")
+	}
+
+	req := wasmtypes.QueryContractInfoRequest{Address: address}
+	data, err := req.Marshal()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal request")
+	}
+
+	result, err := bc.RPCClient.ABCIQuery(ctx, "/cosmwasm.wasm.v1.Query/ContractInfo", data)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to query contract info")
+	}
+
+	if result.Response.Code != 0 {
+		return "", errors.Errorf("the finality contract %s not found: %s", address, result.Response.Log)
+	}
+
+	var info wasmtypes.QueryContractInfoResponse
+	if err := info.Unmarshal(result.Response.Value); err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal contract info")
+	}
+
+	enabled, err := cwclient.NewCosmWasmClient(bc.RPCClient, address).QueryIsEnabled()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to query is enabled")
+	}
+
+	if !enabled {
+		return "", errors.Errorf("the finality contract %s is not enabled", address)
+	}
+
+	return fmt.Sprintf("%s code id %d, enabled", address, info.CodeID), nil
+}
+
+func (d *Doctor) checkConsumerId(_ context.Context, pk string) (string, error) {
+	bc, err := d.babylon()
+	if err != nil {
+		return "", err
+	}
+
+	consumerId, err := cwclient.NewCosmWasmClient(bc.RPCClient, d.fpCfg.FinalityContractAddress).QueryConsumerId()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to query the consumer id from the finality contract")
+	}
+
+	resp, err := bc.FinalityProvider(pk)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to query the finality provider registration in babylon")
+	}
+
+	if bsnId := resp.FinalityProvider.BsnId; bsnId != consumerId {
+		return "", errors.Errorf("the finality provider is registered to %s, but the finality contract is for %s", bsnId, consumerId)
+	}
+
+	return fmt.Sprintf("registered to %s", consumerId), nil
+}
+
+func (d *Doctor) checkEotsKey(_ context.Context, pk string) (string, error) {
+	btcPk, err := bbntypes.NewBIP340PubKeyFromHex(pk)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid btc pk %s", pk)
+	}
+
+	// not sign by the key, the signing would be recorded by the protection db and the audit log
+	if err := eotsmanager.CheckKey(d.logger, d.cfg.EOTSManagerConfig, *btcPk); err != nil {
+		return "", err
+	}
+
+	if d.cfg.EOTSManagerConfig.IsLocal() {
+		return "found in local key store", nil
+	}
+
+	return fmt.Sprintf("found in %s", strings.Join(d.cfg.EOTSManagerConfig.Endpoints(), ",")), nil
+}
+
+func (d *Doctor) checkFpStore(_ context.Context, pk string) (string, error) {
+	btcPk, err := bbntypes.NewBIP340PubKeyFromHex(pk)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid btc pk %s", pk)
+	}
+
+	fpStore, err := store.NewFinalityProviderStore(d.db)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to initiate finality provider store")
+	}
+
+	sfp, err := fpStore.GetFinalityProvider(btcPk.MustToBTCPK())
+	if err != nil {
+		return "", errors.Wrap(err, "the finality provider not found in the fp db, use `fps restore` to restore it")
+	}
+
+	return fmt.Sprintf("chain id %s, status %s", sfp.ChainID, sfp.Status.String()), nil
+}
+
+func (d *Doctor) checkBitcoind(ctx context.Context) (string, error) {
+	fgCfg := d.cfg.Babylon.FinalityGadgetCfg
+	if fgCfg.BitcoinRPCHost == "" || fgCfg.BitcoinRPCHost == mockBtcClientHost {
+		return "", skip("no bitcoin rpc host in config")
+	}
+
+	btcConfig := btcclient.DefaultBTCConfig()
+	btcConfig.RPCHost = fgCfg.BitcoinRPCHost
+	if fgCfg.BitcoinRPCUser != "" && fgCfg.BitcoinRPCPass != "" {
+		btcConfig.RPCUser = fgCfg.BitcoinRPCUser
+		btcConfig.RPCPass = fgCfg.BitcoinRPCPass
+	}
+	btcConfig.DisableTLS = fgCfg.BitcoinDisableTLS
+
+	// use the rpc client directly as the one of the finality gadget can not be closed
+	btcClient, err := rpcclient.New(&rpcclient.ConnConfig{
+		Host:         btcConfig.RPCHost,
+		User:         btcConfig.RPCUser,
+		Pass:         btcConfig.RPCPass,
+		DisableTLS:   btcConfig.DisableTLS,
+		HTTPPostMode: true,
+	}, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create bitcoin client")
+	}
+	// stops the retries of the request
+	defer btcClient.Shutdown()
+
+	// the response is not sent if the client shutdown while retrying, so wait it with the ctx
+	future := btcClient.GetBlockCountAsync()
+
+	var resp *rpcclient.Response
+	select {
+	case resp = <-future:
+	case <-ctx.Done():
+		return "", errors.Wrap(ctx.Err(), "failed to get the block count")
+	}
+
+	// put the response back to parse it by the future
+	parsed := make(chan *rpcclient.Response, 1)
+	parsed <- resp
+
+	count, err := rpcclient.FutureGetBlockCountResult(parsed).Receive()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the block count")
+	}
+
+	return fmt.Sprintf("%s height %d", fgCfg.BitcoinRPCHost, count), nil
+}
+
+func (d *Doctor) checkBalance(ctx context.Context) (string, error) {
+	if d.cfg.IsShadowMode() {
+		return "", skip("no tx submitted in shadow mode")
+	}
+
+	bc, err := d.babylon()
+	if err != nil {
+		return "", err
+	}
+
+	address, err := bc.GetAddr()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the submitter address")
+	}
+
+	balance, err := controllers.QueryBalance(ctx, bc, address, configs.DefaultBalanceDenom, configs.DefaultBalanceDecimals)
+	if err != nil {
+		return "", err
+	}
+
+	if balance < d.minBalance {
+		return "", errors.Errorf("the balance of %s is %v bbn, less than %v bbn", address, balance, d.minBalance)
+	}
+
+	return fmt.Sprintf("%s %v bbn", address, balance), nil
+}
@@ -0,0 +1,110 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	StatusPass = "PASS"
+	StatusFail = "FAIL"
+	StatusSkip = "SKIP"
+)
+
+// errSkip makes the check skipped, for the check which is not needed by the config.
+type errSkip struct {
+	reason string
+}
+
+func (e *errSkip) Error() string {
+	return e.reason
+}
+
+func skip(format string, args ...any) error {
+	return &errSkip{reason: fmt.Sprintf(format, args...)}
+}
+
+// Check is a preflight check, it returns the detail if passed.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) (string, error)
+}
+
+type Result struct {
+	Name   string
+	Status string
+	Detail string
+}
+
+// Run runs the checks one by one, each check will be failed if not finished in the timeout.
+// The checks timed out are waited to stop before return, so the clients can be closed after it.
+func Run(ctx context.Context, checks []Check, timeout time.Duration) []Result {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	res := make([]Result, 0, len(checks))
+	for _, c := range checks {
+		res = append(res, runCheck(ctx, c, timeout, &wg))
+	}
+
+	return res
+}
+
+func runCheck(ctx context.Context, c Check, timeout time.Duration, wg *sync.WaitGroup) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type output struct {
+		detail string
+		err    error
+	}
+
+	// some clients do not use the ctx, so not wait for them to report the timeout,
+	// they are stopped by the timeout of their own requests
+	done := make(chan output, 1)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		detail, err := c.Run(ctx)
+		done <- output{detail, err}
+	}()
+
+	var out output
+	select {
+	case out = <-done:
+	case <-ctx.Done():
+		out.err = fmt.Errorf("check not finished: %w", ctx.Err())
+	}
+
+	if out.err != nil {
+		if s, ok := out.err.(*errSkip); ok {
+			return Result{Name: c.Name, Status: StatusSkip, Detail: s.reason}
+		}
+
+		return Result{Name: c.Name, Status: StatusFail, Detail: out.err.Error()}
+	}
+
+	return Result{Name: c.Name, Status: StatusPass, Detail: out.detail}
+}
+
+// PrintTable prints the results as a table, returns the number of the failed checks.
+func PrintTable(w io.Writer, results []Result) int {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tDETAIL")
+
+	failed := 0
+	for _, r := range results {
+		if r.Status == StatusFail {
+			failed++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Name, r.Status, r.Detail)
+	}
+	tw.Flush()
+
+	return failed
+}
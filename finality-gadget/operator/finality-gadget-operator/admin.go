@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/urfave/cli"
+
+	"github.com/alt-research/blitz/finality-gadget/core/logging"
+	"github.com/alt-research/blitz/finality-gadget/operator/admin"
+)
+
+var adminAddressFlag = cli.StringFlag{
+	Name:   "admin-address",
+	Usage:  "The admin api address of the operator, default use `admin.listen_address` in config",
+	EnvVar: "FINALITY_GADGET_ADMIN_ADDRESS",
+}
+
+var logModuleFlag = cli.StringFlag{
+	Name:  "module",
+	Usage: "The module to change the log level, like `l2BlockHandler`, default change the global level",
+}
+
+var logResetFlag = cli.BoolFlag{
+	Name:  "reset",
+	Usage: "Reset the log level of the module to the global level",
+}
+
+var adminCommand = cli.Command{
+	Name:  "admin",
+	Usage: "subcommand for the running operator admin api",
+	Subcommands: []cli.Command{
+		{
+			Name:   "status",
+			Usage:  "show the status of each finality provider",
+			Flags:  []cli.Flag{adminAddressFlag},
+			Action: adminAction(func(ctx context.Context, c *admin.Client) (*admin.Status, error) { return c.Status(ctx) }),
+		},
+		{
+			Name:   "pause",
+			Usage:  "pause the finality signature submission, the public randomness will still be committed",
+			Flags:  []cli.Flag{adminAddressFlag},
+			Action: adminAction(func(ctx context.Context, c *admin.Client) (*admin.Status, error) { return c.Pause(ctx) }),
+		},
+		{
+			Name:   "resume",
+			Usage:  "resume the finality signature submission",
+			Flags:  []cli.Flag{adminAddressFlag},
+			Action: adminAction(func(ctx context.Context, c *admin.Client) (*admin.Status, error) { return c.Resume(ctx) }),
+		},
+		{
+			Name:   "refresh-balance",
+			Usage:  "force refresh the balance metrics",
+			Flags:  []cli.Flag{adminAddressFlag},
+			Action: adminAction(func(ctx context.Context, c *admin.Client) (*admin.Status, error) { return c.RefreshBalance(ctx) }),
+		},
+		{
+			Name:   "reload-config",
+			Usage:  "reload the config file, only the hot reloadable fields are applied, same as SIGHUP",
+			Flags:  []cli.Flag{adminAddressFlag},
+			Action: adminAction(func(ctx context.Context, c *admin.Client) (*admin.Status, error) { return c.ReloadConfig(ctx) }),
+		},
+		{
+			Name:      "start-fp",
+			Usage:     "start the finality provider instance, it is not persisted, the config `btc_pk` is used after restart",
+			ArgsUsage: "<btc pk>",
+			Flags:     []cli.Flag{adminAddressFlag},
+			Action: fpAction(func(ctx context.Context, c *admin.Client, btcPk string) (*admin.Status, error) {
+				return c.StartFp(ctx, btcPk)
+			}),
+		},
+		{
+			Name:      "stop-fp",
+			Usage:     "stop the finality provider instance, the others keep running",
+			ArgsUsage: "<btc pk>",
+			Flags:     []cli.Flag{adminAddressFlag},
+			Action: fpAction(func(ctx context.Context, c *admin.Client, btcPk string) (*admin.Status, error) {
+				return c.StopFp(ctx, btcPk)
+			}),
+		},
+		{
+			Name:      "log-level",
+			Usage:     "show the log levels, or change the level of a module or the global level, reset when the config reloaded",
+			ArgsUsage: "[debug|info|warn|error]",
+			Flags:     []cli.Flag{adminAddressFlag, logModuleFlag, logResetFlag},
+			Action:    logLevelAction,
+		},
+	},
+}
+
+func fpAction(action func(ctx context.Context, c *admin.Client, btcPk string) (*admin.Status, error)) func(cliCtx *cli.Context) error {
+	return func(cliCtx *cli.Context) error {
+		btcPk := cliCtx.Args().First()
+		if btcPk == "" {
+			return fmt.Errorf("the btc pk of the finality provider is required")
+		}
+
+		return adminAction(func(ctx context.Context, c *admin.Client) (*admin.Status, error) {
+			return action(ctx, c, btcPk)
+		})(cliCtx)
+	}
+}
+
+func logLevelAction(cliCtx *cli.Context) error {
+	module := cliCtx.String(logModuleFlag.Name)
+	level := cliCtx.Args().First()
+	reset := cliCtx.Bool(logResetFlag.Name)
+
+	switch {
+	case reset && module == "":
+		return fmt.Errorf("the --%s is required by --%s", logModuleFlag.Name, logResetFlag.Name)
+	case reset && level != "":
+		return fmt.Errorf("the level should not be set with --%s", logResetFlag.Name)
+	case !reset && level == "" && module != "":
+		return fmt.Errorf("the level is required to change the level of %s", module)
+	}
+
+	return adminAction(func(ctx context.Context, c *admin.Client) (*logging.Levels, error) {
+		if level == "" && !reset {
+			return c.LogLevels(ctx)
+		}
+		return c.SetLogLevel(ctx, module, level)
+	})(cliCtx)
+}
+
+func adminAction[T any](action func(ctx context.Context, c *admin.Client) (T, error)) func(cliCtx *cli.Context) error {
+	return func(cliCtx *cli.Context) error {
+		address := cliCtx.String(adminAddressFlag.Name)
+		if address == "" {
+			config, err := loadConfig(cliCtx)
+			if err != nil {
+				return err
+			}
+			address = config.Admin.ListenAddress
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		client, err := admin.NewClient(address)
+		if err != nil {
+			return fmt.Errorf("failed to create admin client: %w", err)
+		}
+
+		res, err := action(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		out, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the response: %w", err)
+		}
+
+		fmt.Println(string(out))
+
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+
+	rollupfpcfg "github.com/babylonlabs-io/finality-provider/bsn/rollup/config"
+
+	"github.com/alt-research/blitz/finality-gadget/alerting"
+	"github.com/alt-research/blitz/finality-gadget/core/logging"
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
+	"github.com/alt-research/blitz/finality-gadget/operator/configs"
+)
+
+var configCommand = utils.NewConfigCommand(defaultConfigPath, func(cliCtx *cli.Context) (any, error) {
+	config, err := loadConfig(cliCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the fpd config will be validated when loaded
+	if _, err := rollupfpcfg.LoadConfig(config.FinalityProviderHomePath); err != nil {
+		return nil, fmt.Errorf("invalid fpd config in %s: %w", config.FinalityProviderHomePath, err)
+	}
+
+	return config, nil
+})
+
+var alertsCommand = alerting.NewCommand(func(cliCtx *cli.Context) (*alerting.Detector, error) {
+	config, err := loadConfig(cliCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	logger, err := newLogger(cliCtx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDetector(cliCtx, config, logger.Inner()), nil
+})
+
+// loadConfig reads the config with env and validates it.
+func loadConfig(cliCtx *cli.Context) (*configs.OperatorConfig, error) {
+	var config configs.OperatorConfig
+	if err := utils.ReadConfig(cliCtx, defaultConfigPath, &config); err != nil {
+		return nil, fmt.Errorf("read config failed: %w", err)
+	}
+	config.WithEnv()
+
+	// the uniform env names override the legacy ones
+	if err := utils.ApplyEnv(utils.EnvPrefix, &config); err != nil {
+		return nil, fmt.Errorf("invalid env: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &config, nil
+}
+
+// newLogger creates the root logger of the process, the components derive their loggers from it.
+func newLogger(cliCtx *cli.Context, config *configs.OperatorConfig) (*logging.ZapLogger, error) {
+	logger, err := logging.New(&config.Logging, config.Common.Production,
+		logging.ServiceFields(cliCtx.App.Name, config.Layer2.ChainId)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	return logger, nil
+}
+
+// newDetector creates the stall detector by the alerting config, the rules are added by the caller.
+func newDetector(cliCtx *cli.Context, config *configs.OperatorConfig, zaplogger *zap.Logger) *alerting.Detector {
+	return alerting.NewDetector(zaplogger, &config.Alerting, alerting.Labels(cliCtx.App.Name, config.Layer2.ChainId))
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/alt-research/blitz/finality-gadget/operator/doctor"
+)
+
+var (
+	doctorMinBalanceFlag = cli.Float64Flag{
+		Name:   "min-balance",
+		Usage:  "the min balance of the submitter in bbn",
+		Value:  1,
+		EnvVar: "FINALITY_GADGET_DOCTOR_MIN_BALANCE",
+	}
+	doctorTimeoutFlag = cli.DurationFlag{
+		Name:   "timeout",
+		Usage:  "the timeout of each check",
+		Value:  30 * time.Second,
+		EnvVar: "FINALITY_GADGET_DOCTOR_TIMEOUT",
+	}
+)
+
+var doctorCommand = cli.Command{
+	Name:   "doctor",
+	Usage:  "run the preflight checks for the config, exit with non-zero if any check failed",
+	Flags:  []cli.Flag{doctorMinBalanceFlag, doctorTimeoutFlag},
+	Action: doctorAction,
+}
+
+func doctorAction(cliCtx *cli.Context) error {
+	config, err := loadConfig(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger, err := newLogger(cliCtx, config)
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+	zapLogger := logger.Inner()
+
+	fpConfig, dbBackend, err := newAppParams(ctx, config)
+	if err != nil {
+		return fmt.Errorf("failed to create params for app: %w", err)
+	}
+	defer dbBackend.Close()
+
+	d := doctor.NewDoctor(config, fpConfig, dbBackend, cliCtx.Float64(doctorMinBalanceFlag.Name), zapLogger)
+	defer d.Close()
+
+	checks, err := d.Checks()
+	if err != nil {
+		return err
+	}
+
+	results := doctor.Run(ctx, checks, cliCtx.Duration(doctorTimeoutFlag.Name))
+	if failed := doctor.PrintTable(os.Stdout, results); failed != 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+
+	return nil
+}
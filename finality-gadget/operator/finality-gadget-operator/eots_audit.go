@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli"
+
+	"github.com/alt-research/blitz/finality-gadget/client/eotsmanager/audit"
+)
+
+var (
+	auditFromFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "the first seq of the entries to export",
+		Value: 1,
+	}
+	auditToFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "the last seq of the entries to export, 0 means to the end",
+	}
+)
+
+var eotsAuditCommand = cli.Command{
+	Name:  "eots-audit",
+	Usage: "subcommand for the EOTS signing audit log",
+	Subcommands: []cli.Command{
+		{
+			Name:   "verify",
+			Usage:  "verify the hash chain of the audit log",
+			Action: eotsAuditVerify,
+		},
+		{
+			Name:      "export",
+			Usage:     "export the entries in the seq range to the file, or stdout if no file",
+			ArgsUsage: "[file]",
+			Flags:     []cli.Flag{auditFromFlag, auditToFlag},
+			Action:    eotsAuditExport,
+		},
+	},
+}
+
+func openEotsAuditLog(cliCtx *cli.Context) (*os.File, error) {
+	config, err := loadConfig(cliCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	path := config.EOTSManagerConfig.AuditLogFilePath(config.FinalityProviderHomePath)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	return file, nil
+}
+
+func eotsAuditVerify(cliCtx *cli.Context) error {
+	file, err := openEotsAuditLog(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	count, err := audit.Verify(file)
+	if err != nil {
+		return fmt.Errorf("the audit log is broken after %d valid entries: %w", count, err)
+	}
+
+	fmt.Printf("verified %d entries from %s\n", count, file.Name())
+
+	return nil
+}
+
+func eotsAuditExport(cliCtx *cli.Context) error {
+	from, to := cliCtx.Uint64(auditFromFlag.Name), cliCtx.Uint64(auditToFlag.Name)
+	if to != 0 && to < from {
+		return fmt.Errorf("the --to %d should not be less than --from %d", to, from)
+	}
+
+	file, err := openEotsAuditLog(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var w io.Writer = os.Stdout
+	if path := cliCtx.Args().Get(0); path != "" {
+		out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		defer out.Close()
+
+		w = out
+	}
+
+	count, err := audit.Export(file, w, from, to)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d entries\n", count)
+
+	return nil
+}
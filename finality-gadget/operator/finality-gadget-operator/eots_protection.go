@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli"
+
+	"github.com/alt-research/blitz/finality-gadget/client/eotsmanager/protection"
+)
+
+var eotsProtectionCommand = cli.Command{
+	Name:  "eots-protection",
+	Usage: "subcommand for the EOTS slashing protection db, the operator should be stopped before using it",
+	Subcommands: []cli.Command{
+		{
+			Name:      "export",
+			Usage:     "export the signing history to the file, or stdout if no file",
+			ArgsUsage: "[file]",
+			Action:    eotsProtectionExport,
+		},
+		{
+			Name:      "import",
+			Usage:     "import the signing history from the file, nothing will be imported if conflict with the local history",
+			ArgsUsage: "<file>",
+			Action:    eotsProtectionImport,
+		},
+	},
+}
+
+func openEotsProtectionDB(cliCtx *cli.Context) (*protection.DB, error) {
+	config, err := loadConfig(cliCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return protection.Open(config.EOTSManagerConfig.ProtectionDBFilePath(config.FinalityProviderHomePath))
+}
+
+func eotsProtectionExport(cliCtx *cli.Context) error {
+	db, err := openEotsProtectionDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var w io.Writer = os.Stdout
+	if path := cliCtx.Args().Get(0); path != "" {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		defer file.Close()
+
+		w = file
+	}
+
+	return db.Export(w)
+}
+
+func eotsProtectionImport(cliCtx *cli.Context) error {
+	path := cliCtx.Args().Get(0)
+	if path == "" {
+		return fmt.Errorf("no file to import")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	db, err := openEotsProtectionDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	count, err := db.Import(file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("imported %d records from %s\n", count, path)
+
+	return nil
+}
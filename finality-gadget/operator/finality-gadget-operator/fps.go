@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
 	"strconv"
@@ -11,28 +10,24 @@ import (
 
 	"github.com/urfave/cli"
 
-	"github.com/alt-research/blitz/finality-gadget/core/logging"
-	"github.com/alt-research/blitz/finality-gadget/core/utils"
-	"github.com/alt-research/blitz/finality-gadget/operator/configs"
 	"github.com/alt-research/blitz/finality-gadget/operator/fp"
 )
 
 func fpsRestore(cliCtx *cli.Context) error {
-	var config configs.OperatorConfig
-	if err := utils.ReadConfig(cliCtx, defaultConfigPath, &config); err != nil {
-		log.Fatalf("read config failed by %v", err)
+	config, err := loadConfig(cliCtx)
+	if err != nil {
 		return err
 	}
-	config.WithEnv()
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	zapLogger, err := logging.NewZapLoggerInner(logging.NewLogLevel(config.Common.Production))
+	logger, err := newLogger(cliCtx, config)
 	if err != nil {
-		log.Fatalf("new logger failed by %v", err)
 		return err
 	}
+	defer logger.Sync()
+	zapLogger := logger.Inner()
 
 	keyName := cliCtx.Args().Get(0)
 	fpBtcPk := cliCtx.Args().Get(1)
@@ -41,7 +36,7 @@ func fpsRestore(cliCtx *cli.Context) error {
 
 	zapLogger.Sugar().Infof("fp btc pk %v in %v", fpBtcPk, chainId)
 
-	fpConfig, dbBackend, err := newAppParams(ctx, &config)
+	fpConfig, dbBackend, err := newAppParams(ctx, config)
 	if err != nil {
 		return fmt.Errorf("failed to create params for app: %w", err)
 	}
@@ -55,23 +50,22 @@ func fpsRestore(cliCtx *cli.Context) error {
 }
 
 func fpsShow(cliCtx *cli.Context) error {
-	var config configs.OperatorConfig
-	if err := utils.ReadConfig(cliCtx, defaultConfigPath, &config); err != nil {
-		log.Fatalf("read config failed by %v", err)
+	config, err := loadConfig(cliCtx)
+	if err != nil {
 		return err
 	}
-	config.WithEnv()
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	zapLogger, err := logging.NewZapLoggerInner(logging.NewLogLevel(config.Common.Production))
+	logger, err := newLogger(cliCtx, config)
 	if err != nil {
-		log.Fatalf("new logger failed by %v", err)
 		return err
 	}
+	defer logger.Sync()
+	zapLogger := logger.Inner()
 
-	fpConfig, dbBackend, err := newAppParams(ctx, &config)
+	fpConfig, dbBackend, err := newAppParams(ctx, config)
 	if err != nil {
 		return fmt.Errorf("failed to create params for app: %w", err)
 	}
@@ -39,6 +39,12 @@ func main() {
 				},
 			},
 		},
+		adminCommand,
+		eotsProtectionCommand,
+		eotsAuditCommand,
+		doctorCommand,
+		configCommand,
+		alertsCommand,
 	}
 	err := app.Run(os.Args)
 	if err != nil {
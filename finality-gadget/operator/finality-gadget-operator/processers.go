@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/alt-research/blitz/finality-gadget/client/l2eth"
+	"github.com/alt-research/blitz/finality-gadget/core/logging"
+	"github.com/alt-research/blitz/finality-gadget/operator"
+	"github.com/alt-research/blitz/finality-gadget/operator/configs"
+	"github.com/alt-research/blitz/finality-gadget/operator/processers"
+	"github.com/alt-research/blitz/finality-gadget/rpc/provider"
+)
+
+// startBlockProcessers starts the l2 block handler with the built-in processers,
+// returns a func to wait the handler stopped and close the processers.
+func startBlockProcessers(
+	ctx context.Context,
+	config *configs.OperatorConfig,
+	logger logging.Logger,
+) (func(), error) {
+	if config.Processers.IsEmpty() {
+		return func() {}, nil
+	}
+
+	l2Client, err := l2eth.NewL2EthClient(ctx, &config.Layer2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create l2 eth client for processers: %w", err)
+	}
+
+	var querier processers.IFinalizedQuerier
+	if config.Processers.NeedFinalized() {
+		finalizedStateProvider, err := provider.NewFinalizedStateProvider(ctx, config, logger.Inner())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create finalized state provider for processers: %w", err)
+		}
+		querier = finalizedStateProvider
+	}
+
+	ps, err := processers.NewProcessersFromConfig(ctx, logger, &config.Processers, config.Layer2.ChainId, querier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create processers: %w", err)
+	}
+
+	current, err := l2Client.BlockNumber(ctx)
+	if err != nil {
+		ps.Close()
+		return nil, fmt.Errorf("failed to get current l2 block number: %w", err)
+	}
+
+	latest := uint64(0)
+	if current > config.Layer2.BackHeightCount {
+		latest = current - config.Layer2.BackHeightCount
+	}
+
+	handler := operator.NewL2BlockHandler(ctx, logger, l2Client)
+	handler.WithLatestBlock(latest, common.Hash{})
+	ps.Register(handler)
+	handler.Start(ctx)
+
+	return func() {
+		handler.Wait()
+		ps.Close()
+	}, nil
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
 	"syscall"
@@ -11,29 +10,31 @@ import (
 	"github.com/carlmjohnson/versioninfo"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/urfave/cli"
+	"go.uber.org/zap"
 
 	rollupfpcfg "github.com/babylonlabs-io/finality-provider/bsn/rollup/config"
 
+	"github.com/alt-research/blitz/finality-gadget/alerting"
 	"github.com/alt-research/blitz/finality-gadget/core/logging"
 	"github.com/alt-research/blitz/finality-gadget/core/utils"
 	"github.com/alt-research/blitz/finality-gadget/metrics"
+	"github.com/alt-research/blitz/finality-gadget/operator/admin"
 	"github.com/alt-research/blitz/finality-gadget/operator/configs"
 	"github.com/alt-research/blitz/finality-gadget/operator/fp"
+	"github.com/alt-research/blitz/finality-gadget/tracing"
 )
 
 func finalityProvider(cliCtx *cli.Context) error {
-	var config configs.OperatorConfig
-	if err := utils.ReadConfig(cliCtx, defaultConfigPath, &config); err != nil {
-		log.Fatalf("read config failed by %v", err)
+	config, err := loadConfig(cliCtx)
+	if err != nil {
 		return err
 	}
-	config.WithEnv()
 
-	logger, err := logging.NewZapLogger(logging.NewLogLevel(config.Common.Production))
+	logger, err := newLogger(cliCtx, config)
 	if err != nil {
-		log.Fatalf("new logger failed by %v", err)
 		return err
 	}
+	defer logger.Sync()
 
 	logger.Infof("fp operator version %v", versioninfo.Short())
 
@@ -42,23 +43,70 @@ func finalityProvider(cliCtx *cli.Context) error {
 
 	logger.Info("NewFinalityProviderAppFromConfig")
 
-	zaplogger, err := logging.NewZapLoggerInner(logging.NewLogLevel(config.Common.Production))
+	zaplogger := logger.Inner()
+	metricsServer, err := metrics.Start(&config.MetricsConfig, zaplogger)
 	if err != nil {
-		log.Fatalf("new logger failed by %v", err)
-		return err
+		return fmt.Errorf("failed to start metrics server: %w", err)
+	}
+	defer metricsServer.Stop(context.Background())
+
+	shutdownTracing, err := tracing.Setup(ctx, &config.Tracing, cliCtx.App.Name)
+	if err != nil {
+		return fmt.Errorf("failed to setup tracing: %w", err)
 	}
-	promAddr, err := config.MetricsConfig.Address()
+	defer shutdownTracing(context.Background())
+
+	waitProcessers, err := startBlockProcessers(ctx, config, logger)
 	if err != nil {
-		return fmt.Errorf("failed to get prometheus address: %w", err)
+		return fmt.Errorf("failed to start block processers: %w", err)
 	}
-	metricsServer := metrics.Start(promAddr, zaplogger)
-	defer metricsServer.Stop(context.Background())
+	defer waitProcessers()
 
-	app, err := newApp(ctx, &config)
+	app, err := newApp(ctx, config, zaplogger)
 	if err != nil {
 		return fmt.Errorf("failed to create NewFinalityProviderAppFromConfig for app: %w", err)
 	}
 
+	// reload the hot reloadable config by SIGHUP or the admin api
+	reloader := utils.NewConfigReloader(zaplogger, config, func() (*configs.OperatorConfig, error) {
+		return loadConfig(cliCtx)
+	})
+	reloader.OnReload(func(cfg *configs.OperatorConfig) {
+		if err := logging.ApplyLevels(&cfg.Logging, cfg.Common.Production); err != nil {
+			zaplogger.Error("failed to apply the log levels", zap.Error(err))
+		}
+	})
+	app.SetConfigReloader(reloader)
+	reloader.WatchSignal(ctx)
+
+	if config.Admin.ListenAddress != "" {
+		adminServer, err := admin.NewServer(zaplogger, config.Admin.ListenAddress, app)
+		if err != nil {
+			return fmt.Errorf("failed to create admin server: %w", err)
+		}
+
+		if err := adminServer.Start(); err != nil {
+			return fmt.Errorf("failed to start admin server: %w", err)
+		}
+		defer adminServer.Stop(context.Background())
+	}
+
+	// the detector is started without rules too, the rules may be set by reload
+	detector := newDetector(cliCtx, config, zaplogger)
+	addRules := func(cfg *configs.OperatorConfig) func(d *alerting.Detector) {
+		return func(d *alerting.Detector) {
+			d.AddOperatorRules(&cfg.Alerting.Rules)
+			if rpcServer := app.RpcServer(); rpcServer != nil {
+				d.AddFinalityRules(&cfg.Alerting.Rules, rpcServer.FinalizedStateProvider())
+			}
+		}
+	}
+	addRules(config)(detector)
+	reloader.OnReload(func(cfg *configs.OperatorConfig) {
+		detector.Reload(ctx, &cfg.Alerting, addRules(cfg))
+	})
+	detector.Start(ctx)
+
 	err = app.Start(ctx, config.BtcPk)
 	if err != nil {
 		return fmt.Errorf("failed to create Start for app: %w", err)
@@ -67,13 +115,7 @@ func finalityProvider(cliCtx *cli.Context) error {
 	return nil
 }
 
-func newApp(ctx context.Context, config *configs.OperatorConfig) (*fp.FinalityProviderApp, error) {
-	zaplogger, err := logging.NewZapLoggerInner(logging.NewLogLevel(config.Common.Production))
-	if err != nil {
-		log.Fatalf("new logger failed by %v", err)
-		return nil, err
-	}
-
+func newApp(ctx context.Context, config *configs.OperatorConfig, zaplogger *zap.Logger) (*fp.FinalityProviderApp, error) {
 	fpConfig, dbBackend, err := newAppParams(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create params for app: %w", err)
@@ -0,0 +1,153 @@
+package fp
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/babylonlabs-io/babylon/v3/types"
+
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
+	"github.com/alt-research/blitz/finality-gadget/metrics"
+	"github.com/alt-research/blitz/finality-gadget/operator/admin"
+	"github.com/alt-research/blitz/finality-gadget/operator/configs"
+)
+
+var _ admin.Backend = &FinalityProviderApp{}
+
+var errNoOrbitController = errors.New("the consumer controller is not the orbit consumer controller")
+
+// statusTimeout is the timeout to get the status for the metrics server.
+const statusTimeout = 5 * time.Second
+
+// SetConfigReloader sets the reloader for the admin api, the reloaded config will be applied to the app.
+func (app *FinalityProviderApp) SetConfigReloader(reloader *utils.ConfigReloader[configs.OperatorConfig]) {
+	app.reloader = reloader
+	reloader.OnReload(app.ApplyConfig)
+}
+
+// ReloadConfig reloads the config file, only the hot reloadable fields are applied.
+func (app *FinalityProviderApp) ReloadConfig(ctx context.Context) error {
+	if app.reloader == nil {
+		return errors.New("the config reloader is not set")
+	}
+
+	_, err := app.reloader.Reload()
+	return err
+}
+
+// Pause pauses the finality signature submission for all the finality providers.
+func (app *FinalityProviderApp) Pause() error {
+	if app.orbitCon == nil {
+		return errNoOrbitController
+	}
+
+	app.logger.Info("pause the finality signature submission")
+	return app.orbitCon.VotingGate().Pause()
+}
+
+// Resume resumes the finality signature submission for all the finality providers.
+func (app *FinalityProviderApp) Resume() error {
+	if app.orbitCon == nil {
+		return errNoOrbitController
+	}
+
+	app.logger.Info("resume the finality signature submission")
+	return app.orbitCon.VotingGate().Resume()
+}
+
+func (app *FinalityProviderApp) RefreshBalance(ctx context.Context) error {
+	if app.orbitCon == nil {
+		return errNoOrbitController
+	}
+
+	// the ctx of request will be canceled after response
+	app.orbitCon.RefreshBalance(context.WithoutCancel(ctx))
+	return nil
+}
+
+// StartFp starts the finality provider instance by the admin api, the instance runs with the ctx of Start
+// as the ctx of the request is canceled after the response.
+func (app *FinalityProviderApp) StartFp(_ context.Context, btcPk string) error {
+	app.instancesMu.Lock()
+	runCtx := app.runCtx
+	app.instancesMu.Unlock()
+
+	if runCtx == nil {
+		return errors.New("the operator is not started")
+	}
+
+	return app.StartFinalityProvider(runCtx, btcPk)
+}
+
+// StopFp stops the finality provider instance by the admin api.
+func (app *FinalityProviderApp) StopFp(_ context.Context, btcPk string) error {
+	return app.StopFinalityProvider(btcPk)
+}
+
+func (app *FinalityProviderApp) Status(ctx context.Context) (*admin.Status, error) {
+	if app.orbitCon == nil {
+		return nil, errNoOrbitController
+	}
+
+	storedFps, err := app.fps.GetAllStoredFinalityProviders()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get all stored finality providers")
+	}
+
+	res := &admin.Status{
+		Paused:            app.orbitCon.VotingGate().IsPaused(),
+		FinalityProviders: make([]admin.FpStatus, 0, len(storedFps)),
+	}
+
+	if app.finalized != nil {
+		heads, err := app.finalized.Heads(ctx)
+		if err != nil {
+			app.logger.Warn("failed to look up the heads for the status", zap.Error(err))
+		}
+		res.Heads = heads
+	}
+
+	runways := metrics.NewVoteMetrics().PubRandRunway()
+
+	for _, sfp := range storedFps {
+		pkHex := types.NewBIP340PubKeyFromBTCPK(sfp.BtcPk).MarshalHex()
+		voteStatus := app.orbitCon.VoteStatus(pkHex)
+
+		lastVotedHeight := sfp.LastVotedHeight
+		if voteStatus.LastVotedHeight > lastVotedHeight {
+			lastVotedHeight = voteStatus.LastVotedHeight
+		}
+
+		fpStatus := admin.FpStatus{
+			BtcPk:           pkHex,
+			Running:         app.IsFinalityProviderRunning(pkHex),
+			Status:          sfp.Status.String(),
+			LastVotedHeight: lastVotedHeight,
+			PendingHeights:  voteStatus.PendingHeights,
+		}
+		if runway, ok := runways[pkHex]; ok {
+			fpStatus.PubRandRunway = &runway
+		}
+
+		res.FinalityProviders = append(res.FinalityProviders, fpStatus)
+	}
+
+	return res, nil
+}
+
+// registerStatus serves the status in `/status/operator` of the metrics server.
+func (app *FinalityProviderApp) registerStatus() {
+	metrics.RegisterStatusProvider("operator", func() any {
+		ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+		defer cancel()
+
+		status, err := app.Status(ctx)
+		if err != nil {
+			return map[string]string{"error": err.Error()}
+		}
+		return status
+	})
+}
@@ -3,6 +3,7 @@ package fp
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -22,10 +23,13 @@ import (
 	fp_metrics "github.com/babylonlabs-io/finality-provider/metrics"
 
 	"github.com/alt-research/blitz/finality-gadget/client/eotsmanager"
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
 	"github.com/alt-research/blitz/finality-gadget/metrics"
 	"github.com/alt-research/blitz/finality-gadget/operator/configs"
 	"github.com/alt-research/blitz/finality-gadget/operator/fp/controllers"
+	"github.com/alt-research/blitz/finality-gadget/operator/ha"
 	"github.com/alt-research/blitz/finality-gadget/rpc"
+	"github.com/alt-research/blitz/finality-gadget/rpc/provider"
 )
 
 type FinalityProviderApp struct {
@@ -35,16 +39,42 @@ type FinalityProviderApp struct {
 
 	config *fpcfg.Config
 
-	fpApp       *service.FinalityProviderApp
-	eotsManager fpeotsmanager.EOTSManager
-	rpc         *rpc.JsonRpcServer
-	logger      *zap.Logger
+	cc           ccapi.BabylonController
+	consumerCon  ccapi.ConsumerController
+	eotsManager  fpeotsmanager.EOTSManager
+	db           kvdb.Backend
+	fps          *store.FinalityProviderStore
+	pubRandStore *store.PubRandProofStore
+	fpMetrics    *fp_metrics.FpMetrics
+	blitzMetrics *metrics.FpMetrics
+	rpc          *rpc.JsonRpcServer
+	logger       *zap.Logger
+
+	// the orbit consumer controller for admin api, can be nil if use other controller.
+	orbitCon *controllers.OrbitConsumerController
+
+	// the finality lookup for the heads in the status, can be nil if failed to create.
+	finalized *provider.FinalizedStateProvider
+
+	// the config reloader for admin api, can be nil if not set.
+	reloader *utils.ConfigReloader[configs.OperatorConfig]
+
+	// the running finality provider instances, by the pk hex
+	instances   map[string]*fpInstance
+	instancesMu sync.Mutex
+	// runCtx is the ctx of Start, the instances started by the admin api run with it
+	runCtx context.Context
 
 	jsonRpcServerIpPortAddr string
 
 	wg sync.WaitGroup
 }
 
+type fpInstance struct {
+	btcPk *types.BIP340PubKey
+	app   *service.FinalityProviderApp
+}
+
 func NewFinalityProviderAppFromConfig(
 	ctx context.Context,
 	cfg *configs.OperatorConfig,
@@ -54,7 +84,7 @@ func NewFinalityProviderAppFromConfig(
 ) (*FinalityProviderApp, error) {
 	blitzMetrics := metrics.NewFpMetrics()
 
-	em, err := eotsmanager.NewEOTSManagerClient(logger, cfg.EOTSManagerConfig)
+	em, err := eotsmanager.NewEOTSManagerClient(logger, cfg.EOTSManagerConfig, cfg.FinalityProviderHomePath)
 	if err != nil {
 		return nil, errors.Wrap(err, "NewEOTSManagerClient failed")
 	}
@@ -71,16 +101,35 @@ func NewFinalityProviderAppFromConfig(
 		return nil, errors.Wrap(err, "NewOrbitConsumerController failed")
 	}
 
+	if cfg.HA.Enabled {
+		elector, err := ha.NewElectorFromConfig(logger, cfg.HA)
+		if err != nil {
+			return nil, errors.Wrap(err, "NewElectorFromConfig failed")
+		}
+
+		em.SetSignGate(elector)
+		consumerCon.SetLeaderElector(elector)
+		elector.Start(ctx)
+	}
+
 	var rpcServer *rpc.JsonRpcServer
+	var finalized *provider.FinalizedStateProvider
 
 	if cfg.Common.RpcServerIpPortAddress != "" {
 		rpcServer, err = rpc.NewJsonRpcServer(ctx, logger, cfg, fpCfg.Common)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create NewJsonRpcServer")
 		}
+		finalized = rpcServer.FinalizedStateProvider()
+	} else {
+		// the heads in the status are looked up by the operator itself if not serving the rpc
+		finalized, err = provider.NewFinalizedStateProvider(ctx, cfg, logger)
+		if err != nil {
+			logger.Warn("failed to create the finalized state provider, the status will have no heads", zap.Error(err))
+		}
 	}
 
-	return NewFinalityProviderApp(
+	app, err := NewFinalityProviderApp(
 		ctx,
 		fpCfg.Common, cc, consumerCon, em,
 		db, blitzMetrics,
@@ -88,6 +137,12 @@ func NewFinalityProviderAppFromConfig(
 		cfg.Common.RpcServerIpPortAddress,
 		logger,
 	)
+	if err != nil {
+		return nil, err
+	}
+	app.finalized = finalized
+
+	return app, nil
 }
 
 func NewFinalityProviderApp(
@@ -102,20 +157,57 @@ func NewFinalityProviderApp(
 	jsonRpcServerIpPortAddr string,
 	logger *zap.Logger,
 ) (*FinalityProviderApp, error) {
-	fpMetrics := fp_metrics.NewFpMetrics()
-	poller := service.NewChainPoller(logger, config.PollerConfig, consumerCon, fpMetrics)
+	fpStore, err := store.NewFinalityProviderStore(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initiate finality provider store: %w", err)
+	}
+
 	pubRandStore, err := store.NewPubRandProofStore(db)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initiate public randomness store: %w", err)
 	}
 
+	orbitCon, _ := consumerCon.(*controllers.OrbitConsumerController)
+
+	app := &FinalityProviderApp{
+		orbitCon:                orbitCon,
+		config:                  config,
+		cc:                      cc,
+		consumerCon:             consumerCon,
+		eotsManager:             em,
+		db:                      db,
+		fps:                     fpStore,
+		pubRandStore:            pubRandStore,
+		fpMetrics:               fp_metrics.NewFpMetrics(),
+		blitzMetrics:            blitzMetrics,
+		logger:                  logger,
+		rpc:                     rpc,
+		jsonRpcServerIpPortAddr: jsonRpcServerIpPortAddr,
+		instances:               make(map[string]*fpInstance, 4),
+		quit:                    make(chan struct{}),
+	}
+	app.registerStatus()
+
+	return app, nil
+}
+
+// newFpServiceApp creates a finality provider service app for one finality provider,
+// all the clients are shared by the instances.
+func (app *FinalityProviderApp) newFpServiceApp(logger *zap.Logger) (*service.FinalityProviderApp, error) {
+	config := app.config
+	cc := &sharedBabylonController{app.cc}
+	consumerCon := &sharedConsumerController{app.consumerCon}
+	em := &sharedEOTSManager{app.eotsManager}
+
+	poller := service.NewChainPoller(logger, config.PollerConfig, consumerCon, app.fpMetrics)
+
 	rndCommiter := service.NewDefaultRandomnessCommitter(
 		service.NewRandomnessCommitterConfig(config.NumPubRand, int64(config.TimestampingDelayBlocks), config.ContextSigningHeight),
-		service.NewPubRandState(pubRandStore),
+		service.NewPubRandState(app.pubRandStore),
 		consumerCon,
 		em,
 		logger,
-		fpMetrics,
+		app.fpMetrics,
 	)
 
 	heightDeterminer := service.NewStartHeightDeterminer(consumerCon, config.PollerConfig, logger)
@@ -124,7 +216,7 @@ func NewFinalityProviderApp(
 		config.ContextSigningHeight,
 		config.SubmissionRetryInterval,
 	)
-	finalitySubmitter := service.NewDefaultFinalitySubmitter(consumerCon, em, rndCommiter.GetPubRandProofList, fsCfg, logger, fpMetrics)
+	finalitySubmitter := service.NewDefaultFinalitySubmitter(consumerCon, em, rndCommiter.GetPubRandProofList, fsCfg, logger, app.fpMetrics)
 
 	fpApp, err := service.NewFinalityProviderApp(
 		config,
@@ -135,37 +227,36 @@ func NewFinalityProviderApp(
 		rndCommiter,
 		heightDeterminer,
 		finalitySubmitter,
-		fpMetrics,
-		db,
+		app.fpMetrics,
+		app.db,
 		logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create finality-provider manager: %w", err)
 	}
 
-	return &FinalityProviderApp{
-		fpApp:                   fpApp,
-		config:                  config,
-		eotsManager:             em,
-		logger:                  logger,
-		rpc:                     rpc,
-		jsonRpcServerIpPortAddr: jsonRpcServerIpPortAddr,
-		quit:                    make(chan struct{}),
-	}, nil
+	return fpApp, nil
 }
 
 func (app *FinalityProviderApp) GetAllStoredFinalityProviders() ([]*proto.FinalityProviderInfo, error) {
-	return app.fpApp.ListAllFinalityProvidersInfo()
-}
+	storedFps, err := app.fps.GetAllStoredFinalityProviders()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all stored finality providers: %w", err)
+	}
 
-// Start starts only the finality-provider daemon without any finality-provider instances
-func (app *FinalityProviderApp) Start(ctx context.Context, fpPkStr string) error {
-	// only start the app without starting any finality provider instance
-	// this is needed for new finality provider registration or unjailing
-	// finality providers
-	if err := app.fpApp.Start(ctx); err != nil {
-		return fmt.Errorf("failed to start the finality provider app: %w", err)
+	fpsInfo := make([]*proto.FinalityProviderInfo, 0, len(storedFps))
+	for _, fp := range storedFps {
+		fpInfo := fp.ToFinalityProviderInfo()
+		fpInfo.IsRunning = app.IsFinalityProviderRunning(fpInfo.BtcPkHex)
+		fpsInfo = append(fpsInfo, fpInfo)
 	}
 
+	return fpsInfo, nil
+}
+
+// Start starts the finality-provider daemon with the finality-provider instances by the pks,
+// if the pks is empty, it will start the only one stored finality provider,
+// if the pks is `all`, it will start all the stored finality providers.
+func (app *FinalityProviderApp) Start(ctx context.Context, fpPks configs.FpPkList) error {
 	if app.jsonRpcServerIpPortAddr != "" && app.rpc != nil {
 		app.wg.Add(1)
 		go func() {
@@ -178,33 +269,24 @@ func (app *FinalityProviderApp) Start(ctx context.Context, fpPkStr string) error
 		}()
 	}
 
-	// fp instance will be started if public key is specified
-	if fpPkStr != "" {
-		// start the finality-provider instance with the given public key
-		fpPk, err := types.NewBIP340PubKeyFromHex(fpPkStr)
-		if err != nil {
-			return fmt.Errorf("invalid finality provider public key %s: %w", fpPkStr, err)
-		}
+	app.instancesMu.Lock()
+	app.runCtx = ctx
+	app.instancesMu.Unlock()
 
-		if err := app.fpApp.StartFinalityProvider(ctx, fpPk); err != nil {
-			return fmt.Errorf("failed to start by fpPkStr %s: %w", fpPkStr, err)
-		}
-	} else {
-		app.logger.Sugar().Info("start fp by storedFps")
-		storedFps, err := app.fpApp.GetFinalityProviderStore().GetAllStoredFinalityProviders()
-		if err != nil {
-			return err
-		}
+	pks, err := app.fpPksToStart(fpPks)
+	if err != nil {
+		return err
+	}
 
-		if len(storedFps) == 1 {
-			pk := types.NewBIP340PubKeyFromBTCPK(storedFps[0].BtcPk)
-			if err := app.fpApp.StartFinalityProvider(ctx, pk); err != nil {
-				return fmt.Errorf("failed to start by storedFps %s: %w", pk.MarshalHex(), err)
+	for i, pk := range pks {
+		if err := app.StartFinalityProvider(ctx, pk); err != nil {
+			// not leave the started ones running
+			for _, started := range pks[:i] {
+				if stopErr := app.StopFinalityProvider(started); stopErr != nil {
+					app.logger.Error("failed to stop the finality provider", zap.String("fp_btc_pk", started), zap.Error(stopErr))
+				}
 			}
-		}
-
-		if len(storedFps) > 1 {
-			return fmt.Errorf("%d finality providers found in DB. Please specify the EOTS public key", len(storedFps))
+			return err
 		}
 	}
 
@@ -227,6 +309,141 @@ func (app *FinalityProviderApp) Start(ctx context.Context, fpPkStr string) error
 	}
 }
 
+func (app *FinalityProviderApp) fpPksToStart(fpPks configs.FpPkList) ([]string, error) {
+	if len(fpPks) != 0 && !fpPks.IsAll() {
+		return fpPks, nil
+	}
+
+	app.logger.Sugar().Info("start fp by storedFps")
+	storedFps, err := app.fps.GetAllStoredFinalityProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(storedFps) > 1 && !fpPks.IsAll() {
+		return nil, fmt.Errorf(
+			"%d finality providers found in DB. Please specify the EOTS public keys or `%s`",
+			len(storedFps), configs.AllStoredFps)
+	}
+
+	res := make([]string, 0, len(storedFps))
+	for _, sfp := range storedFps {
+		res = append(res, types.NewBIP340PubKeyFromBTCPK(sfp.BtcPk).MarshalHex())
+	}
+
+	return res, nil
+}
+
+// StartFinalityProvider starts a finality provider instance by the pk, the instance
+// will use the clients shared by the app.
+func (app *FinalityProviderApp) StartFinalityProvider(ctx context.Context, fpPkStr string) error {
+	fpPk, err := types.NewBIP340PubKeyFromHex(fpPkStr)
+	if err != nil {
+		return fmt.Errorf("invalid finality provider public key %s: %w", fpPkStr, err)
+	}
+	pkHex := fpPk.MarshalHex()
+
+	app.instancesMu.Lock()
+	defer app.instancesMu.Unlock()
+
+	if _, ok := app.instances[pkHex]; ok {
+		return fmt.Errorf("the finality provider %s is already running", pkHex)
+	}
+
+	logger := app.logger.With(zap.String("fp_btc_pk", pkHex))
+	logger.Info("start finality provider instance")
+
+	fpApp, err := app.newFpServiceApp(logger)
+	if err != nil {
+		return fmt.Errorf("failed to create finality provider app for %s: %w", pkHex, err)
+	}
+
+	if err := fpApp.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start the finality provider app for %s: %w", pkHex, err)
+	}
+
+	if err := fpApp.StartFinalityProvider(ctx, fpPk); err != nil {
+		if stopErr := fpApp.Stop(); stopErr != nil {
+			logger.Error("failed to stop the finality provider app", zap.Error(stopErr))
+		}
+		return fmt.Errorf("failed to start by fpPkStr %s: %w", pkHex, err)
+	}
+
+	app.instances[pkHex] = &fpInstance{
+		btcPk: fpPk,
+		app:   fpApp,
+	}
+	app.blitzMetrics.RecordFpInstanceRunning(pkHex, true)
+
+	if app.orbitCon != nil {
+		if err := app.orbitCon.SeedPubRandRunway(ctx, fpPk); err != nil {
+			logger.Warn("failed to query the last public randomness commit, the runway is unknown until the next commit", zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// StopFinalityProvider stops the finality provider instance by the pk,
+// the other instances and the shared clients will keep running.
+func (app *FinalityProviderApp) StopFinalityProvider(fpPkStr string) error {
+	fpPk, err := types.NewBIP340PubKeyFromHex(fpPkStr)
+	if err != nil {
+		return fmt.Errorf("invalid finality provider public key %s: %w", fpPkStr, err)
+	}
+	pkHex := fpPk.MarshalHex()
+
+	app.instancesMu.Lock()
+	defer app.instancesMu.Unlock()
+
+	ins, ok := app.instances[pkHex]
+	if !ok {
+		return fmt.Errorf("the finality provider %s is not running", pkHex)
+	}
+
+	return app.stopInstance(pkHex, ins)
+}
+
+func (app *FinalityProviderApp) stopInstance(pkHex string, ins *fpInstance) error {
+	app.logger.Info("stop finality provider instance", zap.String("fp_btc_pk", pkHex))
+
+	delete(app.instances, pkHex)
+	app.blitzMetrics.RecordFpInstanceRunning(pkHex, false)
+
+	if err := ins.app.Stop(); err != nil {
+		return fmt.Errorf("failed to stop the finality provider %s: %w", pkHex, err)
+	}
+
+	return nil
+}
+
+// RunningFinalityProviders returns the pks of the running finality provider instances.
+func (app *FinalityProviderApp) RunningFinalityProviders() []string {
+	app.instancesMu.Lock()
+	defer app.instancesMu.Unlock()
+
+	res := make([]string, 0, len(app.instances))
+	for pkHex := range app.instances {
+		res = append(res, pkHex)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+func (app *FinalityProviderApp) IsFinalityProviderRunning(fpPkStr string) bool {
+	app.instancesMu.Lock()
+	defer app.instancesMu.Unlock()
+
+	_, ok := app.instances[fpPkStr]
+	return ok
+}
+
+// RpcServer returns the rpc server, or nil if the rpc is not served by the operator.
+func (app *FinalityProviderApp) RpcServer() *rpc.JsonRpcServer {
+	return app.rpc
+}
+
 func (app *FinalityProviderApp) Wait() {
 	app.wg.Wait()
 }
@@ -243,9 +460,24 @@ func (app *FinalityProviderApp) stopImpl() error {
 
 		app.logger.Debug("Stopping finality providers")
 
-		if err := app.fpApp.Stop(); err != nil {
+		app.instancesMu.Lock()
+		for pkHex, ins := range app.instances {
+			if err := app.stopInstance(pkHex, ins); err != nil {
+				app.logger.Error("failed to stop finality provider", zap.Error(err))
+				stopErr = err
+			}
+		}
+		app.instancesMu.Unlock()
+
+		app.logger.Debug("Stopping clients")
+		if err := app.consumerCon.Close(); err != nil {
+			app.logger.Error("failed to close consumer controller", zap.Error(err))
+			stopErr = err
+		}
+
+		if err := app.cc.Close(); err != nil {
+			app.logger.Error("failed to close babylon controller", zap.Error(err))
 			stopErr = err
-			return
 		}
 
 		app.logger.Debug("Stopping EOTS manager")
@@ -259,3 +491,14 @@ func (app *FinalityProviderApp) stopImpl() error {
 	})
 	return stopErr
 }
+
+// ApplyConfig applies the hot reloadable config to the controller and the rpc server.
+func (app *FinalityProviderApp) ApplyConfig(cfg *configs.OperatorConfig) {
+	if app.orbitCon != nil {
+		app.orbitCon.ApplyConfig(cfg)
+	}
+
+	if app.rpc != nil {
+		app.rpc.ApplyConfig(cfg)
+	}
+}
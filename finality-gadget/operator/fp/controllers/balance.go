@@ -0,0 +1,249 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	bbnclient "github.com/babylonlabs-io/babylon/v3/client/client"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+
+	"github.com/alt-research/blitz/finality-gadget/metrics"
+	"github.com/alt-research/blitz/finality-gadget/operator/configs"
+)
+
+// balanceQueryTimeout is the timeout to query the balance of an account.
+const balanceQueryTimeout = 8 * time.Second
+
+// minSpendObservation is the min time observed to estimate the daily spend, the spend of a few votes is not stable.
+const minSpendObservation = time.Hour
+
+// QueryBalance returns the balance of the denom at the address, divided by 10^decimals.
+func QueryBalance(ctx context.Context, bbnClient *bbnclient.Client, address, denom string, decimals uint) (float64, error) {
+	req := banktypes.QueryBalanceRequest{
+		Address: address,
+		Denom:   denom,
+	}
+
+	data, err := req.Marshal()
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	result, err := bbnClient.RPCClient.ABCIQuery(ctx, "/cosmos.bank.v1beta1.Query/Balance", data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query balance: %w", err)
+	}
+
+	if !result.Response.IsOK() {
+		return 0, fmt.Errorf("failed to query balance: %s", result.Response.Log)
+	}
+
+	var balancesResp banktypes.QueryBalanceResponse
+	if err := balancesResp.Unmarshal(result.Response.Value); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal balance response: %w", err)
+	}
+
+	if balancesResp.GetBalance() == nil {
+		return 0, nil
+	}
+
+	return toUnit(balancesResp.GetBalance().Amount.BigInt(), decimals), nil
+}
+
+// toUnit divides the amount by 10^decimals, the big float keeps the amounts more than uint64.
+func toUnit(amount *big.Int, decimals uint) float64 {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	res, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(scale)).Float64()
+	return res
+}
+
+type balanceSample struct {
+	at      time.Time
+	balance float64
+}
+
+// balanceTracker queries the balances of the monitored accounts,
+// and estimates the days left by the decreases of the balances observed in the spend window.
+type balanceTracker struct {
+	logger      *zap.Logger
+	bbnClient   *bbnclient.Client
+	metrics     *metrics.FpMetrics
+	accounts    []configs.BalanceAccountConfig
+	interval    time.Duration
+	spendWindow time.Duration
+
+	// the samples in the spend window by the account name
+	samples map[string][]balanceSample
+	mu      sync.Mutex
+	// reloaded sends the new interval after the config reloaded
+	reloaded chan time.Duration
+	// refreshing is true if the submitter balance is being refreshed after a tx, to not pile up the refreshes
+	refreshing atomic.Bool
+}
+
+func newBalanceTracker(
+	logger *zap.Logger,
+	bbnClient *bbnclient.Client,
+	blitzMetrics *metrics.FpMetrics,
+	cfg configs.BalanceConfig) *balanceTracker {
+	cfg.WithDefault()
+
+	return &balanceTracker{
+		logger:      logger,
+		bbnClient:   bbnClient,
+		metrics:     blitzMetrics,
+		accounts:    cfg.Accounts,
+		interval:    cfg.Interval,
+		spendWindow: cfg.SpendWindow,
+		samples:     make(map[string][]balanceSample, len(cfg.Accounts)),
+		reloaded:    make(chan time.Duration, 1),
+	}
+}
+
+// apply applies the reloaded config, the samples and the metrics of the accounts removed or changed are dropped.
+func (t *balanceTracker) apply(cfg configs.BalanceConfig) {
+	cfg.WithDefault()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	accounts := make(map[string]configs.BalanceAccountConfig, len(cfg.Accounts))
+	for _, account := range cfg.Accounts {
+		accounts[account.Name] = account
+	}
+
+	for _, old := range t.accounts {
+		account, ok := accounts[old.Name]
+		if ok && account.Address == old.Address && account.Denom == old.Denom && account.Decimals == old.Decimals {
+			continue
+		}
+
+		delete(t.samples, old.Name)
+		t.metrics.RemoveAccountBalance(old.Name)
+	}
+
+	t.accounts = cfg.Accounts
+	t.interval = cfg.Interval
+	t.spendWindow = cfg.SpendWindow
+
+	// drop the interval not applied yet
+	select {
+	case <-t.reloaded:
+	default:
+	}
+	t.reloaded <- t.interval
+}
+
+// record queries and records the balances of all the accounts, the failed ones are skipped.
+// The balances are sampled for the spend estimate only if sample, which is by the interval.
+func (t *balanceTracker) record(ctx context.Context, sample bool) {
+	t.recordAccounts(ctx, func(configs.BalanceAccountConfig) bool { return true }, sample)
+}
+
+// refreshSubmitter refreshes the balance of the submitter in background after a tx,
+// it is skipped if the last refresh is not done.
+func (t *balanceTracker) refreshSubmitter(ctx context.Context) {
+	if !t.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer t.refreshing.Store(false)
+
+		t.recordAccounts(ctx, func(account configs.BalanceAccountConfig) bool { return account.Address == "" }, false)
+	}()
+}
+
+func (t *balanceTracker) recordAccounts(ctx context.Context, filter func(configs.BalanceAccountConfig) bool, sample bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, account := range t.accounts {
+		if !filter(account) {
+			continue
+		}
+
+		address := account.Address
+		if address == "" {
+			addr, err := t.bbnClient.GetAddr()
+			if err != nil {
+				t.logger.Error("failed to get the submitter address", zap.String("account", account.Name), zap.Error(err))
+				continue
+			}
+			address = addr
+		}
+
+		queryCtx, cancel := context.WithTimeout(ctx, balanceQueryTimeout)
+		balance, err := QueryBalance(queryCtx, t.bbnClient, address, account.Denom, account.Decimals)
+		cancel()
+		if err != nil {
+			// not record the zero balance, which will raise the balance alert
+			t.logger.Error("failed to query the balance",
+				zap.String("account", account.Name), zap.String("address", address), zap.Error(err))
+			continue
+		}
+
+		b := metrics.AccountBalance{
+			Name:              account.Name,
+			Address:           address,
+			Denom:             account.Denom,
+			Balance:           balance,
+			WarningThreshold:  account.WarningThreshold,
+			CriticalThreshold: account.CriticalThreshold,
+		}
+		b.DailySpend, b.DaysLeft = t.estimate(account.Name, balance, time.Now(), sample)
+
+		t.logger.Debug("record account balance",
+			zap.String("account", account.Name), zap.String("address", address), zap.Float64("balance", balance))
+		t.metrics.RecordAccountBalance(b)
+
+		// the legacy metric of the submitter address
+		if account.Address == "" {
+			t.metrics.RecordFpBalance(address, balance)
+		}
+	}
+}
+
+// estimate returns the daily spend and the days left by the samples and the balance,
+// nil if not observed long enough or nothing spent. The balance is added to the samples if sample.
+func (t *balanceTracker) estimate(name string, balance float64, now time.Time, sample bool) (*float64, *float64) {
+	samples := t.samples[name]
+	samples = append(samples[:len(samples):len(samples)], balanceSample{at: now, balance: balance})
+
+	// drop the samples out of the window
+	start := 0
+	for start < len(samples)-1 && now.Sub(samples[start].at) > t.spendWindow {
+		start++
+	}
+	samples = samples[start:]
+	if sample {
+		t.samples[name] = samples
+	}
+
+	observed := now.Sub(samples[0].at)
+	if observed < minSpendObservation {
+		return nil, nil
+	}
+
+	// only the decreases are spent, the increases are the top ups
+	var spent float64
+	for i := 1; i < len(samples); i++ {
+		if d := samples[i-1].balance - samples[i].balance; d > 0 {
+			spent += d
+		}
+	}
+
+	dailySpend := spent / observed.Hours() * 24
+	if dailySpend <= 0 {
+		return &dailySpend, nil
+	}
+
+	daysLeft := balance / dailySpend
+	return &dailySpend, &daysLeft
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"sync"
 	"time"
@@ -9,12 +10,15 @@ import (
 	"go.uber.org/zap"
 
 	bbnclient "github.com/babylonlabs-io/babylon/v3/client/client"
+	bbntypes "github.com/babylonlabs-io/babylon/v3/types"
+	"github.com/babylonlabs-io/finality-gadget/cwclient"
 	"github.com/babylonlabs-io/finality-gadget/db"
+	"github.com/babylonlabs-io/finality-gadget/finalitygadget"
+	fgtypes "github.com/babylonlabs-io/finality-gadget/types"
 	"github.com/babylonlabs-io/finality-provider/bsn/rollup/clientcontroller"
 	rollupfpconfig "github.com/babylonlabs-io/finality-provider/bsn/rollup/config"
 	"github.com/babylonlabs-io/finality-provider/clientcontroller/api"
 	"github.com/babylonlabs-io/finality-provider/types"
-	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 
 	"github.com/alt-research/blitz/finality-gadget/client/l2eth"
 	"github.com/alt-research/blitz/finality-gadget/operator/configs"
@@ -32,11 +36,27 @@ type OrbitConsumerController struct {
 	fpConfig *rollupfpconfig.RollupFPConfig
 	*clientcontroller.RollupBSNController
 	blitzMetrics *metrics.FpMetrics
-	metricsMu    sync.Mutex
+	voteMetrics  *metrics.VoteMetrics
+	balances     *balanceTracker
 
 	backHeightCount uint64
 
 	bbnClient *bbnclient.Client
+
+	gate  *VotingGate
+	votes *voteTracker
+
+	// shadow is not nil if the operator is in shadow mode
+	shadow *shadowComparator
+
+	// elector is not nil if the operator is in active/standby mode
+	elector ILeaderElector
+	// the term of the elector checked by the batch of each fp after took over
+	checkedTerms   map[string]uint64
+	checkedTermsMu sync.Mutex
+
+	// votedQuerier is nil if no finality contract configured
+	votedQuerier IVotedFpsQuerier
 }
 
 func NewOrbitConsumerController(
@@ -80,29 +100,74 @@ func NewOrbitConsumerController(
 		return nil, fmt.Errorf("create initial buckets error: %w", err)
 	}
 
+	gate, err := NewVotingGate(cfg.Admin.StateFilePath(cfg.FinalityProviderHomePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create voting gate: %w", err)
+	}
+
+	if gate.IsPaused() {
+		zapLogger.Warn("the finality signature submission is paused, use `admin resume` to resume it")
+	}
+
 	res := &OrbitConsumerController{
 		RollupBSNController: consumerCon,
 		bbnClient:           bc,
 		blitzMetrics:        blitzMetrics,
+		voteMetrics:         metrics.NewVoteMetrics(),
+		balances:            newBalanceTracker(zapLogger, bc, blitzMetrics, cfg.Balance),
 		fpConfig:            fpConfig,
 		logger:              zapLogger,
 		backHeightCount:     cfg.Layer2.BackHeightCount,
+		gate:                gate,
+		votes:               newVoteTracker(),
+		checkedTerms:        make(map[string]uint64, 4),
+	}
+
+	if cfg.Babylon.FinalityGadgetCfg.FGContractAddress != "" {
+		res.votedQuerier = &cwVotedFpsQuerier{
+			cwClient: cwclient.NewCosmWasmClient(bc.RPCClient, cfg.Babylon.FinalityGadgetCfg.FGContractAddress),
+		}
+	}
+
+	if cfg.HA.Enabled {
+		// to check the batch is still canonical after took over
+		res.l2Client, err = l2eth.NewL2EthClient(ctx, &cfg.Layer2)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create l2 eth client: %w", err)
+		}
 	}
 
+	if cfg.IsShadowMode() {
+		zapLogger.Warn("the operator is in shadow mode, the votes and public randomness will not be broadcast")
+
+		res.shadow = newShadowComparator(
+			zapLogger, metrics.NewShadowMetrics(), res.votedQuerier,
+			cfg.Shadow.CompareDelay, cfg.Shadow.CompareTimeout)
+		res.shadow.start(ctx)
+	}
+
+	metrics.RegisterStatusProvider("balances", func() any {
+		return blitzMetrics.AccountBalances()
+	})
+
 	go func() {
-		res.logger.Info("Starting fp token metrics")
+		res.logger.Info("Starting fp token metrics", zap.Int("accounts", len(res.balances.accounts)))
 
-		res.recordFpBalance(ctx)
+		res.balances.record(ctx, true)
 
-		ticker := time.NewTicker(5 * time.Minute)
+		ticker := time.NewTicker(res.balances.interval)
 		defer ticker.Stop()
 		for {
 			select {
 			case <-ctx.Done():
 				return
+			case interval := <-res.balances.reloaded:
+				// record the accounts reloaded at once
+				ticker.Reset(interval)
+				res.balances.record(ctx, true)
 			case <-ticker.C:
 				res.logger.Debug("on recordAddressToken ticker")
-				res.recordFpBalance(ctx)
+				res.balances.record(ctx, true)
 			}
 		}
 	}()
@@ -117,12 +182,32 @@ func (wc *OrbitConsumerController) CommitPubRandList(
 	wc.logger.Sugar().Debugf(
 		"CommitPubRandList %v %v",
 		req.StartHeight, req.NumPubRand)
+
+	if err := wc.waitLeader(ctx); err != nil {
+		return nil, err
+	}
+
+	fpPk := bbntypes.NewBIP340PubKeyFromBTCPK(req.FpPk).MarshalHex()
+	if wc.shadow != nil {
+		wc.logger.Sugar().Infow(
+			"shadow mode: skip broadcast public randomness commit",
+			"fp", fpPk,
+			"start", req.StartHeight,
+			"num", req.NumPubRand,
+			"commitment", hex.EncodeToString(req.Commitment))
+		wc.shadow.metrics.RecordShadowPubRandCommit(fpPk)
+		return &types.TxResponse{}, nil
+	}
+
 	resp, err := wc.RollupBSNController.CommitPubRandList(ctx, req)
 	if err != nil {
+		wc.voteMetrics.RecordPubRandCommit(fpPk, req.StartHeight, uint64(req.NumPubRand), classifyTxError(err))
 		return nil, err
 	}
 
-	wc.recordFpBalance(ctx)
+	wc.voteMetrics.RecordPubRandCommit(fpPk, req.StartHeight, uint64(req.NumPubRand), "")
+	wc.recordTxFee(ctx, fpPk, metrics.TxTypePubRand, resp)
+	wc.balances.refreshSubmitter(ctx)
 	return resp, nil
 }
 
@@ -131,69 +216,100 @@ func (wc *OrbitConsumerController) SubmitBatchFinalitySigs(
 	ctx context.Context, req *api.SubmitBatchFinalitySigsRequest) (*types.TxResponse, error) {
 	wc.logger.Sugar().Debugf("SubmitBatchFinalitySigs %v", req.Blocks)
 
-	resp, err := wc.RollupBSNController.SubmitBatchFinalitySigs(ctx, req)
-	if err != nil {
-		return nil, err
+	fpPk := bbntypes.NewBIP340PubKeyFromBTCPK(req.FpPk).MarshalHex()
+	heights := make([]uint64, 0, len(req.Blocks))
+	for _, b := range req.Blocks {
+		heights = append(heights, b.GetHeight())
 	}
 
-	wc.recordFpBalance(ctx)
-	return resp, nil
-}
-
-func (wc *OrbitConsumerController) recordFpBalance(ctxBase context.Context) {
-	go func() {
-		wc.metricsMu.Lock()
-		defer wc.metricsMu.Unlock()
+	wc.votes.begin(fpPk, heights)
 
-		ctx, cancel := context.WithTimeout(ctxBase, 8*time.Second)
-		defer cancel()
+	// the randomness commit is not paused, so the fp can continue voting after resume.
+	if wc.gate.IsPaused() {
+		wc.logger.Sugar().Infow("finality signature submission is paused, wait for resume", "fp", fpPk, "heights", heights)
+	}
+	if err := wc.gate.Wait(ctx); err != nil {
+		wc.votes.end(fpPk, heights, false)
+		return nil, err
+	}
 
-		address, err := wc.bbnClient.GetAddr()
-		if err != nil {
-			wc.logger.Sugar().Errorw("recordFpBalance failed to get addr", "err", err)
-			return
-		}
+	if err := wc.waitLeader(ctx); err != nil {
+		wc.votes.end(fpPk, heights, false)
+		return nil, err
+	}
 
-		balance, err := wc.queryBalance(ctx, address, "ubbn")
-		if err != nil {
-			wc.logger.Sugar().Errorw("recordFpBalance failed to get balance by addr", "addr", address, "err", err)
+	if wc.shadow != nil {
+		hashes := make([]string, 0, len(req.Blocks))
+		for _, b := range req.Blocks {
+			hash := hex.EncodeToString(b.GetHash())
+			hashes = append(hashes, hash)
+			wc.shadow.record(fpPk, b.GetHeight(), hash)
 		}
 
-		wc.logger.Sugar().Debugw("record fp balance", "addr", address, "balance", balance)
-		wc.blitzMetrics.RecordFpBalance(address, balance)
-	}()
-}
-
-// QueryBalances returns balances at the address.
-func (wc *OrbitConsumerController) queryBalance(ctx context.Context, address, denom string) (float64, error) {
-	req := banktypes.QueryBalanceRequest{
-		Address: address,
-		Denom:   denom,
+		wc.logger.Sugar().Infow(
+			"shadow mode: skip broadcast finality signatures",
+			"fp", fpPk, "heights", heights, "hashes", hashes)
+		wc.votes.end(fpPk, heights, true)
+		return &types.TxResponse{}, nil
 	}
 
-	data, err := req.Marshal()
+	voted, err := wc.checkTakeover(ctx, fpPk, req)
 	if err != nil {
-		return 0, fmt.Errorf("failed to marshal request: %w", err)
+		wc.votes.end(fpPk, heights, false)
+		return nil, err
+	}
+	if voted {
+		wc.logger.Sugar().Infow("the batch was voted by the previous active operator, skip", "fp", fpPk, "heights", heights)
+		wc.votes.end(fpPk, heights, true)
+		return &types.TxResponse{}, nil
 	}
 
-	result, err := wc.bbnClient.RPCClient.ABCIQuery(ctx, "/cosmos.bank.v1beta1.Query/Balance", data)
+	start := time.Now()
+	resp, err := wc.RollupBSNController.SubmitBatchFinalitySigs(ctx, req)
+	wc.votes.end(fpPk, heights, err == nil)
 	if err != nil {
-		return 0, fmt.Errorf("failed to query balance: %w", err)
+		wc.voteMetrics.RecordFinalitySigs(fpPk, heights, start, classifyTxError(err))
+		return nil, err
 	}
 
-	var balancesResp banktypes.QueryBalanceResponse
-	if err := balancesResp.Unmarshal(result.Response.Value); err != nil {
-		return 0, fmt.Errorf("failed to unmarshal balance response: %w", err)
-	}
+	wc.voteMetrics.RecordFinalitySigs(fpPk, heights, start, "")
+	wc.recordTxFee(ctx, fpPk, metrics.TxTypeFinalitySigs, resp)
+	wc.balances.refreshSubmitter(ctx)
+	return resp, nil
+}
+
+// VotingGate returns the gate to pause and resume the finality signature submission.
+func (wc *OrbitConsumerController) VotingGate() *VotingGate {
+	return wc.gate
+}
+
+// VoteStatus returns the vote status submitted by this operator for the finality provider.
+func (wc *OrbitConsumerController) VoteStatus(fpPk string) FpVoteStatus {
+	return wc.votes.status(fpPk)
+}
+
+// RefreshBalance refreshes the balance metrics, the balances are not sampled for the spend estimate.
+func (wc *OrbitConsumerController) RefreshBalance(ctx context.Context) {
+	go wc.balances.record(ctx, false)
+}
+
+// cwVotedFpsQuerier query the voted finality providers from the finality contract.
+type cwVotedFpsQuerier struct {
+	cwClient finalitygadget.ICosmWasmClient
+}
 
-	// from ubbn to bbn
-	balanceUBBN := balancesResp.GetBalance().Amount.BigInt()
-	return float64(balanceUBBN.Uint64()) / 1000000, nil
+func (q *cwVotedFpsQuerier) QueryVotedFps(height uint64, hash string) ([]string, error) {
+	return q.cwClient.QueryListOfVotedFinalityProviders(&fgtypes.Block{
+		BlockHeight: height,
+		BlockHash:   hash,
+	})
 }
 
 func (wc *OrbitConsumerController) Close() error {
 	wc.logger.Sugar().Debugw("close OrbitConsumerController")
-	wc.l2Client.Close()
+	if wc.l2Client != nil {
+		wc.l2Client.Close()
+	}
 
 	if wc.bbnClient.IsRunning() {
 		if err := wc.bbnClient.Stop(); err != nil {
@@ -203,3 +319,12 @@ func (wc *OrbitConsumerController) Close() error {
 
 	return wc.RollupBSNController.Close()
 }
+
+// ApplyConfig applies the hot reloadable config.
+func (wc *OrbitConsumerController) ApplyConfig(cfg *configs.OperatorConfig) {
+	if wc.shadow != nil {
+		wc.shadow.setCompareTimes(cfg.Shadow.CompareDelay, cfg.Shadow.CompareTimeout)
+	}
+
+	wc.balances.apply(cfg.Balance)
+}
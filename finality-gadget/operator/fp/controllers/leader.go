@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/babylonlabs-io/finality-provider/clientcontroller/api"
+)
+
+// ILeaderElector elects the active operator in active/standby mode.
+type ILeaderElector interface {
+	IsLeader() bool
+	WaitLeader(ctx context.Context) error
+	// Term returns the number of times this operator became the active one.
+	Term() uint64
+}
+
+// SetLeaderElector makes the controller only submit the votes and randomness when
+// the operator is the active one, the standby will block until it takes over.
+func (wc *OrbitConsumerController) SetLeaderElector(elector ILeaderElector) {
+	wc.elector = elector
+}
+
+func (wc *OrbitConsumerController) waitLeader(ctx context.Context) error {
+	if wc.elector == nil || wc.elector.IsLeader() {
+		return nil
+	}
+
+	wc.logger.Info("the operator is standby, wait to be the active one")
+	return wc.elector.WaitLeader(ctx)
+}
+
+// checkTakeover re-reads the vote state by the first batch of the fp after this operator became the active one,
+// as the batch may be made before the previous active operator stopped. It returns an error if a block is not
+// canonical any more, and true if all the blocks were voted by the previous active operator.
+func (wc *OrbitConsumerController) checkTakeover(
+	ctx context.Context, fpPk string, req *api.SubmitBatchFinalitySigsRequest) (bool, error) {
+	if wc.elector == nil {
+		return false, nil
+	}
+
+	term := wc.elector.Term()
+
+	wc.checkedTermsMu.Lock()
+	checked := wc.checkedTerms[fpPk] == term
+	wc.checkedTermsMu.Unlock()
+	if checked {
+		return false, nil
+	}
+
+	allVoted := wc.votedQuerier != nil
+	for _, b := range req.Blocks {
+		header, err := wc.l2Client.HeaderByNumber(ctx, new(big.Int).SetUint64(b.GetHeight()))
+		if err != nil {
+			return false, fmt.Errorf("failed to query the l2 block %d: %w", b.GetHeight(), err)
+		}
+
+		if !bytes.Equal(header.Hash().Bytes(), b.GetHash()) {
+			return false, fmt.Errorf("the l2 block %d %x is not canonical after took over, refuse the stale batch",
+				b.GetHeight(), b.GetHash())
+		}
+
+		if !allVoted {
+			continue
+		}
+
+		voted, err := wc.votedQuerier.QueryVotedFps(b.GetHeight(), hex.EncodeToString(b.GetHash()))
+		if err != nil {
+			return false, fmt.Errorf("failed to query the voted finality providers of the l2 block %d: %w", b.GetHeight(), err)
+		}
+		allVoted = containsFpPk(voted, fpPk)
+	}
+
+	wc.checkedTermsMu.Lock()
+	wc.checkedTerms[fpPk] = term
+	wc.checkedTermsMu.Unlock()
+
+	return allVoted, nil
+}
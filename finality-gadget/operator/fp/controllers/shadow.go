@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/alt-research/blitz/finality-gadget/metrics"
+)
+
+const (
+	defaultShadowCompareDelay   = 30 * time.Second
+	defaultShadowCompareTimeout = 10 * time.Minute
+	maxShadowPendingVotes       = 65536
+)
+
+// IVotedFpsQuerier query the finality providers which voted the l2 block on chain.
+type IVotedFpsQuerier interface {
+	QueryVotedFps(height uint64, hash string) ([]string, error)
+}
+
+type shadowVote struct {
+	fpPk     string
+	height   uint64
+	hash     string
+	signedAt time.Time
+}
+
+// shadowComparator records the votes signed in shadow mode, and compares them
+// with the votes the live finality provider submitted on chain.
+type shadowComparator struct {
+	logger  *zap.Logger
+	metrics *metrics.ShadowMetrics
+	querier IVotedFpsQuerier
+
+	// the compare delay and timeout, can be changed by reload
+	delay   atomic.Int64
+	timeout atomic.Int64
+
+	pendings []*shadowVote
+	mu       sync.Mutex
+}
+
+func newShadowComparator(
+	logger *zap.Logger,
+	shadowMetrics *metrics.ShadowMetrics,
+	querier IVotedFpsQuerier,
+	delay, timeout time.Duration,
+) *shadowComparator {
+	c := &shadowComparator{
+		logger:  logger.With(zap.String("module", "shadow")),
+		metrics: shadowMetrics,
+		querier: querier,
+	}
+	c.setCompareTimes(delay, timeout)
+
+	return c
+}
+
+// setCompareTimes sets the compare delay and timeout, use the defaults if 0.
+func (c *shadowComparator) setCompareTimes(delay, timeout time.Duration) {
+	if delay == 0 {
+		delay = defaultShadowCompareDelay
+	}
+
+	if timeout == 0 {
+		timeout = defaultShadowCompareTimeout
+	}
+
+	c.delay.Store(int64(delay))
+	c.timeout.Store(int64(timeout))
+}
+
+func (c *shadowComparator) compareDelay() time.Duration {
+	return time.Duration(c.delay.Load())
+}
+
+func (c *shadowComparator) compareTimeout() time.Duration {
+	return time.Duration(c.timeout.Load())
+}
+
+func (c *shadowComparator) record(fpPk string, height uint64, hash string) {
+	c.metrics.RecordShadowVote(fpPk)
+
+	if c.querier == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.pendings) >= maxShadowPendingVotes {
+		c.logger.Warn("too many shadow votes waiting to compare, drop the oldest", zap.Uint64("height", c.pendings[0].height))
+		c.pendings = c.pendings[1:]
+	}
+
+	c.pendings = append(c.pendings, &shadowVote{
+		fpPk:     fpPk,
+		height:   height,
+		hash:     strings.TrimPrefix(hash, "0x"),
+		signedAt: time.Now(),
+	})
+	c.metrics.RecordShadowPendingCompares(len(c.pendings))
+}
+
+func (c *shadowComparator) start(ctx context.Context) {
+	if c.querier == nil {
+		c.logger.Warn("no voted finality providers querier, the shadow votes will not be compared")
+		return
+	}
+
+	go func() {
+		c.logger.Info("Starting shadow votes comparator")
+
+		delay := c.compareDelay()
+		ticker := time.NewTicker(delay)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.compare()
+
+				if d := c.compareDelay(); d != delay {
+					delay = d
+					ticker.Reset(delay)
+				}
+			}
+		}
+	}()
+}
+
+func (c *shadowComparator) compare() {
+	now := time.Now()
+	delay, timeout := c.compareDelay(), c.compareTimeout()
+
+	c.mu.Lock()
+	votes := make([]*shadowVote, len(c.pendings))
+	copy(votes, c.pendings)
+	c.mu.Unlock()
+
+	done := make(map[*shadowVote]struct{}, len(votes))
+	for _, v := range votes {
+		if now.Sub(v.signedAt) < delay {
+			// the pendings is ordered by the signed time
+			break
+		}
+
+		voted, err := c.querier.QueryVotedFps(v.height, v.hash)
+		if err != nil {
+			// keep the votes compared in this pass removed, the others are compared next time
+			c.logger.Error("query voted finality providers failed", zap.Uint64("height", v.height), zap.Error(err))
+			break
+		}
+
+		matched := containsFpPk(voted, v.fpPk)
+		if !matched && now.Sub(v.signedAt) < timeout {
+			// the live fp may not voted yet
+			continue
+		}
+
+		if matched {
+			c.logger.Debug("shadow vote matched", zap.String("fp", v.fpPk), zap.Uint64("height", v.height), zap.String("hash", v.hash))
+		} else {
+			c.logger.Warn("shadow vote mismatched, the live finality provider not voted the same block",
+				zap.String("fp", v.fpPk), zap.Uint64("height", v.height), zap.String("hash", v.hash))
+		}
+
+		c.metrics.RecordShadowCompare(v.fpPk, v.height, matched)
+		done[v] = struct{}{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pendings := c.pendings[:0]
+	for _, v := range c.pendings {
+		if _, ok := done[v]; !ok {
+			pendings = append(pendings, v)
+		}
+	}
+	c.pendings = pendings
+	c.metrics.RecordShadowPendingCompares(len(c.pendings))
+}
+
+func containsFpPk(fpPks []string, fpPk string) bool {
+	for _, pk := range fpPks {
+		if strings.EqualFold(strings.TrimPrefix(pk, "0x"), fpPk) {
+			return true
+		}
+	}
+
+	return false
+}
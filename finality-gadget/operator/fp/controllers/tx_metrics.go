@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	bbntypes "github.com/babylonlabs-io/babylon/v3/types"
+	"github.com/babylonlabs-io/finality-provider/types"
+	abci "github.com/cometbft/cometbft/abci/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// The error classes of the failed submissions.
+const (
+	errClassTimeout           = "timeout"
+	errClassCanceled          = "canceled"
+	errClassInsufficientFunds = "insufficient_funds"
+	errClassOutOfGas          = "out_of_gas"
+	errClassSequence          = "sequence_mismatch"
+	errClassDuplicate         = "duplicate"
+	errClassNetwork           = "network"
+	errClassContract          = "contract"
+	errClassOther             = "other"
+)
+
+// classifyTxError returns the class of the error for the metrics, to keep the labels bounded.
+func classifyTxError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errClassTimeout
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return errClassCanceled
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "insufficient fee"), strings.Contains(msg, "insufficient funds"):
+		return errClassInsufficientFunds
+	case strings.Contains(msg, "out of gas"):
+		return errClassOutOfGas
+	case strings.Contains(msg, "account sequence mismatch"), strings.Contains(msg, "incorrect account sequence"):
+		return errClassSequence
+	case strings.Contains(msg, "tx already in mempool"), strings.Contains(msg, "duplicate"):
+		return errClassDuplicate
+	case strings.Contains(msg, "timed out"), strings.Contains(msg, "timeout"):
+		return errClassTimeout
+	case strings.Contains(msg, "connection refused"), strings.Contains(msg, "no such host"), strings.Contains(msg, "eof"):
+		return errClassNetwork
+	case strings.Contains(msg, "wasm"), strings.Contains(msg, "contract"):
+		return errClassContract
+	}
+
+	return errClassOther
+}
+
+// SeedPubRandRunway records the last public randomness commit of the fp in the contract, so the runway
+// is known before the first commit after started.
+func (wc *OrbitConsumerController) SeedPubRandRunway(ctx context.Context, fpPk *bbntypes.BIP340PubKey) error {
+	btcPk, err := fpPk.ToBTCPK()
+	if err != nil {
+		return err
+	}
+
+	commit, err := wc.RollupBSNController.QueryLastPublicRandCommit(ctx, btcPk)
+	if err != nil {
+		return err
+	}
+
+	if commit == nil {
+		// not committed yet
+		return nil
+	}
+
+	wc.voteMetrics.RecordLastPubRandCommit(fpPk.MarshalHex(), commit.GetStartHeight(), commit.GetNumPubRand())
+
+	return nil
+}
+
+// recordTxFee queries the fee of the tx in background, the tx has been included when submitted.
+func (wc *OrbitConsumerController) recordTxFee(ctxBase context.Context, fpPk, txType string, resp *types.TxResponse) {
+	if resp == nil || resp.TxHash == "" {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(ctxBase, 8*time.Second)
+		defer cancel()
+
+		hash, err := hex.DecodeString(resp.TxHash)
+		if err != nil {
+			wc.logger.Sugar().Debugw("recordTxFee invalid tx hash", "hash", resp.TxHash, "err", err)
+			return
+		}
+
+		res, err := wc.bbnClient.RPCClient.Tx(ctx, hash, false)
+		if err != nil {
+			wc.logger.Sugar().Debugw("recordTxFee failed to query tx", "hash", resp.TxHash, "err", err)
+			return
+		}
+
+		if fee, ok := txFee(res.TxResult.Events, "ubbn"); ok {
+			wc.voteMetrics.RecordTxFee(fpPk, txType, fee)
+		}
+	}()
+}
+
+// txFee returns the fee in the unit of 10^6 denom from the `tx` event.
+func txFee(events []abci.Event, denom string) (float64, bool) {
+	for _, e := range events {
+		if e.Type != sdk.EventTypeTx {
+			continue
+		}
+
+		for _, attr := range e.Attributes {
+			if attr.Key != sdk.AttributeKeyFee {
+				continue
+			}
+
+			coins, err := sdk.ParseCoinsNormalized(attr.Value)
+			if err != nil {
+				return 0, false
+			}
+
+			return float64(coins.AmountOf(denom).Uint64()) / 1000000, true
+		}
+	}
+
+	return 0, false
+}
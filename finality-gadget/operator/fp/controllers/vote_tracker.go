@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"sort"
+	"sync"
+)
+
+// FpVoteStatus is the vote status of a finality provider submitted by the operator.
+type FpVoteStatus struct {
+	LastVotedHeight uint64
+	PendingHeights  []uint64
+}
+
+// voteTracker tracks the votes submitted by the operator for each finality provider.
+type voteTracker struct {
+	lastVoted map[string]uint64
+	pendings  map[string]map[uint64]struct{}
+	mu        sync.Mutex
+}
+
+func newVoteTracker() *voteTracker {
+	return &voteTracker{
+		lastVoted: make(map[string]uint64, 4),
+		pendings:  make(map[string]map[uint64]struct{}, 4),
+	}
+}
+
+// begin marks the heights as pending before submitting.
+func (t *voteTracker) begin(fpPk string, heights []uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	pendings, ok := t.pendings[fpPk]
+	if !ok {
+		pendings = make(map[uint64]struct{}, len(heights))
+		t.pendings[fpPk] = pendings
+	}
+
+	for _, h := range heights {
+		pendings[h] = struct{}{}
+	}
+}
+
+// end removes the heights from pending, and update the last voted height if success.
+func (t *voteTracker) end(fpPk string, heights []uint64, success bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	pendings := t.pendings[fpPk]
+	for _, h := range heights {
+		delete(pendings, h)
+
+		if success && h > t.lastVoted[fpPk] {
+			t.lastVoted[fpPk] = h
+		}
+	}
+}
+
+func (t *voteTracker) status(fpPk string) FpVoteStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	res := FpVoteStatus{
+		LastVotedHeight: t.lastVoted[fpPk],
+		PendingHeights:  make([]uint64, 0, len(t.pendings[fpPk])),
+	}
+
+	for h := range t.pendings[fpPk] {
+		res.PendingHeights = append(res.PendingHeights, h)
+	}
+	sort.Slice(res.PendingHeights, func(i, j int) bool { return res.PendingHeights[i] < res.PendingHeights[j] })
+
+	return res
+}
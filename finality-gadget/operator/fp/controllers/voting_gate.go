@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// operatorState is the operator state persisted across restarts.
+type operatorState struct {
+	Paused    bool      `json:"paused"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// VotingGate pauses the finality signature submission, the paused state
+// is persisted into the state file.
+type VotingGate struct {
+	path    string
+	paused  bool
+	resumed chan struct{}
+	mu      sync.Mutex
+}
+
+func NewVotingGate(path string) (*VotingGate, error) {
+	g := &VotingGate{
+		path:    path,
+		resumed: make(chan struct{}),
+	}
+
+	bz, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			close(g.resumed)
+			return g, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read operator state %s", path)
+	}
+
+	var state operatorState
+	if err := json.Unmarshal(bz, &state); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse operator state %s", path)
+	}
+
+	g.paused = state.Paused
+	if !g.paused {
+		close(g.resumed)
+	}
+
+	return g, nil
+}
+
+func (g *VotingGate) IsPaused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.paused
+}
+
+func (g *VotingGate) Pause() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.paused {
+		return nil
+	}
+
+	if err := g.save(true); err != nil {
+		return err
+	}
+
+	g.paused = true
+	g.resumed = make(chan struct{})
+
+	return nil
+}
+
+func (g *VotingGate) Resume() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.paused {
+		return nil
+	}
+
+	if err := g.save(false); err != nil {
+		return err
+	}
+
+	g.paused = false
+	close(g.resumed)
+
+	return nil
+}
+
+// Wait blocks until the gate is not paused or the ctx is done.
+func (g *VotingGate) Wait(ctx context.Context) error {
+	g.mu.Lock()
+	resumed := g.resumed
+	g.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-resumed:
+		return nil
+	}
+}
+
+func (g *VotingGate) save(paused bool) error {
+	bz, err := json.Marshal(&operatorState{
+		Paused:    paused,
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal operator state")
+	}
+
+	// write to a temp file then rename, so the state file will not be broken
+	tmp := g.path + ".tmp"
+	if err := os.WriteFile(tmp, bz, 0o600); err != nil {
+		return errors.Wrapf(err, "failed to write operator state %s", tmp)
+	}
+
+	if err := os.Rename(tmp, g.path); err != nil {
+		return errors.Wrapf(err, "failed to save operator state %s", g.path)
+	}
+
+	return nil
+}
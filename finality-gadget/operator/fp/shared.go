@@ -0,0 +1,34 @@
+package fp
+
+import (
+	ccapi "github.com/babylonlabs-io/finality-provider/clientcontroller/api"
+	fpeotsmanager "github.com/babylonlabs-io/finality-provider/eotsmanager"
+)
+
+// The clients are shared by all the finality provider instances in the operator,
+// the instance will close the clients when it stopped, so we wrap them to make
+// the `Close` as a no-op, the FinalityProviderApp will close the inner clients.
+
+type sharedBabylonController struct {
+	ccapi.BabylonController
+}
+
+func (c *sharedBabylonController) Close() error {
+	return nil
+}
+
+type sharedConsumerController struct {
+	ccapi.ConsumerController
+}
+
+func (c *sharedConsumerController) Close() error {
+	return nil
+}
+
+type sharedEOTSManager struct {
+	fpeotsmanager.EOTSManager
+}
+
+func (e *sharedEOTSManager) Close() error {
+	return nil
+}
@@ -0,0 +1,242 @@
+package ha
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/alt-research/blitz/finality-gadget/metrics"
+)
+
+const releaseLeaseTimeout = 5 * time.Second
+
+// Elector elects the active operator by the lease, the others are standby
+// and wait to take over when the lease expired.
+type Elector struct {
+	logger  *zap.Logger
+	metrics *metrics.HaMetrics
+	cfg     Config
+	lease   Lease
+
+	isLeader bool
+	// renewedAt is the time the last successful acquire or renew started,
+	// the lease is held until `renewedAt + lease_duration` at least.
+	renewedAt time.Time
+	// term is increased each time this operator becomes the leader.
+	term uint64
+	// leaderCh is closed when this operator becomes the leader,
+	// and replaced by a new one when it lost the lease.
+	leaderCh chan struct{}
+	mu       sync.Mutex
+}
+
+// NewElectorFromConfig creates the elector by the lease backend in config.
+func NewElectorFromConfig(logger *zap.Logger, cfg Config) (*Elector, error) {
+	cfg.WithDefault()
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid ha config")
+	}
+
+	var (
+		lease Lease
+		err   error
+	)
+
+	switch cfg.Backend {
+	case BackendFile:
+		lease, err = NewFileLease(cfg.File.Path)
+	case BackendKvdb:
+		lease, err = NewKvdbLease(&cfg.Kvdb, cfg.LeaseName)
+	case BackendHttp:
+		lease, err = NewHttpLease(&cfg.Http, cfg.LeaseName)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the %s lease", cfg.Backend)
+	}
+
+	return NewElector(logger, cfg, lease), nil
+}
+
+func NewElector(logger *zap.Logger, cfg Config, lease Lease) *Elector {
+	cfg.WithDefault()
+
+	haMetrics := metrics.NewHaMetrics()
+	haMetrics.RecordLeader(false)
+
+	return &Elector{
+		logger: logger.With(
+			zap.String("module", "ha"),
+			zap.String("holder", cfg.HolderIdentity),
+		),
+		metrics:  haMetrics,
+		cfg:      cfg,
+		lease:    lease,
+		leaderCh: make(chan struct{}),
+	}
+}
+
+// Start starts the election loop, the lease will be released when the ctx is done.
+func (e *Elector) Start(ctx context.Context) {
+	e.logger.Info("Starting the leader election",
+		zap.String("backend", e.cfg.Backend),
+		zap.String("lease", e.cfg.LeaseName),
+		zap.Duration("lease_duration", e.cfg.LeaseDuration),
+		zap.Duration("safety_margin", e.cfg.SafetyMargin))
+
+	go e.run(ctx)
+}
+
+func (e *Elector) run(ctx context.Context) {
+	defer e.close()
+
+	for {
+		interval := e.cfg.RetryInterval
+		if e.tryAcquireOrRenew(ctx) {
+			interval = e.cfg.RenewInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (e *Elector) tryAcquireOrRenew(ctx context.Context) bool {
+	// the lease should be renewed before it expired, or we should step down
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.LeaseDuration-e.cfg.RenewInterval)
+	defer cancel()
+
+	// the backend sets the expire time after this, so the lease is held until `start + lease_duration` at least
+	start := time.Now()
+	held, err := e.lease.TryAcquireOrRenew(ctx, e.cfg.HolderIdentity, e.cfg.LeaseDuration)
+	if err != nil {
+		e.metrics.RecordLeaseError()
+		e.logger.Error("failed to acquire or renew the lease", zap.Error(err))
+		// we can not make sure we still hold the lease
+		held = false
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if held {
+		e.renewedAt = start
+		// the renew took too long, the lease may be expired already
+		held = e.leaseValid(time.Now())
+	}
+	e.setLeader(held)
+
+	return held
+}
+
+// close releases the lease, and closes the lease backend if it holds a connection.
+func (e *Elector) close() {
+	e.release()
+
+	if closer, ok := e.lease.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			e.logger.Error("failed to close the lease", zap.Error(err))
+		}
+	}
+}
+
+func (e *Elector) release() {
+	e.mu.Lock()
+	held := e.isLeader
+	e.setLeader(false)
+	e.mu.Unlock()
+
+	if !held {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), releaseLeaseTimeout)
+	defer cancel()
+
+	if err := e.lease.Release(ctx, e.cfg.HolderIdentity); err != nil {
+		e.logger.Error("failed to release the lease", zap.Error(err))
+		return
+	}
+
+	e.logger.Info("released the lease")
+}
+
+// setLeader changes the leader state, the caller should hold the lock.
+func (e *Elector) setLeader(isLeader bool) {
+	if e.isLeader == isLeader {
+		return
+	}
+
+	e.isLeader = isLeader
+	e.metrics.RecordLeader(isLeader)
+	e.metrics.RecordLeaseTransition()
+
+	if isLeader {
+		e.term++
+		e.logger.Info("became the active operator", zap.Uint64("term", e.term))
+		close(e.leaderCh)
+	} else {
+		e.logger.Warn("lost the lease, became the standby operator")
+		e.leaderCh = make(chan struct{})
+	}
+}
+
+// leaseValid returns true if the lease is held at now, the lease is treated as expired
+// `safety_margin` before it expired, for the clock drift and the delay of the signing.
+func (e *Elector) leaseValid(now time.Time) bool {
+	return now.Before(e.renewedAt.Add(e.cfg.LeaseDuration - e.cfg.SafetyMargin))
+}
+
+// IsLeader returns true if this operator is the active one, and the lease is not near to expire,
+// so a hanging renew will not keep the operator signing after the standby took over.
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.checkLeader()
+}
+
+// checkLeader steps down if the lease is near to expire, the caller should hold the lock.
+func (e *Elector) checkLeader() bool {
+	if e.isLeader && !e.leaseValid(time.Now()) {
+		e.logger.Warn("the lease is not renewed in time, step down before it expired",
+			zap.Time("renewed_at", e.renewedAt))
+		e.setLeader(false)
+	}
+
+	return e.isLeader
+}
+
+// Term returns the number of times this operator became the leader,
+// the state read before the term changed may be changed by the previous leader.
+func (e *Elector) Term() uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.term
+}
+
+// WaitLeader blocks until this operator is the active one.
+func (e *Elector) WaitLeader(ctx context.Context) error {
+	for {
+		e.mu.Lock()
+		if e.checkLeader() {
+			e.mu.Unlock()
+			return nil
+		}
+		ch := e.leaderCh
+		e.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ch:
+		}
+	}
+}
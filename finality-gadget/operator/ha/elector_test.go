@@ -0,0 +1,157 @@
+package ha
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// fakeLease returns the configured result, and blocks for `delay` in each call.
+type fakeLease struct {
+	mu       sync.Mutex
+	held     bool
+	err      error
+	delay    time.Duration
+	released bool
+}
+
+func (l *fakeLease) set(held bool, err error, delay time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.held, l.err, l.delay = held, err, delay
+}
+
+func (l *fakeLease) TryAcquireOrRenew(_ context.Context, _ string, _ time.Duration) (bool, error) {
+	l.mu.Lock()
+	held, err, delay := l.held, l.err, l.delay
+	l.mu.Unlock()
+
+	time.Sleep(delay)
+	return held, err
+}
+
+func (l *fakeLease) Release(_ context.Context, _ string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.released = true
+	return nil
+}
+
+func newTestElector(t *testing.T, lease Lease) *Elector {
+	return NewElector(zaptest.NewLogger(t), Config{
+		Enabled:        true,
+		HolderIdentity: "operator-1",
+		LeaseDuration:  300 * time.Millisecond,
+		RenewInterval:  50 * time.Millisecond,
+		RetryInterval:  50 * time.Millisecond,
+		SafetyMargin:   100 * time.Millisecond,
+	}, lease)
+}
+
+func TestElectorSafetyMargin(t *testing.T) {
+	lease := &fakeLease{held: true}
+	e := newTestElector(t, lease)
+
+	if !e.tryAcquireOrRenew(context.Background()) || !e.IsLeader() {
+		t.Fatal("should be the leader after acquired")
+	}
+
+	// not renewed, step down `safety_margin` before the lease expired
+	time.Sleep(150 * time.Millisecond)
+	if !e.IsLeader() {
+		t.Fatal("should be the leader before the safety margin")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if e.IsLeader() {
+		t.Fatal("should step down in the safety margin")
+	}
+}
+
+func TestElectorSlowRenew(t *testing.T) {
+	lease := &fakeLease{held: true}
+	e := newTestElector(t, lease)
+
+	// the renew returned after `lease_duration - safety_margin`, the lease may be taken over already
+	lease.set(true, nil, 220*time.Millisecond)
+	if e.tryAcquireOrRenew(context.Background()) || e.IsLeader() {
+		t.Fatal("should not be the leader after a slow renew")
+	}
+}
+
+func TestElectorTerm(t *testing.T) {
+	lease := &fakeLease{}
+	e := newTestElector(t, lease)
+	ctx := context.Background()
+
+	if e.tryAcquireOrRenew(ctx) || e.Term() != 0 {
+		t.Fatalf("should be standby with term 0, got term %d", e.Term())
+	}
+
+	lease.set(true, nil, 0)
+	e.tryAcquireOrRenew(ctx)
+	e.tryAcquireOrRenew(ctx)
+	if !e.IsLeader() || e.Term() != 1 {
+		t.Fatalf("renew should keep the term 1, got %d", e.Term())
+	}
+
+	// the error steps down, as the lease may be lost
+	lease.set(true, errors.New("unavailable"), 0)
+	if e.tryAcquireOrRenew(ctx) || e.IsLeader() {
+		t.Fatal("should step down on error")
+	}
+
+	lease.set(true, nil, 0)
+	e.tryAcquireOrRenew(ctx)
+	if !e.IsLeader() || e.Term() != 2 {
+		t.Fatalf("should be term 2 after acquired again, got %d", e.Term())
+	}
+}
+
+func TestElectorWaitLeader(t *testing.T) {
+	lease := &fakeLease{}
+	e := newTestElector(t, lease)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.Start(ctx)
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer waitCancel()
+	if err := e.WaitLeader(waitCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("should wait while standby, got %v", err)
+	}
+
+	lease.set(true, nil, 0)
+	waitCtx, waitCancel = context.WithTimeout(ctx, time.Second)
+	defer waitCancel()
+	if err := e.WaitLeader(waitCtx); err != nil {
+		t.Fatalf("should be the leader: %v", err)
+	}
+
+	// the lease is released when the ctx is done
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for {
+		lease.mu.Lock()
+		released := lease.released
+		lease.mu.Unlock()
+
+		if released {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the lease is not released")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if e.IsLeader() {
+		t.Fatal("should not be the leader after released")
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package ha
+
+import (
+	"fmt"
+)
+
+func NewFileLease(path string) (Lease, error) {
+	return nil, fmt.Errorf("the file lease is not supported on this platform")
+}
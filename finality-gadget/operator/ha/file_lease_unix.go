@@ -0,0 +1,118 @@
+//go:build unix
+
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// fileLockRetryInterval is the interval to retry locking the lease file held by a peer.
+const fileLockRetryInterval = 50 * time.Millisecond
+
+// fileLease holds the lease by a json record in a file, the file is locked by `flock` during update,
+// so the operators which share the file system will not update it at the same time.
+type fileLease struct {
+	path string
+}
+
+func NewFileLease(path string) (Lease, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, errors.Wrapf(err, "failed to create the dir for lease file %s", path)
+	}
+
+	return &fileLease{path: path}, nil
+}
+
+func (l *fileLease) TryAcquireOrRenew(ctx context.Context, holder string, duration time.Duration) (bool, error) {
+	var held bool
+
+	err := l.update(ctx, func(r *leaseRecord) bool {
+		held = r.update(holder, duration, time.Now())
+		return held
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return held, nil
+}
+
+func (l *fileLease) Release(ctx context.Context, holder string) error {
+	return l.update(ctx, func(r *leaseRecord) bool {
+		return r.release(holder)
+	})
+}
+
+// update locks the file and calls f with the record, the record will be written back if f returns true.
+func (l *fileLease) update(ctx context.Context, f func(r *leaseRecord) bool) error {
+	file, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open lease file %s", l.path)
+	}
+	defer file.Close()
+
+	if err := lockFile(ctx, file); err != nil {
+		return errors.Wrapf(err, "failed to lock lease file %s", l.path)
+	}
+	defer syscall.Flock(int(file.Fd()), syscall.LOCK_UN) //nolint:errcheck
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read lease file %s", l.path)
+	}
+
+	var record leaseRecord
+	if len(data) != 0 {
+		if err := json.Unmarshal(data, &record); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal lease file %s", l.path)
+		}
+	}
+
+	if !f(&record) {
+		return nil
+	}
+
+	data, err = json.Marshal(&record)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal lease record")
+	}
+
+	if err := file.Truncate(0); err != nil {
+		return errors.Wrapf(err, "failed to truncate lease file %s", l.path)
+	}
+
+	if _, err := file.WriteAt(data, 0); err != nil {
+		return errors.Wrapf(err, "failed to write lease file %s", l.path)
+	}
+
+	return errors.Wrapf(file.Sync(), "failed to sync lease file %s", l.path)
+}
+
+// lockFile locks the file without blocking, and retries until the ctx is done,
+// so a peer holding the lock will not block the renew after the lease expired.
+func lockFile(ctx context.Context, file *os.File) error {
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, syscall.EWOULDBLOCK) && !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(fileLockRetryInterval):
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package ha
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const httpLeaseRequestTimeout = 10 * time.Second
+
+// microTimeFormat is the time format of the `MicroTime` in Kubernetes api.
+const microTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// httpLease holds the lease by the `coordination.k8s.io/v1` Lease api,
+// it works with the Kubernetes api server or any compatible server.
+type httpLease struct {
+	client    *http.Client
+	url       string
+	namespace string
+	name      string
+	tokenFile string
+}
+
+type k8sLeaseMetadata struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+type k8sLeaseSpec struct {
+	HolderIdentity       *string `json:"holderIdentity,omitempty"`
+	LeaseDurationSeconds *int32  `json:"leaseDurationSeconds,omitempty"`
+	AcquireTime          *string `json:"acquireTime,omitempty"`
+	RenewTime            *string `json:"renewTime,omitempty"`
+	LeaseTransitions     *int32  `json:"leaseTransitions,omitempty"`
+}
+
+type k8sLease struct {
+	ApiVersion string           `json:"apiVersion"`
+	Kind       string           `json:"kind"`
+	Metadata   k8sLeaseMetadata `json:"metadata"`
+	Spec       k8sLeaseSpec     `json:"spec"`
+}
+
+func NewHttpLease(cfg *HttpLeaseConfig, name string) (Lease, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.CAFile != "" {
+		ca, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the ca file %s", cfg.CAFile)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificate found in the ca file %s", cfg.CAFile)
+		}
+
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	return &httpLease{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   httpLeaseRequestTimeout,
+		},
+		url:       strings.TrimSuffix(cfg.Url, "/"),
+		namespace: cfg.Namespace,
+		name:      name,
+		tokenFile: cfg.TokenFile,
+	}, nil
+}
+
+func (l *httpLease) TryAcquireOrRenew(ctx context.Context, holder string, duration time.Duration) (bool, error) {
+	now := time.Now()
+
+	lease, err := l.get(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if lease == nil {
+		lease = &k8sLease{
+			ApiVersion: "coordination.k8s.io/v1",
+			Kind:       "Lease",
+			Metadata: k8sLeaseMetadata{
+				Name:      l.name,
+				Namespace: l.namespace,
+			},
+		}
+		l.updateSpec(&lease.Spec, holder, duration, now)
+
+		return l.write(ctx, http.MethodPost, l.leasesUrl(), lease)
+	}
+
+	if !l.updateSpec(&lease.Spec, holder, duration, now) {
+		return false, nil
+	}
+
+	return l.write(ctx, http.MethodPut, l.leaseUrl(), lease)
+}
+
+func (l *httpLease) Release(ctx context.Context, holder string) error {
+	lease, err := l.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	if lease == nil || lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holder {
+		return nil
+	}
+
+	empty := ""
+	lease.Spec.HolderIdentity = &empty
+
+	_, err = l.write(ctx, http.MethodPut, l.leaseUrl(), lease)
+	return err
+}
+
+// updateSpec acquires or renews the lease spec, returns false if the lease is held by others.
+func (l *httpLease) updateSpec(spec *k8sLeaseSpec, holder string, duration time.Duration, now time.Time) bool {
+	current := ""
+	if spec.HolderIdentity != nil {
+		current = *spec.HolderIdentity
+	}
+
+	if current != "" && current != holder && !leaseSpecExpired(spec, now) {
+		return false
+	}
+
+	nowStr := now.UTC().Format(microTimeFormat)
+	seconds := int32((duration + time.Second - 1) / time.Second)
+
+	if current != holder {
+		var transitions int32
+		if spec.LeaseTransitions != nil {
+			transitions = *spec.LeaseTransitions
+		}
+		if current != "" {
+			transitions++
+		}
+
+		spec.HolderIdentity = &holder
+		spec.AcquireTime = &nowStr
+		spec.LeaseTransitions = &transitions
+	}
+
+	spec.RenewTime = &nowStr
+	spec.LeaseDurationSeconds = &seconds
+
+	return true
+}
+
+func leaseSpecExpired(spec *k8sLeaseSpec, now time.Time) bool {
+	if spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+		return true
+	}
+
+	renewTime, err := time.Parse(time.RFC3339Nano, *spec.RenewTime)
+	if err != nil {
+		return true
+	}
+
+	return !now.Before(renewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second))
+}
+
+func (l *httpLease) leasesUrl() string {
+	return fmt.Sprintf("%s/apis/coordination.k8s.io/v1/namespaces/%s/leases", l.url, l.namespace)
+}
+
+func (l *httpLease) leaseUrl() string {
+	return fmt.Sprintf("%s/%s", l.leasesUrl(), l.name)
+}
+
+// get returns nil if the lease not exists.
+func (l *httpLease) get(ctx context.Context) (*k8sLease, error) {
+	resp, err := l.do(ctx, http.MethodGet, l.leaseUrl(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the lease response")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get lease failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var lease k8sLease
+	if err := json.Unmarshal(body, &lease); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the lease")
+	}
+
+	return &lease, nil
+}
+
+// write creates or updates the lease, returns false if conflicted with others.
+func (l *httpLease) write(ctx context.Context, method, url string, lease *k8sLease) (bool, error) {
+	data, err := json.Marshal(lease)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to marshal the lease")
+	}
+
+	resp, err := l.do(ctx, method, url, data)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		return true, nil
+	case http.StatusConflict:
+		// others updated the lease at the same time
+		return false, nil
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("%s lease failed with status %d: %s", method, resp.StatusCode, string(body))
+	}
+}
+
+func (l *httpLease) do(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the lease request")
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if l.tokenFile != "" {
+		// the token may be rotated, so read it for each request
+		token, err := os.ReadFile(l.tokenFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the token file %s", l.tokenFile)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to %s lease", method)
+	}
+
+	return resp, nil
+}
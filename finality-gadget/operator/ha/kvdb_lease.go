@@ -0,0 +1,135 @@
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/kvdb/etcd"
+	"github.com/lightningnetwork/lnd/kvdb/postgres"
+	"github.com/pkg/errors"
+)
+
+// kvdbLeasePrefix is the table prefix of the lease in postgres.
+const kvdbLeasePrefix = "blitz_ha"
+
+var leaseBucketName = []byte("blitz-ha-lease")
+
+// kvdbLease holds the lease in a kvdb shared by the operators, the record is updated in a transaction,
+// so the operators will not update it at the same time.
+type kvdbLease struct {
+	db   kvdb.Backend
+	name []byte
+}
+
+// NewKvdbLease opens the shared kvdb for the lease, the etcd and postgres drivers are only registered
+// when the operator is built with the `kvdb_etcd` or `kvdb_postgres` tag.
+func NewKvdbLease(cfg *KvdbLeaseConfig, name string) (Lease, error) {
+	var (
+		db  kvdb.Backend
+		err error
+	)
+
+	// the db is used until the operator exits
+	ctx := context.Background()
+
+	switch cfg.Db {
+	case KvdbEtcd:
+		db, err = kvdb.Open(kvdb.EtcdBackendName, ctx, &etcd.Config{
+			Host:               cfg.Etcd.Host,
+			User:               cfg.Etcd.User,
+			Pass:               cfg.Etcd.Pass,
+			Namespace:          cfg.Etcd.Namespace,
+			DisableTLS:         cfg.Etcd.DisableTLS,
+			CertFile:           cfg.Etcd.CertFile,
+			KeyFile:            cfg.Etcd.KeyFile,
+			InsecureSkipVerify: cfg.Etcd.InsecureSkipVerify,
+		})
+	case KvdbPostgres:
+		db, err = kvdb.Open(kvdb.PostgresBackendName, ctx, &postgres.Config{
+			Dsn:     cfg.Postgres.Dsn,
+			Timeout: cfg.Postgres.Timeout,
+		}, kvdbLeasePrefix)
+	default:
+		return nil, errors.Errorf("unknown kvdb %s for the lease", cfg.Db)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the %s kvdb, the operator should be built with the `kvdb_%s` tag", cfg.Db, cfg.Db)
+	}
+
+	lease, err := newKvdbLease(db, name)
+	if err != nil {
+		db.Close() //nolint:errcheck
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+func newKvdbLease(db kvdb.Backend, name string) (*kvdbLease, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(leaseBucketName)
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the lease bucket")
+	}
+
+	return &kvdbLease{db: db, name: []byte(name)}, nil
+}
+
+// TryAcquireOrRenew does not stop by the ctx, the elector steps down if it did not return in time.
+func (l *kvdbLease) TryAcquireOrRenew(_ context.Context, holder string, duration time.Duration) (bool, error) {
+	var held bool
+
+	err := l.update(func(r *leaseRecord) bool {
+		held = r.update(holder, duration, time.Now())
+		return held
+	}, func() { held = false })
+	if err != nil {
+		return false, err
+	}
+
+	return held, nil
+}
+
+func (l *kvdbLease) Release(_ context.Context, holder string) error {
+	return l.update(func(r *leaseRecord) bool {
+		return r.release(holder)
+	}, func() {})
+}
+
+// Close closes the kvdb.
+func (l *kvdbLease) Close() error {
+	return l.db.Close()
+}
+
+// update calls f with the record in a transaction, the record will be written back if f returns true,
+// the reset is called before the transaction is retried.
+func (l *kvdbLease) update(f func(r *leaseRecord) bool, reset func()) error {
+	return kvdb.Update(l.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(leaseBucketName)
+		if bucket == nil {
+			return errors.New("no lease bucket")
+		}
+
+		var record leaseRecord
+		if data := bucket.Get(l.name); len(data) != 0 {
+			if err := json.Unmarshal(data, &record); err != nil {
+				return errors.Wrap(err, "failed to unmarshal lease record")
+			}
+		}
+
+		if !f(&record) {
+			return nil
+		}
+
+		data, err := json.Marshal(&record)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal lease record")
+		}
+
+		return bucket.Put(l.name, data)
+	}, reset)
+}
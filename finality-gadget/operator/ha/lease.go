@@ -0,0 +1,54 @@
+package ha
+
+import (
+	"context"
+	"time"
+)
+
+// Lease is the lock shared by the operators, only one holder can hold it until expired.
+type Lease interface {
+	// TryAcquireOrRenew acquires the lease if it is free or expired, or renews it if it is held by the holder,
+	// returns true if the holder holds the lease after this call.
+	TryAcquireOrRenew(ctx context.Context, holder string, duration time.Duration) (bool, error)
+	// Release releases the lease if it is held by the holder.
+	Release(ctx context.Context, holder string) error
+}
+
+// leaseRecord is the lease state stored by the file and kvdb backends.
+type leaseRecord struct {
+	Holder      string    `json:"holder"`
+	AcquireTime time.Time `json:"acquire_time"`
+	RenewTime   time.Time `json:"renew_time"`
+	ExpireTime  time.Time `json:"expire_time"`
+	Transitions uint64    `json:"transitions"`
+}
+
+// update tries to acquire or renew the record for the holder, returns true if the holder holds it.
+func (r *leaseRecord) update(holder string, duration time.Duration, now time.Time) bool {
+	if r.Holder != "" && r.Holder != holder && now.Before(r.ExpireTime) {
+		return false
+	}
+
+	if r.Holder != holder {
+		r.Holder = holder
+		r.AcquireTime = now
+		r.Transitions++
+	}
+
+	r.RenewTime = now
+	r.ExpireTime = now.Add(duration)
+
+	return true
+}
+
+// release releases the record if it is held by the holder, returns true if changed.
+func (r *leaseRecord) release(holder string) bool {
+	if r.Holder != holder {
+		return false
+	}
+
+	r.Holder = ""
+	r.ExpireTime = time.Time{}
+
+	return true
+}
@@ -0,0 +1,131 @@
+package ha
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// testLease checks the lease is held by one holder until expired or released.
+func testLease(t *testing.T, lease Lease) {
+	ctx := context.Background()
+	duration := 200 * time.Millisecond
+
+	acquire := func(holder string, want bool) {
+		t.Helper()
+
+		held, err := lease.TryAcquireOrRenew(ctx, holder, duration)
+		if err != nil {
+			t.Fatalf("acquire by %s: %v", holder, err)
+		}
+		if held != want {
+			t.Fatalf("acquire by %s got %v, want %v", holder, held, want)
+		}
+	}
+
+	acquire("a", true)
+	acquire("b", false)
+	// renew
+	acquire("a", true)
+
+	// the expired lease can be taken over
+	time.Sleep(duration + 50*time.Millisecond)
+	acquire("b", true)
+	acquire("a", false)
+
+	// only the holder can release it
+	if err := lease.Release(ctx, "a"); err != nil {
+		t.Fatalf("release by a: %v", err)
+	}
+	acquire("a", false)
+
+	if err := lease.Release(ctx, "b"); err != nil {
+		t.Fatalf("release by b: %v", err)
+	}
+	acquire("a", true)
+}
+
+func TestFileLease(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the file lease is not supported")
+	}
+
+	lease, err := NewFileLease(filepath.Join(t.TempDir(), "ha", "operator.lease"))
+	if err != nil {
+		t.Fatalf("new file lease: %v", err)
+	}
+
+	testLease(t, lease)
+}
+
+func TestFileLeaseShared(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the file lease is not supported")
+	}
+
+	// the operators open the same file by their own lease
+	path := filepath.Join(t.TempDir(), "operator.lease")
+	a, err := NewFileLease(path)
+	if err != nil {
+		t.Fatalf("new file lease: %v", err)
+	}
+	b, err := NewFileLease(path)
+	if err != nil {
+		t.Fatalf("new file lease: %v", err)
+	}
+
+	ctx := context.Background()
+	if held, err := a.TryAcquireOrRenew(ctx, "a", time.Minute); err != nil || !held {
+		t.Fatalf("acquire by a got %v, %v", held, err)
+	}
+	if held, err := b.TryAcquireOrRenew(ctx, "b", time.Minute); err != nil || held {
+		t.Fatalf("acquire by b got %v, %v", held, err)
+	}
+}
+
+func TestKvdbLease(t *testing.T) {
+	// the etcd and postgres kvdb need the build tags, the lease logic is the same on bbolt
+	db, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
+		DBPath:         t.TempDir(),
+		DBFileName:     "lease.db",
+		NoFreelistSync: true,
+		DBTimeout:      time.Second,
+	})
+	if err != nil {
+		t.Fatalf("open bolt: %v", err)
+	}
+
+	lease, err := newKvdbLease(db, "blitz-operator")
+	if err != nil {
+		t.Fatalf("new kvdb lease: %v", err)
+	}
+	defer lease.Close()
+
+	testLease(t, lease)
+}
+
+func TestKvdbLeaseConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     KvdbLeaseConfig
+		wantErr bool
+	}{
+		{name: "etcd", cfg: KvdbLeaseConfig{Db: KvdbEtcd, Etcd: EtcdLeaseConfig{Host: "127.0.0.1:2379"}}},
+		{name: "etcd without host", cfg: KvdbLeaseConfig{Db: KvdbEtcd}, wantErr: true},
+		{name: "postgres", cfg: KvdbLeaseConfig{Db: KvdbPostgres, Postgres: PostgresLeaseConfig{Dsn: "postgres://127.0.0.1/blitz"}}},
+		{name: "postgres without dsn", cfg: KvdbLeaseConfig{Db: KvdbPostgres}, wantErr: true},
+		{name: "local bbolt", cfg: KvdbLeaseConfig{Db: "bdb"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("validate err %v, want err %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package processers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
+)
+
+const (
+	// ModeBlock will emit a event for each l2 block the handler fetched.
+	ModeBlock = "block"
+	// ModeFinalized will emit a event only after the l2 block is finalized by babylon.
+	ModeFinalized = "finalized"
+)
+
+const (
+	defaultFinalizedPollInterval = 3 * time.Second
+
+	defaultWebhookTimeout       = 10 * time.Second
+	defaultWebhookMaxRetries    = 3
+	defaultWebhookRetryInterval = 2 * time.Second
+	defaultWebhookQueueSize     = 1024
+
+	defaultFileMaxSizeMB  = 100
+	defaultFileMaxBackups = 10
+)
+
+type Config struct {
+	// The webhooks to POST the block events
+	Webhooks []WebhookConfig `yaml:"webhooks,omitempty"`
+	// The files to append the block events as newline-delimited json
+	Files []FileSinkConfig `yaml:"files,omitempty"`
+	// The NATS subjects to publish the block events
+	Nats []NatsConfig `yaml:"nats,omitempty"`
+	// The interval to poll the babylon finalized height for the processers in `finalized` mode, default is 3s
+	FinalizedPollInterval time.Duration `yaml:"finalized_poll_interval,omitempty"`
+}
+
+// IsEmpty returns true if no processer is configured.
+func (c *Config) IsEmpty() bool {
+	return len(c.Webhooks) == 0 && len(c.Files) == 0 && len(c.Nats) == 0
+}
+
+// NeedFinalized returns true if any processer need the babylon finalized state.
+func (c *Config) NeedFinalized() bool {
+	for _, w := range c.Webhooks {
+		if w.Mode == ModeFinalized {
+			return true
+		}
+	}
+
+	for _, f := range c.Files {
+		if f.Mode == ModeFinalized {
+			return true
+		}
+	}
+
+	for _, n := range c.Nats {
+		if n.Mode == ModeFinalized {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Config) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.FinalizedPollInterval < 0 {
+		errs.Addf("finalized_poll_interval", "should not be negative")
+	}
+
+	names := make(map[string]struct{}, len(c.Webhooks)+len(c.Files)+len(c.Nats))
+	checkName := func(field, name string) {
+		if name == "" {
+			errs.Addf(field+".name", "required")
+			return
+		}
+
+		if _, ok := names[name]; ok {
+			errs.Addf(field+".name", "the processer name %s is duplicated", name)
+		}
+		names[name] = struct{}{}
+	}
+
+	for i, w := range c.Webhooks {
+		field := fmt.Sprintf("webhooks[%d]", i)
+		checkName(field, w.Name)
+		errs.Merge(field, w.Validate())
+	}
+
+	for i, f := range c.Files {
+		field := fmt.Sprintf("files[%d]", i)
+		checkName(field, f.Name)
+		errs.Merge(field, f.Validate())
+	}
+
+	for i, n := range c.Nats {
+		field := fmt.Sprintf("nats[%d]", i)
+		checkName(field, n.Name)
+		errs.Merge(field, n.Validate())
+	}
+
+	return errs.Err()
+}
+
+func validateMode(errs *utils.ConfigErrors, mode string) {
+	switch mode {
+	case "", ModeBlock, ModeFinalized:
+	default:
+		errs.Addf("mode", "unknown mode %s, should be `%s` or `%s`", mode, ModeBlock, ModeFinalized)
+	}
+}
+
+type WebhookConfig struct {
+	// The name of the processer, should be unique
+	Name string `yaml:"name"`
+	// The mode for the events, `block` or `finalized`, default is `block`
+	Mode string `yaml:"mode,omitempty"`
+	// The url to POST the json event
+	Url string `yaml:"url"`
+	// The secret to sign the body by HMAC-SHA256, if empty, no signature header
+	Secret string `yaml:"secret,omitempty" secret:"true"`
+	// The extra headers for each request
+	Headers map[string]string `yaml:"headers,omitempty" secret:"true"`
+	// The timeout for each request
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// The max retries count for a event, default is 3 if unset, 0 to disable the retries
+	MaxRetries *int `yaml:"max_retries,omitempty"`
+	// The interval between the retries, will be doubled by each retry
+	RetryInterval time.Duration `yaml:"retry_interval,omitempty"`
+	// The max pending events count, the new events will be dropped if full
+	QueueSize int `yaml:"queue_size,omitempty"`
+}
+
+type FileSinkConfig struct {
+	// The name of the processer, should be unique
+	Name string `yaml:"name"`
+	// The mode for the events, `block` or `finalized`, default is `block`
+	Mode string `yaml:"mode,omitempty"`
+	// The path of the file to append
+	Path string `yaml:"path"`
+	// The max size in megabytes of the file before it gets rotated
+	MaxSizeMB int `yaml:"max_size_mb,omitempty"`
+	// The max number of old files to retain
+	MaxBackups int `yaml:"max_backups,omitempty"`
+	// The max number of days to retain old files, 0 means no limit
+	MaxAgeDays int `yaml:"max_age_days,omitempty"`
+	// Compress the rotated files by gzip
+	Compress bool `yaml:"compress,omitempty"`
+}
+
+type NatsConfig struct {
+	// The name of the processer, should be unique
+	Name string `yaml:"name"`
+	// The mode for the events, `block` or `finalized`, default is `block`
+	Mode string `yaml:"mode,omitempty"`
+	// The NATS server urls, split by `,`
+	Url string `yaml:"url"`
+	// The subject to publish
+	Subject string `yaml:"subject"`
+	// The token for auth
+	Token string `yaml:"token,omitempty" secret:"true"`
+	// The user for auth
+	User string `yaml:"user,omitempty"`
+	// The password for auth
+	Password string `yaml:"password,omitempty" secret:"true"`
+	// The credentials file for auth
+	CredentialsFile string `yaml:"credentials_file,omitempty"`
+}
+
+func (c *WebhookConfig) Validate() error {
+	var errs utils.ConfigErrors
+	validateMode(&errs, c.Mode)
+
+	if c.Url == "" {
+		errs.Addf("url", "required")
+	} else {
+		errs.Add("url", utils.CheckURL(c.Url, "http", "https"))
+	}
+
+	if c.Timeout < 0 {
+		errs.Addf("timeout", "should not be negative")
+	}
+
+	if c.MaxRetries != nil && *c.MaxRetries < 0 {
+		errs.Addf("max_retries", "should not be negative")
+	}
+
+	if c.RetryInterval < 0 {
+		errs.Addf("retry_interval", "should not be negative")
+	}
+
+	if c.QueueSize < 0 {
+		errs.Addf("queue_size", "should not be negative")
+	}
+
+	return errs.Err()
+}
+
+func (c *FileSinkConfig) Validate() error {
+	var errs utils.ConfigErrors
+	validateMode(&errs, c.Mode)
+
+	if c.Path == "" {
+		errs.Addf("path", "required")
+	}
+
+	if c.MaxSizeMB < 0 {
+		errs.Addf("max_size_mb", "should not be negative")
+	}
+
+	if c.MaxBackups < 0 {
+		errs.Addf("max_backups", "should not be negative")
+	}
+
+	if c.MaxAgeDays < 0 {
+		errs.Addf("max_age_days", "should not be negative")
+	}
+
+	return errs.Err()
+}
+
+func (c *NatsConfig) Validate() error {
+	var errs utils.ConfigErrors
+	validateMode(&errs, c.Mode)
+
+	if c.Url == "" {
+		errs.Addf("url", "required")
+	}
+
+	// the url without scheme is `nats://` by default
+	for _, u := range strings.Split(c.Url, ",") {
+		if u = strings.TrimSpace(u); strings.Contains(u, "://") {
+			errs.Add("url", utils.CheckURL(u, "nats", "tls", "ws", "wss"))
+		}
+	}
+
+	if c.Subject == "" {
+		errs.Addf("subject", "required")
+	}
+
+	if c.Token != "" && c.User != "" {
+		errs.Addf("token", "the token and user should not be set together")
+	}
+
+	return errs.Err()
+}
@@ -0,0 +1,42 @@
+package processers
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	EventTypeBlock          = "block"
+	EventTypeFinalizedBlock = "finalized_block"
+)
+
+// BlockEvent is the json payload the processers send for a l2 block.
+type BlockEvent struct {
+	Type       string `json:"type"`
+	ChainId    uint64 `json:"chain_id"`
+	Number     uint64 `json:"number"`
+	Hash       string `json:"hash"`
+	ParentHash string `json:"parent_hash"`
+	Timestamp  uint64 `json:"timestamp"`
+	TxCount    int    `json:"tx_count"`
+	// The babylon finalized height when the event is emitted, only for `finalized_block`.
+	FinalizedHeight uint64 `json:"finalized_height,omitempty"`
+}
+
+func NewBlockEvent(chainId uint64, blk *types.Block) *BlockEvent {
+	return &BlockEvent{
+		Type:       EventTypeBlock,
+		ChainId:    chainId,
+		Number:     blk.NumberU64(),
+		Hash:       blk.Hash().Hex(),
+		ParentHash: blk.ParentHash().Hex(),
+		Timestamp:  blk.Time(),
+		TxCount:    len(blk.Transactions()),
+	}
+}
+
+func NewFinalizedBlockEvent(chainId uint64, blk *types.Block, finalizedHeight uint64) *BlockEvent {
+	res := NewBlockEvent(chainId, blk)
+	res.Type = EventTypeFinalizedBlock
+	res.FinalizedHeight = finalizedHeight
+	return res
+}
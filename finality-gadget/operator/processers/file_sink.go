@@ -0,0 +1,69 @@
+package processers
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// FileSink append the events into a file as newline-delimited json,
+// the file will be rotated by the size.
+type FileSink struct {
+	cfg    FileSinkConfig
+	writer *lumberjack.Logger
+	mu     sync.Mutex
+}
+
+var _ IEventSink = &FileSink{}
+
+func NewFileSink(cfg FileSinkConfig) (*FileSink, error) {
+	if cfg.Path == "" {
+		return nil, errors.Errorf("no path for file sink %s", cfg.Name)
+	}
+
+	if cfg.MaxSizeMB == 0 {
+		cfg.MaxSizeMB = defaultFileMaxSizeMB
+	}
+
+	if cfg.MaxBackups == 0 {
+		cfg.MaxBackups = defaultFileMaxBackups
+	}
+
+	return &FileSink{
+		cfg: cfg,
+		writer: &lumberjack.Logger{
+			Filename:   cfg.Path,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAgeDays,
+			Compress:   cfg.Compress,
+		},
+	}, nil
+}
+
+func (s *FileSink) Send(ctx context.Context, event *BlockEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "marshal event failed")
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.writer.Write(line); err != nil {
+		return errors.Wrapf(err, "write to %s failed", s.cfg.Path)
+	}
+
+	return nil
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.writer.Close()
+}
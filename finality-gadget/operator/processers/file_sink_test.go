@@ -0,0 +1,59 @@
+package processers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+
+	sink, err := NewFileSink(FileSinkConfig{Name: "file", Path: path})
+	if err != nil {
+		t.Fatalf("new file sink: %v", err)
+	}
+
+	events := []*BlockEvent{
+		NewBlockEvent(42, testBlock(1)),
+		NewFinalizedBlockEvent(42, testBlock(2), 5),
+	}
+	for _, e := range events {
+		if err := sink.Send(context.Background(), e); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+
+	var got []BlockEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e BlockEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line %q is not json: %v", scanner.Text(), err)
+		}
+		got = append(got, e)
+	}
+
+	if len(got) != len(events) {
+		t.Fatalf("got %d lines, want %d", len(got), len(events))
+	}
+
+	for i := range events {
+		if got[i] != *events[i] {
+			t.Fatalf("line %d: got %+v, want %+v", i, got[i], *events[i])
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package processers
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/alt-research/blitz/finality-gadget/core/logging"
+	"github.com/alt-research/blitz/finality-gadget/operator"
+)
+
+const maxPendingFinalizedBlocks = 8192
+
+// IFinalizedQuerier query the latest l2 block height finalized by babylon.
+type IFinalizedQuerier interface {
+	QueryFinalizedBlockInBabylon(ctx context.Context) (uint64, error)
+}
+
+// IEventSink is the sink to deliver the block events.
+type IEventSink interface {
+	Send(ctx context.Context, event *BlockEvent) error
+}
+
+// blockProcesser will send a event to the sink for each block.
+type blockProcesser struct {
+	chainId uint64
+	sink    IEventSink
+}
+
+var _ operator.IL2BlockProcesser = &blockProcesser{}
+
+func newBlockProcesser(chainId uint64, sink IEventSink) *blockProcesser {
+	return &blockProcesser{
+		chainId: chainId,
+		sink:    sink,
+	}
+}
+
+func (p *blockProcesser) OnBlock(ctx context.Context, blk *types.Block) error {
+	return p.sink.Send(ctx, NewBlockEvent(p.chainId, blk))
+}
+
+// finalizedProcesser will keep the blocks until it is finalized by babylon,
+// then send the event to the sink by the block number order.
+//
+// The finalized height is polled by the shared finalizedPoller, not by each block.
+type finalizedProcesser struct {
+	logger   logging.Logger
+	chainId  uint64
+	sink     IEventSink
+	pendings map[uint64]*types.Block
+	// the latest finalized height from the poller
+	finalized uint64
+	mu        sync.Mutex
+}
+
+var _ operator.IL2BlockProcesser = &finalizedProcesser{}
+
+func newFinalizedProcesser(
+	logger logging.Logger,
+	chainId uint64,
+	sink IEventSink) *finalizedProcesser {
+	return &finalizedProcesser{
+		logger:   logger,
+		chainId:  chainId,
+		sink:     sink,
+		pendings: make(map[uint64]*types.Block, 256),
+	}
+}
+
+func (p *finalizedProcesser) OnBlock(ctx context.Context, blk *types.Block) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.pendings) >= maxPendingFinalizedBlocks {
+		p.dropOldest()
+	}
+	p.pendings[blk.NumberU64()] = blk
+
+	// the block finalized already, like the blocks fetched from the back height
+	if blk.NumberU64() <= p.finalized {
+		return p.emit(ctx)
+	}
+
+	return nil
+}
+
+// onFinalized sends the pending blocks finalized by the finalized height,
+// the blocks failed to send last time are retried.
+func (p *finalizedProcesser) onFinalized(ctx context.Context, finalized uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if finalized > p.finalized {
+		p.finalized = finalized
+	}
+
+	return p.emit(ctx)
+}
+
+func (p *finalizedProcesser) emit(ctx context.Context) error {
+	numbers := make([]uint64, 0, len(p.pendings))
+	for number := range p.pendings {
+		if number <= p.finalized {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	for _, number := range numbers {
+		if err := p.sink.Send(ctx, NewFinalizedBlockEvent(p.chainId, p.pendings[number], p.finalized)); err != nil {
+			return errors.Wrapf(err, "failed to send finalized block %d", number)
+		}
+		delete(p.pendings, number)
+	}
+
+	return nil
+}
+
+func (p *finalizedProcesser) dropOldest() {
+	var oldest uint64
+	first := true
+	for number := range p.pendings {
+		if first || number < oldest {
+			oldest = number
+			first = false
+		}
+	}
+
+	p.logger.Warn("too many pending blocks not finalized, drop the oldest", "number", oldest)
+	delete(p.pendings, oldest)
+}
+
+// finalizedPoller queries the babylon finalized height by the interval, and emits the finalized blocks
+// of all the processers in `finalized` mode, so the finality is queried once for all of them.
+type finalizedPoller struct {
+	logger     logging.Logger
+	querier    IFinalizedQuerier
+	interval   time.Duration
+	processers []*finalizedProcesser
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newFinalizedPoller(logger logging.Logger, querier IFinalizedQuerier, interval time.Duration) *finalizedPoller {
+	return &finalizedPoller{
+		logger:   logger,
+		querier:  querier,
+		interval: interval,
+	}
+}
+
+func (p *finalizedPoller) add(processer *finalizedProcesser) {
+	p.processers = append(p.processers, processer)
+}
+
+func (p *finalizedPoller) start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			p.poll(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (p *finalizedPoller) poll(ctx context.Context) {
+	finalized, err := p.querier.QueryFinalizedBlockInBabylon(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.logger.Error("failed to query finalized block", "err", err)
+		}
+		return
+	}
+
+	for _, processer := range p.processers {
+		if err := processer.onFinalized(ctx, finalized); err != nil {
+			p.logger.Error("failed to emit finalized blocks", "finalized", finalized, "err", err)
+		}
+	}
+}
+
+// stop stops the polling and waits the last poll finished.
+func (p *finalizedPoller) stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+}
@@ -0,0 +1,86 @@
+package processers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+
+	"github.com/alt-research/blitz/finality-gadget/core/logging"
+)
+
+// NatsSink publish the events to a NATS subject.
+type NatsSink struct {
+	cfg  NatsConfig
+	conn *nats.Conn
+}
+
+var _ IEventSink = &NatsSink{}
+
+func NewNatsSink(logger logging.Logger, cfg NatsConfig) (*NatsSink, error) {
+	if cfg.Url == "" {
+		return nil, errors.Errorf("no url for nats %s", cfg.Name)
+	}
+
+	if cfg.Subject == "" {
+		return nil, errors.Errorf("no subject for nats %s", cfg.Name)
+	}
+
+	logger = logger.With("nats", cfg.Name)
+
+	opts := []nats.Option{
+		nats.Name("blitz-" + cfg.Name),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			logger.Warn("nats disconnected", "err", err)
+		}),
+		nats.ReconnectHandler(func(c *nats.Conn) {
+			logger.Info("nats reconnected", "url", c.ConnectedUrl())
+		}),
+	}
+
+	if cfg.Token != "" {
+		opts = append(opts, nats.Token(cfg.Token))
+	}
+
+	if cfg.User != "" {
+		opts = append(opts, nats.UserInfo(cfg.User, cfg.Password))
+	}
+
+	if cfg.CredentialsFile != "" {
+		opts = append(opts, nats.UserCredentials(cfg.CredentialsFile))
+	}
+
+	conn, err := nats.Connect(cfg.Url, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "connect to nats %s failed", cfg.Url)
+	}
+
+	return &NatsSink{
+		cfg:  cfg,
+		conn: conn,
+	}, nil
+}
+
+func (s *NatsSink) Send(ctx context.Context, event *BlockEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "marshal event failed")
+	}
+
+	if err := s.conn.Publish(s.cfg.Subject, data); err != nil {
+		return errors.Wrapf(err, "publish to %s failed", s.cfg.Subject)
+	}
+
+	return nil
+}
+
+func (s *NatsSink) Close() error {
+	if err := s.conn.Drain(); err != nil {
+		s.conn.Close()
+		return errors.Wrap(err, "drain nats conn failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package processers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// runNatsServer starts an in-process nats server on a random port.
+func runNatsServer(t *testing.T, token string) *natsserver.Server {
+	srv, err := natsserver.NewServer(&natsserver.Options{
+		Host:          "127.0.0.1",
+		Port:          natsserver.RANDOM_PORT,
+		NoLog:         true,
+		NoSigs:        true,
+		Authorization: token,
+	})
+	if err != nil {
+		t.Fatalf("new nats server: %v", err)
+	}
+
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+
+	return srv
+}
+
+func TestNatsSink(t *testing.T) {
+	const token = "the-token"
+	srv := runNatsServer(t, token)
+
+	conn, err := nats.Connect(srv.ClientURL(), nats.Token(token))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer conn.Close()
+
+	sub, err := conn.SubscribeSync("blitz.finalized")
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := conn.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	sink, err := NewNatsSink(testLogger(t), NatsConfig{
+		Name:    "nats",
+		Url:     srv.ClientURL(),
+		Subject: "blitz.finalized",
+		Token:   token,
+	})
+	if err != nil {
+		t.Fatalf("new nats sink: %v", err)
+	}
+
+	event := NewFinalizedBlockEvent(42, testBlock(9), 10)
+	if err := sink.Send(context.Background(), event); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	// drain flushes the published events
+	if err := sink.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	msg, err := sub.NextMsg(5 * time.Second)
+	if err != nil {
+		t.Fatalf("next msg: %v", err)
+	}
+
+	var got BlockEvent
+	if err := json.Unmarshal(msg.Data, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got != *event {
+		t.Fatalf("got %+v, want %+v", got, *event)
+	}
+}
+
+func TestNatsSinkAuthFailed(t *testing.T) {
+	srv := runNatsServer(t, "the-token")
+
+	_, err := NewNatsSink(testLogger(t), NatsConfig{
+		Name:    "nats",
+		Url:     srv.ClientURL(),
+		Subject: "blitz.finalized",
+		Token:   "wrong-token",
+	})
+	if err == nil {
+		t.Fatal("the wrong token should fail to connect")
+	}
+}
@@ -0,0 +1,147 @@
+package processers
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/alt-research/blitz/finality-gadget/core/logging"
+	"github.com/alt-research/blitz/finality-gadget/operator"
+)
+
+// Processers is the built-in processers created by the config.
+type Processers struct {
+	logger     logging.Logger
+	processers map[string]operator.IL2BlockProcesser
+	closers    map[string]io.Closer
+	// poller is nil if no processer in `finalized` mode
+	poller *finalizedPoller
+}
+
+// NewProcessersFromConfig creates the processers by config, the querier can be nil
+// if there is no processer in `finalized` mode.
+func NewProcessersFromConfig(
+	ctx context.Context,
+	logger logging.Logger,
+	cfg *Config,
+	chainId uint64,
+	querier IFinalizedQuerier,
+) (*Processers, error) {
+	res := &Processers{
+		logger:     logger.With("module", "processers"),
+		processers: make(map[string]operator.IL2BlockProcesser, 8),
+		closers:    make(map[string]io.Closer, 8),
+	}
+
+	if querier != nil {
+		interval := cfg.FinalizedPollInterval
+		if interval == 0 {
+			interval = defaultFinalizedPollInterval
+		}
+		res.poller = newFinalizedPoller(res.logger, querier, interval)
+	}
+
+	for _, c := range cfg.Webhooks {
+		sink, err := NewWebhookSink(ctx, res.logger, c)
+		if err != nil {
+			res.Close()
+			return nil, errors.Wrapf(err, "failed to create webhook %s", c.Name)
+		}
+
+		if err := res.add(c.Name, c.Mode, chainId, sink); err != nil {
+			res.Close()
+			return nil, err
+		}
+	}
+
+	for _, c := range cfg.Files {
+		sink, err := NewFileSink(c)
+		if err != nil {
+			res.Close()
+			return nil, errors.Wrapf(err, "failed to create file sink %s", c.Name)
+		}
+
+		if err := res.add(c.Name, c.Mode, chainId, sink); err != nil {
+			res.Close()
+			return nil, err
+		}
+	}
+
+	for _, c := range cfg.Nats {
+		sink, err := NewNatsSink(res.logger, c)
+		if err != nil {
+			res.Close()
+			return nil, errors.Wrapf(err, "failed to create nats %s", c.Name)
+		}
+
+		if err := res.add(c.Name, c.Mode, chainId, sink); err != nil {
+			res.Close()
+			return nil, err
+		}
+	}
+
+	if res.poller != nil && len(res.poller.processers) != 0 {
+		res.poller.start(ctx)
+	}
+
+	return res, nil
+}
+
+type closableSink interface {
+	IEventSink
+	io.Closer
+}
+
+func (p *Processers) add(name, mode string, chainId uint64, sink closableSink) error {
+	// close the sink first if we cannot use it
+	if name == "" {
+		sink.Close()
+		return errors.New("the processer name cannot be empty")
+	}
+
+	if _, ok := p.processers[name]; ok {
+		sink.Close()
+		return errors.Errorf("the processer name %s is duplicated", name)
+	}
+
+	switch mode {
+	case "", ModeBlock:
+		p.processers[name] = newBlockProcesser(chainId, sink)
+	case ModeFinalized:
+		if p.poller == nil {
+			sink.Close()
+			return errors.Errorf("the processer %s need the finalized querier", name)
+		}
+		processer := newFinalizedProcesser(p.logger.With("name", name), chainId, sink)
+		p.poller.add(processer)
+		p.processers[name] = processer
+	default:
+		sink.Close()
+		return errors.Errorf("unknown mode %s for processer %s", mode, name)
+	}
+
+	p.closers[name] = sink
+
+	return nil
+}
+
+// Register adds all processers into the block handler.
+func (p *Processers) Register(handler *operator.L2BlockHandler) {
+	for name, processer := range p.processers {
+		handler.AddProcesser(name, processer)
+	}
+}
+
+// Close stops the finalized poller and closes all the sinks.
+func (p *Processers) Close() {
+	if p.poller != nil {
+		p.poller.stop()
+	}
+
+	for name, closer := range p.closers {
+		if err := closer.Close(); err != nil {
+			p.logger.Error("close processer failed", "name", name, "err", err)
+		}
+	}
+}
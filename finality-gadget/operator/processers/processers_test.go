@@ -0,0 +1,182 @@
+package processers
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/alt-research/blitz/finality-gadget/core/logging"
+)
+
+func testLogger(t *testing.T) logging.Logger {
+	return logging.NewFromZap(zaptest.NewLogger(t))
+}
+
+func testBlock(number uint64) *types.Block {
+	return types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(number)})
+}
+
+// memorySink keeps the events sent.
+type memorySink struct {
+	events []*BlockEvent
+	mu     sync.Mutex
+}
+
+func (s *memorySink) Send(ctx context.Context, event *BlockEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
+
+func (s *memorySink) numbers() []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]uint64, 0, len(s.events))
+	for _, e := range s.events {
+		res = append(res, e.Number)
+	}
+	return res
+}
+
+// fixedQuerier returns the finalized height set, and counts the queries.
+type fixedQuerier struct {
+	finalized uint64
+	queries   int
+	mu        sync.Mutex
+}
+
+func (q *fixedQuerier) QueryFinalizedBlockInBabylon(ctx context.Context) (uint64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.queries++
+	return q.finalized, nil
+}
+
+func (q *fixedQuerier) set(finalized uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.finalized = finalized
+}
+
+func equalNumbers(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBlockProcesser(t *testing.T) {
+	ctx := context.Background()
+	sink := &memorySink{}
+	p := newBlockProcesser(42, sink)
+
+	for _, n := range []uint64{1, 2, 3} {
+		if err := p.OnBlock(ctx, testBlock(n)); err != nil {
+			t.Fatalf("on block %d: %v", n, err)
+		}
+	}
+
+	if got := sink.numbers(); !equalNumbers(got, []uint64{1, 2, 3}) {
+		t.Fatalf("unexpected events %v", got)
+	}
+
+	for _, e := range sink.events {
+		if e.Type != EventTypeBlock || e.ChainId != 42 {
+			t.Fatalf("unexpected event %+v", e)
+		}
+	}
+}
+
+func TestFinalizedPollerShared(t *testing.T) {
+	ctx := context.Background()
+	querier := &fixedQuerier{}
+	poller := newFinalizedPoller(testLogger(t), querier, 0)
+
+	sinks := []*memorySink{{}, {}}
+	processers := make([]*finalizedProcesser, 0, len(sinks))
+	for _, sink := range sinks {
+		p := newFinalizedProcesser(testLogger(t), 42, sink)
+		poller.add(p)
+		processers = append(processers, p)
+	}
+
+	// the blocks are kept until finalized, no query by the blocks
+	for _, p := range processers {
+		for _, n := range []uint64{3, 1, 2, 4} {
+			if err := p.OnBlock(ctx, testBlock(n)); err != nil {
+				t.Fatalf("on block %d: %v", n, err)
+			}
+		}
+	}
+	if querier.queries != 0 {
+		t.Fatalf("the blocks should not query the finality, got %d queries", querier.queries)
+	}
+
+	querier.set(3)
+	poller.poll(ctx)
+	if querier.queries != 1 {
+		t.Fatalf("one poll should query once for all the processers, got %d queries", querier.queries)
+	}
+
+	for i, sink := range sinks {
+		if got := sink.numbers(); !equalNumbers(got, []uint64{1, 2, 3}) {
+			t.Fatalf("sink %d: unexpected events %v", i, got)
+		}
+		for _, e := range sink.events {
+			if e.Type != EventTypeFinalizedBlock || e.FinalizedHeight != 3 {
+				t.Fatalf("sink %d: unexpected event %+v", i, e)
+			}
+		}
+	}
+
+	// the block under the finalized height is emitted at once
+	if err := processers[0].OnBlock(ctx, testBlock(2)); err != nil {
+		t.Fatalf("on block: %v", err)
+	}
+	if got := sinks[0].numbers(); !equalNumbers(got, []uint64{1, 2, 3, 2}) {
+		t.Fatalf("unexpected events %v", got)
+	}
+
+	querier.set(10)
+	poller.poll(ctx)
+	if got := sinks[1].numbers(); !equalNumbers(got, []uint64{1, 2, 3, 4}) {
+		t.Fatalf("unexpected events %v", got)
+	}
+}
+
+func TestNewProcessersFromConfigNeedQuerier(t *testing.T) {
+	cfg := &Config{
+		Files: []FileSinkConfig{{Name: "file", Mode: ModeFinalized, Path: t.TempDir() + "/events.ndjson"}},
+	}
+
+	if _, err := NewProcessersFromConfig(context.Background(), testLogger(t), cfg, 42, nil); err == nil {
+		t.Fatal("the finalized mode without querier should fail")
+	}
+
+	ps, err := NewProcessersFromConfig(context.Background(), testLogger(t), cfg, 42, &fixedQuerier{})
+	if err != nil {
+		t.Fatalf("new processers: %v", err)
+	}
+	if ps.poller == nil || len(ps.poller.processers) != 1 {
+		t.Fatal("the finalized processer should be polled")
+	}
+	ps.Close()
+}
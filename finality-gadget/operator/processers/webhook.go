@@ -0,0 +1,185 @@
+package processers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/alt-research/blitz/finality-gadget/core/logging"
+)
+
+const (
+	WebhookSignatureHeader = "X-Blitz-Signature"
+	WebhookTimestampHeader = "X-Blitz-Timestamp"
+	WebhookEventHeader     = "X-Blitz-Event"
+)
+
+// WebhookSink POST the events to a url, with the HMAC-SHA256 signature by the secret.
+//
+// The signature is `sha256=hex(hmac(secret, timestamp + "." + body))`, the receiver
+// should check the timestamp to avoid the replay.
+type WebhookSink struct {
+	logger     logging.Logger
+	cfg        WebhookConfig
+	maxRetries int
+	client     *http.Client
+
+	queue chan *BlockEvent
+	wg    sync.WaitGroup
+}
+
+var _ IEventSink = &WebhookSink{}
+
+func NewWebhookSink(ctx context.Context, logger logging.Logger, cfg WebhookConfig) (*WebhookSink, error) {
+	if cfg.Url == "" {
+		return nil, errors.Errorf("no url for webhook %s", cfg.Name)
+	}
+
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultWebhookTimeout
+	}
+
+	maxRetries := defaultWebhookMaxRetries
+	if cfg.MaxRetries != nil {
+		maxRetries = *cfg.MaxRetries
+	}
+
+	if cfg.RetryInterval == 0 {
+		cfg.RetryInterval = defaultWebhookRetryInterval
+	}
+
+	if cfg.QueueSize == 0 {
+		cfg.QueueSize = defaultWebhookQueueSize
+	}
+
+	s := &WebhookSink{
+		logger:     logger.With("webhook", cfg.Name),
+		cfg:        cfg,
+		maxRetries: maxRetries,
+		client:     &http.Client{Timeout: cfg.Timeout},
+		queue:      make(chan *BlockEvent, cfg.QueueSize),
+	}
+
+	s.wg.Add(1)
+	go s.loop(ctx)
+
+	return s, nil
+}
+
+// Send put the event into the queue, the event will be dropped if the queue is full,
+// so a slow webhook will not block the block handler.
+func (s *WebhookSink) Send(ctx context.Context, event *BlockEvent) error {
+	select {
+	case s.queue <- event:
+		return nil
+	default:
+		return errors.Errorf("webhook %s queue is full, drop event %d", s.cfg.Name, event.Number)
+	}
+}
+
+func (s *WebhookSink) Close() error {
+	close(s.queue)
+	s.wg.Wait()
+	return nil
+}
+
+func (s *WebhookSink) loop(ctx context.Context) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-s.queue:
+			if !ok {
+				return
+			}
+
+			if err := s.postWithRetry(ctx, event); err != nil {
+				s.logger.Error("post webhook failed", "number", event.Number, "err", err)
+			}
+		}
+	}
+}
+
+func (s *WebhookSink) postWithRetry(ctx context.Context, event *BlockEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "marshal event failed")
+	}
+
+	interval := s.cfg.RetryInterval
+	for i := 0; ; i++ {
+		err = s.post(ctx, event.Type, body)
+		if err == nil {
+			return nil
+		}
+
+		if i >= s.maxRetries {
+			return errors.Wrapf(err, "post failed after %d retries", i)
+		}
+
+		s.logger.Warn("post webhook failed, retry", "number", event.Number, "retry", i+1, "err", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+	}
+}
+
+func (s *WebhookSink) post(ctx context.Context, eventType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.Url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "new request failed")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, eventType)
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	if s.cfg.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(WebhookSignatureHeader, SignWebhookBody(s.cfg.Secret, timestamp, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "do request failed")
+	}
+	defer resp.Body.Close()
+
+	// drain the body to reuse the connection
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SignWebhookBody returns the signature header value for the webhook body.
+func SignWebhookBody(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,131 @@
+package processers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestWebhookSinkSigned(t *testing.T) {
+	const secret = "the-secret"
+
+	received := make(chan *BlockEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+			return
+		}
+
+		timestamp := r.Header.Get(WebhookTimestampHeader)
+		if got, want := r.Header.Get(WebhookSignatureHeader), SignWebhookBody(secret, timestamp, body); got != want {
+			t.Errorf("signature %s, want %s", got, want)
+		}
+
+		if got := r.Header.Get(WebhookEventHeader); got != EventTypeBlock {
+			t.Errorf("event header %s", got)
+		}
+
+		if got := r.Header.Get("X-Extra"); got != "extra" {
+			t.Errorf("extra header %s", got)
+		}
+
+		var event BlockEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("unmarshal body: %v", err)
+		}
+		received <- &event
+	}))
+	defer srv.Close()
+
+	sink, err := NewWebhookSink(context.Background(), testLogger(t), WebhookConfig{
+		Name:    "hook",
+		Url:     srv.URL,
+		Secret:  secret,
+		Headers: map[string]string{"X-Extra": "extra"},
+	})
+	if err != nil {
+		t.Fatalf("new webhook sink: %v", err)
+	}
+	defer sink.Close()
+
+	if err := sink.Send(context.Background(), NewBlockEvent(42, testBlock(7))); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	select {
+	case event := <-received:
+		if event.Number != 7 || event.ChainId != 42 {
+			t.Fatalf("unexpected event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not received")
+	}
+}
+
+func TestWebhookSinkRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries *int
+		failures   int32
+		want       int32
+	}{
+		{name: "retry until ok", maxRetries: nil, failures: 2, want: 3},
+		{name: "retries exhausted", maxRetries: intPtr(1), failures: 5, want: 2},
+		{name: "retries disabled", maxRetries: intPtr(0), failures: 5, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer srv.Close()
+
+			sink, err := NewWebhookSink(context.Background(), testLogger(t), WebhookConfig{
+				Name:          "hook",
+				Url:           srv.URL,
+				MaxRetries:    tt.maxRetries,
+				RetryInterval: time.Millisecond,
+			})
+			if err != nil {
+				t.Fatalf("new webhook sink: %v", err)
+			}
+
+			if err := sink.Send(context.Background(), NewBlockEvent(42, testBlock(1))); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+
+			// wait the queue consumed
+			sink.Close()
+
+			if got := calls.Load(); got != tt.want {
+				t.Fatalf("got %d calls, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookConfigValidate(t *testing.T) {
+	cfg := WebhookConfig{Name: "hook", Url: "http://127.0.0.1:8080", MaxRetries: intPtr(0)}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("zero retries should be valid: %v", err)
+	}
+
+	cfg.MaxRetries = intPtr(-1)
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("negative retries should be invalid")
+	}
+}
@@ -20,7 +20,10 @@ func main() {
 	app.Usage = "The finality-gadget rpc services"
 
 	app.Action = rpcService
-	app.Commands = []cli.Command{}
+	app.Commands = []cli.Command{
+		configCommand,
+		alertsCommand,
+	}
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Fatalln("Application failed.", "Message:", err)
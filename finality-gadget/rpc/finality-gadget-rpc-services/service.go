@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
 	"syscall"
@@ -11,29 +10,30 @@ import (
 	"github.com/carlmjohnson/versioninfo"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
+	"go.uber.org/zap"
 
 	rollupfpcfg "github.com/babylonlabs-io/finality-provider/bsn/rollup/config"
 
+	"github.com/alt-research/blitz/finality-gadget/alerting"
 	"github.com/alt-research/blitz/finality-gadget/core/logging"
 	"github.com/alt-research/blitz/finality-gadget/core/utils"
 	"github.com/alt-research/blitz/finality-gadget/metrics"
 	"github.com/alt-research/blitz/finality-gadget/operator/configs"
 	"github.com/alt-research/blitz/finality-gadget/rpc"
+	"github.com/alt-research/blitz/finality-gadget/tracing"
 )
 
 func rpcService(cliCtx *cli.Context) error {
-	var config configs.OperatorConfig
-	if err := utils.ReadConfig(cliCtx, defaultConfigPath, &config); err != nil {
-		log.Fatalf("read config failed by %v", err)
+	config, err := loadConfig(cliCtx)
+	if err != nil {
 		return err
 	}
-	config.WithEnv()
 
-	logger, err := logging.NewZapLogger(logging.NewLogLevel(config.Common.Production))
+	logger, err := newLogger(cliCtx, config)
 	if err != nil {
-		log.Fatalf("new logger failed by %v", err)
 		return err
 	}
+	defer logger.Sync()
 
 	logger.Infof("fp operator version %v", versioninfo.Short())
 
@@ -42,40 +42,57 @@ func rpcService(cliCtx *cli.Context) error {
 
 	logger.Info("NewFinalityProviderAppFromConfig")
 
-	zaplogger, err := logging.NewZapLoggerInner(logging.NewLogLevel(config.Common.Production))
+	zaplogger := logger.Inner()
+	metricsServer, err := metrics.Start(&config.MetricsConfig, zaplogger)
 	if err != nil {
-		log.Fatalf("new logger failed by %v", err)
-		return err
+		return fmt.Errorf("failed to start metrics server: %w", err)
 	}
-	promAddr, err := config.MetricsConfig.Address()
+	defer metricsServer.Stop(context.Background())
+
+	shutdownTracing, err := tracing.Setup(ctx, &config.Tracing, cliCtx.App.Name)
 	if err != nil {
-		return fmt.Errorf("failed to get prometheus address: %w", err)
+		return fmt.Errorf("failed to setup tracing: %w", err)
 	}
-	metricsServer := metrics.Start(promAddr, zaplogger)
-	defer metricsServer.Stop(context.Background())
+	defer shutdownTracing(context.Background())
 
-	rpc, err := newApp(ctx, &config, metrics.NewFpMetrics())
+	rpc, err := newApp(ctx, config, metrics.NewFpMetrics(), zaplogger)
 	if err != nil {
 		return errors.Wrap(err, "new provider failed")
 	}
 
+	// reload the hot reloadable config by SIGHUP
+	reloader := utils.NewConfigReloader(zaplogger, config, func() (*configs.OperatorConfig, error) {
+		return loadConfig(cliCtx)
+	})
+	reloader.OnReload(func(cfg *configs.OperatorConfig) {
+		if err := logging.ApplyLevels(&cfg.Logging, cfg.Common.Production); err != nil {
+			zaplogger.Error("failed to apply the log levels", zap.Error(err))
+		}
+	})
+	reloader.OnReload(rpc.ApplyConfig)
+	reloader.WatchSignal(ctx)
+
+	// the detector is started without rules too, the rules may be set by reload
+	detector := newDetector(cliCtx, config, zaplogger)
+	detector.AddFinalityRules(&config.Alerting.Rules, rpc.FinalizedStateProvider())
+	reloader.OnReload(func(cfg *configs.OperatorConfig) {
+		detector.Reload(ctx, &cfg.Alerting, func(d *alerting.Detector) {
+			d.AddFinalityRules(&cfg.Alerting.Rules, rpc.FinalizedStateProvider())
+		})
+	})
+	detector.Start(ctx)
+
 	rpc.StartServer(ctx, config.Common.RpcServerIpPortAddress)
 
 	return nil
 }
 
-func newApp(ctx context.Context, config *configs.OperatorConfig, blitzMetrics *metrics.FpMetrics) (*rpc.JsonRpcServer, error) {
+func newApp(ctx context.Context, config *configs.OperatorConfig, blitzMetrics *metrics.FpMetrics, zaplogger *zap.Logger) (*rpc.JsonRpcServer, error) {
 	fpConfig, err := rollupfpcfg.LoadConfig(config.FinalityProviderHomePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	zaplogger, err := logging.NewZapLoggerInner(logging.NewLogLevel(config.Common.Production))
-	if err != nil {
-		log.Fatalf("new logger failed by %v", err)
-		return nil, err
-	}
-
 	rpcApp, err := rpc.NewJsonRpcServer(ctx, zaplogger, config, fpConfig.Common)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create NewJsonRpcServer")
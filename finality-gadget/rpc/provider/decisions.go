@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alt-research/blitz/finality-gadget/metrics"
+	"github.com/babylonlabs-io/finality-gadget/types"
+)
+
+// RecentDecisionsCount is the number of the recent finality decisions kept for the status.
+const RecentDecisionsCount = 20
+
+// FinalityDecision is the votes of a block checked from babylon, and whether it is finalized by them.
+type FinalityDecision struct {
+	Height    uint64 `json:"height"`
+	Hash      string `json:"hash"`
+	Finalized bool   `json:"finalized"`
+	// the voting power at the btc height of the block
+	VotedPower uint64 `json:"voted_power"`
+	TotalPower uint64 `json:"total_power"`
+	// the fps with voting power, and voted of them
+	Fps      int      `json:"fps"`
+	VotedFps int      `json:"voted_fps"`
+	NotVoted []string `json:"not_voted"`
+	// the time checked, the finalized blocks are cached so not checked again
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// FinalityStatus is the status of the finality lookups.
+type FinalityStatus struct {
+	// Heads is nil if no lookup finished.
+	Heads           *metrics.FinalityHeads   `json:"heads"`
+	Upstreams       []metrics.UpstreamHealth `json:"upstreams"`
+	RecentDecisions []FinalityDecision       `json:"recent_decisions"`
+}
+
+func newFinalityDecision(block *types.Block, fpPower map[string]uint64, votedFpPks []string, finalized bool) FinalityDecision {
+	voted := make(map[string]struct{}, len(votedFpPks))
+	for _, pk := range votedFpPks {
+		voted[pk] = struct{}{}
+	}
+
+	d := FinalityDecision{
+		Height:    block.BlockHeight,
+		Hash:      "0x" + block.BlockHash,
+		Finalized: finalized,
+		NotVoted:  make([]string, 0),
+		CheckedAt: time.Now(),
+	}
+
+	for pk, power := range fpPower {
+		if power == 0 {
+			continue
+		}
+
+		d.Fps++
+		d.TotalPower += power
+		if _, ok := voted[pk]; ok {
+			d.VotedFps++
+			d.VotedPower += power
+		} else {
+			d.NotVoted = append(d.NotVoted, pk)
+		}
+	}
+	sort.Strings(d.NotVoted)
+
+	return d
+}
+
+// decisionLog keeps the decisions of the most recent heights, the decision of a height checked again is replaced.
+type decisionLog struct {
+	size      int
+	decisions []FinalityDecision
+	mu        sync.Mutex
+}
+
+var (
+	decisionsOnce     sync.Once
+	decisionsInstance *decisionLog
+)
+
+// processDecisions returns the decision log shared by the providers of the process,
+// and serves the finality status of the process in `/status/finality`.
+func processDecisions() *decisionLog {
+	decisionsOnce.Do(func() {
+		decisionsInstance = newDecisionLog(RecentDecisionsCount)
+		metrics.RegisterStatusProvider("finality", func() any {
+			return newFinalityStatus(metrics.NewFinalityMetrics(), decisionsInstance)
+		})
+	})
+
+	return decisionsInstance
+}
+
+func newFinalityStatus(fm *metrics.FinalityMetrics, decisions *decisionLog) *FinalityStatus {
+	return &FinalityStatus{
+		Heads:           fm.Heads(),
+		Upstreams:       fm.UpstreamHealth(),
+		RecentDecisions: decisions.recent(),
+	}
+}
+
+func newDecisionLog(size int) *decisionLog {
+	return &decisionLog{
+		size:      size,
+		decisions: make([]FinalityDecision, 0, size),
+	}
+}
+
+func (l *decisionLog) record(d FinalityDecision) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// sorted by the height desc
+	idx := sort.Search(len(l.decisions), func(i int) bool { return l.decisions[i].Height <= d.Height })
+	if idx < len(l.decisions) && l.decisions[idx].Height == d.Height {
+		l.decisions[idx] = d
+		return
+	}
+
+	if idx >= l.size {
+		// older than all the kept ones
+		return
+	}
+
+	l.decisions = append(l.decisions, FinalityDecision{})
+	copy(l.decisions[idx+1:], l.decisions[idx:])
+	l.decisions[idx] = d
+
+	if len(l.decisions) > l.size {
+		l.decisions = l.decisions[:l.size]
+	}
+}
+
+// recent returns the decisions sorted by the height desc.
+func (l *decisionLog) recent() []FinalityDecision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	res := make([]FinalityDecision, len(l.decisions))
+	copy(res, l.decisions)
+	return res
+}
@@ -6,12 +6,16 @@ import (
 	"math/big"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
 	"github.com/alt-research/blitz/finality-gadget/client/l2eth"
+	"github.com/alt-research/blitz/finality-gadget/metrics"
 	"github.com/alt-research/blitz/finality-gadget/operator/configs"
+	"github.com/alt-research/blitz/finality-gadget/tracing"
 	bbnclient "github.com/babylonlabs-io/babylon/v3/client/client"
 	bbncfg "github.com/babylonlabs-io/babylon/v3/client/config"
 	fgbbnclient "github.com/babylonlabs-io/finality-gadget/bbnclient"
@@ -20,6 +24,7 @@ import (
 	"github.com/babylonlabs-io/finality-gadget/finalitygadget"
 	"github.com/babylonlabs-io/finality-gadget/testutil/mocks"
 	"github.com/babylonlabs-io/finality-gadget/types"
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
 )
@@ -33,9 +38,17 @@ type FinalizedStateProvider struct {
 	btcClient finalitygadget.IBitcoinClient
 	bbnClient finalitygadget.IBabylonClient
 	cwClient  finalitygadget.ICosmWasmClient
+	// bbnRpcClient is the babylon comet rpc, to query the latest block
+	bbnRpcClient rpcclient.Client
 
 	lastFinalizedHeight uint64
-	mu                  sync.Mutex
+	// the last finalized height recorded in metrics, to record the time to finality for each new one
+	lastObservedFinalized uint64
+	mu                    sync.Mutex
+
+	metrics       *metrics.FinalityMetrics
+	participation *participationTracker
+	decisions     *decisionLog
 
 	allFpsCache                     []string
 	allFpsCacheLastTime             time.Time
@@ -46,6 +59,9 @@ type FinalizedStateProvider struct {
 	multiFpPowerCache               map[uint32]map[string]uint64
 	l2BlockCache                    map[uint64]*ethTypes.Block
 	cacheMu                         sync.RWMutex
+
+	// the max entries of each cache, can be changed by reload
+	maxCacheSize atomic.Int64
 }
 
 func NewFinalizedStateProvider(
@@ -95,19 +111,90 @@ func NewFinalizedStateProvider(
 		return nil, errors.Wrap(err, "failed to create l2 eth client")
 	}
 
-	return &FinalizedStateProvider{
-		logger:                          logger,
+	p := &FinalizedStateProvider{
+		logger:                          logger.With(zap.String("module", "provider")),
 		l2Client:                        l2Client,
 		btcClient:                       btcClient,
 		bbnClient:                       bbnClient,
 		cwClient:                        cwClient,
+		bbnRpcClient:                    babylonClient.RPCClient,
+		metrics:                         metrics.NewFinalityMetrics(),
+		participation:                   processParticipation(),
+		decisions:                       processDecisions(),
 		votedFpPksCache:                 make(map[string][]string, CacheMapCount),
 		finalizedCache:                  make(map[uint64]bool, CacheMapCount),
 		btcblockHeightCache:             make(map[string]uint32, CacheMapCount),
 		earliestActiveDelBtcHeightCache: make(map[string]uint32, CacheMapCount),
 		multiFpPowerCache:               make(map[uint32]map[string]uint64, CacheMapCount),
 		l2BlockCache:                    make(map[uint64]*ethTypes.Block, CacheMapCount),
-	}, nil
+	}
+	p.SetCacheSize(cfg.Common.RpcCacheSize)
+
+	return p, nil
+}
+
+// SetCacheSize sets the max entries of each cache, use the default if size is 0.
+func (p *FinalizedStateProvider) SetCacheSize(size int) {
+	if size <= 0 {
+		size = CacheMapCount
+	}
+
+	p.maxCacheSize.Store(int64(size))
+}
+
+func (p *FinalizedStateProvider) cacheSize() int {
+	return int(p.maxCacheSize.Load())
+}
+
+// ParticipationStatus returns the votes of each finality provider on the recent blocks.
+func (p *FinalizedStateProvider) ParticipationStatus() *ParticipationStatus {
+	return p.participation.Status()
+}
+
+// FinalityStatus returns the heads of the last lookup, the upstream health and the recent finality decisions,
+// which are shared by the providers of the process.
+func (p *FinalizedStateProvider) FinalityStatus() *FinalityStatus {
+	return newFinalityStatus(p.metrics, p.decisions)
+}
+
+// Heads looks up the finalized head, and returns the heads of this lookup.
+func (p *FinalizedStateProvider) Heads(ctx context.Context) (*metrics.FinalityHeads, error) {
+	l2Head, finalized, err := p.FinalizedHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blk, err := p.blockByNumber(ctx, finalized)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the finalized block %d", finalized)
+	}
+
+	heads := &metrics.FinalityHeads{
+		L2Head:             l2Head,
+		FinalizedHead:      finalized,
+		FinalizedBlockTime: time.Unix(int64(blk.Time()), 0),
+		UpdatedAt:          time.Now(),
+	}
+	if l2Head >= finalized {
+		heads.LagBlocks = l2Head - finalized
+	}
+
+	return heads, nil
+}
+
+// ParticipationRates returns the participation rate of each fp with voting power on the recent blocks,
+// the fps with less than minParticipationBlocks blocks recorded are skipped.
+func (p *FinalizedStateProvider) ParticipationRates() map[string]float64 {
+	status := p.participation.Status()
+
+	rates := make(map[string]float64, len(status.FinalityProviders))
+	for _, fp := range status.FinalityProviders {
+		if fp.Blocks >= minParticipationBlocks {
+			rates[fp.BtcPk] = fp.ParticipationRate
+		}
+	}
+
+	return rates
 }
 
 func (p *FinalizedStateProvider) GetLastFinalized() uint64 {
@@ -127,9 +214,102 @@ func (p *FinalizedStateProvider) SetLastFinalized(height uint64) {
 }
 
 func (p *FinalizedStateProvider) QueryFinalizedBlockInBabylon(ctx context.Context) (uint64, error) {
-	currentNumber, err := p.l2Client.BlockNumber(ctx)
+	_, finalized, err := p.FinalizedHead(ctx)
+	return finalized, err
+}
+
+// FinalizedHead returns the l2 head and the babylon finalized height.
+func (p *FinalizedStateProvider) FinalizedHead(ctx context.Context) (uint64, uint64, error) {
+	ctx, span := tracing.Start(ctx, "finality.query_finalized_block")
+
+	steps := 0
+	currentNumber, finalized, err := p.queryFinalizedBlockInBabylon(ctx, &steps)
 	if err != nil {
-		return 0, errors.Wrap(err, "failed to got blockNumber")
+		tracing.End(span, err)
+		return 0, 0, err
+	}
+
+	span.SetAttributes(
+		attribute.Int64("l2.head", int64(currentNumber)),
+		attribute.Int64("l2.finalized", int64(finalized)),
+		attribute.Int("bisection.steps", steps))
+	tracing.End(span, nil)
+
+	p.recordFinalized(ctx, currentNumber, finalized, steps)
+	go p.recordParticipation(context.WithoutCancel(ctx), currentNumber, finalized)
+
+	return currentNumber, finalized, nil
+}
+
+// BabylonLatestBlockTime returns the time of the latest babylon block.
+func (p *FinalizedStateProvider) BabylonLatestBlockTime(ctx context.Context) (time.Time, error) {
+	ctx, end := p.traceUpstream(ctx, metrics.UpstreamBabylon, "status")
+	status, err := p.bbnRpcClient.Status(ctx)
+	end(err)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to query the babylon status")
+	}
+
+	return status.SyncInfo.LatestBlockTime, nil
+}
+
+// recordFinalized records the metrics of a finality lookup.
+func (p *FinalizedStateProvider) recordFinalized(ctx context.Context, l2Head, finalized uint64, steps int) {
+	p.metrics.RecordBisectionSteps(steps)
+
+	blk, err := p.blockByNumber(ctx, finalized)
+	if err != nil {
+		p.log(ctx).Debugf("get the finalized block %d for metrics failed: %v", finalized, err)
+		return
+	}
+
+	blockTime := time.Unix(int64(blk.Time()), 0)
+	p.metrics.RecordFinalizedHead(l2Head, finalized, blockTime)
+
+	p.mu.Lock()
+	last := p.lastObservedFinalized
+	if last < finalized {
+		p.lastObservedFinalized = finalized
+	}
+	p.mu.Unlock()
+
+	// skip the first lookup, the block may be finalized long before started
+	if last == 0 || last >= finalized {
+		return
+	}
+
+	p.metrics.RecordTimeToFinality(blockTime)
+	if finalized-last > 1 {
+		// a lookup may finalize many blocks, observe each of them, without blocking the lookup
+		go p.recordTimeToFinality(context.WithoutCancel(ctx), last+1, finalized-1)
+	}
+}
+
+// recordTimeToFinality records the time to finality of the blocks in [from, to], which are finalized now,
+// only the recent `FastCheckNumberCount` blocks are observed if too many.
+func (p *FinalizedStateProvider) recordTimeToFinality(ctx context.Context, from, to uint64) {
+	if to-from+1 > FastCheckNumberCount {
+		from = to - FastCheckNumberCount + 1
+	}
+
+	for height := from; height <= to; height++ {
+		blk, err := p.blockByNumber(ctx, height)
+		if err != nil {
+			p.log(ctx).Debugf("get the finalized block %d for metrics failed: %v", height, err)
+			continue
+		}
+
+		p.metrics.RecordTimeToFinality(time.Unix(int64(blk.Time()), 0))
+	}
+}
+
+// queryFinalizedBlockInBabylon returns the l2 head and the finalized height, the steps is the number of bisection steps.
+func (p *FinalizedStateProvider) queryFinalizedBlockInBabylon(ctx context.Context, steps *int) (uint64, uint64, error) {
+	upstreamCtx, end := p.traceUpstream(ctx, metrics.UpstreamL2, "block_number")
+	currentNumber, err := p.l2Client.BlockNumber(upstreamCtx)
+	end(err)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to got blockNumber")
 	}
 
 	fromBlockHeight := p.GetLastFinalized()
@@ -138,7 +318,7 @@ func (p *FinalizedStateProvider) QueryFinalizedBlockInBabylon(ctx context.Contex
 	}
 
 	if currentNumber == fromBlockHeight {
-		return currentNumber, nil
+		return currentNumber, currentNumber, nil
 	}
 
 	// from block height is the start search point
@@ -146,11 +326,11 @@ func (p *FinalizedStateProvider) QueryFinalizedBlockInBabylon(ctx context.Contex
 
 	// check current newest block
 	if currentNumber > 1 {
-		isFinalized, err := p.queryFinalizedBlockInBabylonByNumber(ctx, currentNumber-1)
+		isFinalized, err := p.queryFinalizedBlockInBabylonByNumber(ctx, stepCheckHead, currentNumber-1)
 		if err == nil {
 			if isFinalized {
 				// The finalitzed block number can be the current number -1
-				return currentNumber - 1, nil
+				return currentNumber, currentNumber - 1, nil
 			}
 		}
 	}
@@ -159,12 +339,12 @@ func (p *FinalizedStateProvider) QueryFinalizedBlockInBabylon(ctx context.Contex
 	if currentNumber > FastCheckNumberCount {
 		checkNumber := currentNumber - FastCheckNumberCount
 		if checkNumber > fromBlockHeight {
-			p.logger.Sugar().Debugf(
+			p.log(ctx).Debugf(
 				"try use a check number near the current header: %d, %d, %d",
 				fromBlockHeight, checkNumber, currentNumber)
-			isFinalized, err := p.queryFinalizedBlockInBabylonByNumber(ctx, checkNumber)
+			isFinalized, err := p.queryFinalizedBlockInBabylonByNumber(ctx, stepCheckRecent, checkNumber)
 			if err != nil {
-				return 0, errors.Wrapf(err, "queryFinalizedBlockInBabylonByNumber failed: %v", checkNumber)
+				return 0, 0, errors.Wrapf(err, "queryFinalizedBlockInBabylonByNumber failed: %v", checkNumber)
 			}
 
 			if isFinalized {
@@ -176,29 +356,29 @@ func (p *FinalizedStateProvider) QueryFinalizedBlockInBabylon(ctx context.Contex
 	// mostly the currentNumber is the next block to last finality, so we can check it fast
 	nextFinality := fromBlockHeight + 1
 	if nextFinality == currentNumber {
-		p.logger.Sugar().Debugf(
+		p.log(ctx).Debugf(
 			"just check the current header by it is the next: %d, %d",
 			fromBlockHeight, currentNumber)
-		isFinalized, err := p.queryFinalizedBlockInBabylonByNumber(ctx, currentNumber)
+		isFinalized, err := p.queryFinalizedBlockInBabylonByNumber(ctx, stepCheckCurrent, currentNumber)
 		if err != nil {
-			return 0, errors.Wrapf(err, "queryFinalizedBlockInBabylonByNumber failed: %v", currentNumber)
+			return 0, 0, errors.Wrapf(err, "queryFinalizedBlockInBabylonByNumber failed: %v", currentNumber)
 		}
 
 		if isFinalized {
-			return currentNumber, nil
+			return currentNumber, currentNumber, nil
 		} else {
-			return fromBlockHeight, nil
+			return currentNumber, fromBlockHeight, nil
 		}
 	}
 
 	// mostly it is no new finality block after last finality, so we can check next
 	tryEndBlockHeight := fromBlockHeight + 1
 	if tryEndBlockHeight < currentNumber {
-		p.logger.Sugar().Debugf("try use next finaliy block to check: %d, %d", tryEndBlockHeight, currentNumber)
+		p.log(ctx).Debugf("try use next finaliy block to check: %d, %d", tryEndBlockHeight, currentNumber)
 
-		isFinalized, err := p.queryFinalizedBlockInBabylonByNumber(ctx, tryEndBlockHeight)
+		isFinalized, err := p.queryFinalizedBlockInBabylonByNumber(ctx, stepCheckNext, tryEndBlockHeight)
 		if err != nil {
-			return 0, errors.Wrapf(err, "queryFinalizedBlockInBabylonByNumber failed: %v", tryEndBlockHeight)
+			return 0, 0, errors.Wrapf(err, "queryFinalizedBlockInBabylonByNumber failed: %v", tryEndBlockHeight)
 		}
 
 		if isFinalized {
@@ -206,15 +386,18 @@ func (p *FinalizedStateProvider) QueryFinalizedBlockInBabylon(ctx context.Contex
 		}
 	}
 
-	res, err := p.queryFinalizedBlockInBabylonFromTo(ctx, fromBlockHeight, currentNumber)
-	if err == nil {
-		p.SetLastFinalized(res)
+	res, err := p.queryFinalizedBlockInBabylonFromTo(ctx, fromBlockHeight, currentNumber, steps)
+	if err != nil {
+		return 0, 0, err
 	}
 
-	return res, err
+	p.SetLastFinalized(res)
+
+	return currentNumber, res, nil
 }
 
 func (p *FinalizedStateProvider) blockByNumber(ctx context.Context, number uint64) (*ethTypes.Block, error) {
+	endCache := traceCache(ctx, "l2_block")
 	res, useCache := func() (*ethTypes.Block, bool) {
 		p.cacheMu.RLock()
 		defer p.cacheMu.RUnlock()
@@ -226,12 +409,15 @@ func (p *FinalizedStateProvider) blockByNumber(ctx context.Context, number uint6
 
 		return nil, false
 	}()
+	endCache(useCache)
 
 	if useCache {
 		return res, nil
 	}
 
-	blk, err := p.l2Client.BlockByNumber(ctx, big.NewInt(int64(number)))
+	upstreamCtx, end := p.traceUpstream(ctx, metrics.UpstreamL2, "block_by_number")
+	blk, err := p.l2Client.BlockByNumber(upstreamCtx, big.NewInt(int64(number)))
+	end(err)
 	if err != nil {
 		return nil, errors.Wrapf(err, "QueryBlock failed: %v", number)
 	}
@@ -241,8 +427,8 @@ func (p *FinalizedStateProvider) blockByNumber(ctx context.Context, number uint6
 			p.cacheMu.Lock()
 			defer p.cacheMu.Unlock()
 
-			if len(p.l2BlockCache) > CacheMapCount {
-				p.l2BlockCache = make(map[uint64]*ethTypes.Block, CacheMapCount)
+			if len(p.l2BlockCache) > p.cacheSize() {
+				p.l2BlockCache = make(map[uint64]*ethTypes.Block, p.cacheSize())
 			}
 
 			p.l2BlockCache[number] = blk
@@ -252,7 +438,15 @@ func (p *FinalizedStateProvider) blockByNumber(ctx context.Context, number uint6
 	return blk, err
 }
 
-func (p *FinalizedStateProvider) queryFinalizedBlockInBabylonByNumber(ctx context.Context, height uint64) (bool, error) {
+// queryFinalizedBlockInBabylonByNumber checks if the block is finalized, the step is the lookup step for tracing.
+func (p *FinalizedStateProvider) queryFinalizedBlockInBabylonByNumber(ctx context.Context, step string, height uint64) (res bool, err error) {
+	ctx, span := tracing.Start(ctx, "finality."+step, attribute.Int64("l2.height", int64(height)))
+	defer func() {
+		span.SetAttributes(attribute.Bool("finalized", res))
+		tracing.End(span, err)
+	}()
+
+	endCache := traceCache(ctx, "finalized")
 	useCache := func() bool {
 		p.cacheMu.RLock()
 		defer p.cacheMu.RUnlock()
@@ -261,9 +455,10 @@ func (p *FinalizedStateProvider) queryFinalizedBlockInBabylonByNumber(ctx contex
 
 		return ok
 	}()
+	endCache(useCache)
 
 	if useCache {
-		p.logger.Sugar().Debugf("queryFinalizedBlockInBabylonByNumber final by cache: %d", height)
+		p.log(ctx).Debugw("finalized by cache", "height", height)
 		return true, nil
 	}
 
@@ -272,7 +467,7 @@ func (p *FinalizedStateProvider) queryFinalizedBlockInBabylonByNumber(ctx contex
 		return false, errors.Wrapf(err, "QueryBlock failed: %v", height)
 	}
 
-	isFinalized, err := p.QueryIsBlockBabylonFinalizedFromBabylon(&types.Block{
+	isFinalized, err := p.QueryIsBlockBabylonFinalizedFromBabylon(ctx, &types.Block{
 		BlockHash:      blk.Hash().Hex(),
 		BlockTimestamp: blk.Time(),
 		BlockHeight:    blk.NumberU64(),
@@ -286,23 +481,23 @@ func (p *FinalizedStateProvider) queryFinalizedBlockInBabylonByNumber(ctx contex
 			p.cacheMu.Lock()
 			defer p.cacheMu.Unlock()
 
-			if len(p.finalizedCache) > CacheMapCount {
-				p.logger.Sugar().Debugf("clean the finality cache to %d", CacheMapCount)
-				p.finalizedCache = make(map[uint64]bool, CacheMapCount)
+			if len(p.finalizedCache) > p.cacheSize() {
+				p.log(ctx).Debugw("clean the finality cache", "size", p.cacheSize())
+				p.finalizedCache = make(map[uint64]bool, p.cacheSize())
 			}
 
-			p.logger.Sugar().Debugf("fill into the new finality cache %d", height)
+			p.log(ctx).Debugw("fill into the finality cache", "height", height)
 
 			p.finalizedCache[height] = true
 		}()
 	}
 
-	p.logger.Sugar().Debugf("queryFinalizedBlockInBabylonByNumber: %d, %v", height, isFinalized)
+	p.log(ctx).Debugw("query finalized block by number", "height", height, "finalized", isFinalized)
 
 	return isFinalized, nil
 }
 
-func (p *FinalizedStateProvider) queryFinalizedBlockInBabylonFromTo(ctx context.Context, from, to uint64) (uint64, error) {
+func (p *FinalizedStateProvider) queryFinalizedBlockInBabylonFromTo(ctx context.Context, from, to uint64, steps *int) (uint64, error) {
 	// from is a finalized block
 	if from > to {
 		tmp := from
@@ -319,20 +514,21 @@ func (p *FinalizedStateProvider) queryFinalizedBlockInBabylonFromTo(ctx context.
 	}
 
 	check := (from + to + 1) / 2
+	*steps++
 
-	p.logger.Sugar().Debugf("queryFinalizedBlockInBabylonFromTo from %v to %v check %v", from, to, check)
+	p.log(ctx).Debugw("bisection check", "from", from, "to", to, "check", check)
 
-	isFinalized, err := p.queryFinalizedBlockInBabylonByNumber(ctx, check)
+	isFinalized, err := p.queryFinalizedBlockInBabylonByNumber(ctx, stepBisection, check)
 	if err != nil {
 		return 0, errors.Wrapf(err, "queryFinalizedBlockInBabylonByNumber failed: %v", check)
 	}
 
-	p.logger.Sugar().Debugf("queryFinalizedBlockInBabylonByNumber got isFinalized: %v", isFinalized)
+	p.log(ctx).Debugw("bisection checked", "check", check, "finalized", isFinalized)
 	if isFinalized {
 		p.SetLastFinalized(check - 1)
-		return p.queryFinalizedBlockInBabylonFromTo(ctx, check, to)
+		return p.queryFinalizedBlockInBabylonFromTo(ctx, check, to, steps)
 	} else {
-		return p.queryFinalizedBlockInBabylonFromTo(ctx, from, check)
+		return p.queryFinalizedBlockInBabylonFromTo(ctx, from, check, steps)
 	}
 }
 
@@ -353,16 +549,22 @@ func (p *FinalizedStateProvider) queryFinalizedBlockInBabylonFromTo(ctx context.
  *   - calculate voted voting power
  *   - check if the voted voting power is more than 2/3 of the total voting power
  */
-func (p *FinalizedStateProvider) QueryIsBlockBabylonFinalizedFromBabylon(block *types.Block) (bool, error) {
+func (p *FinalizedStateProvider) QueryIsBlockBabylonFinalizedFromBabylon(ctx context.Context, block *types.Block) (res bool, err error) {
 	if block == nil {
 		return false, fmt.Errorf("block is nil")
 	}
 
+	ctx, span := tracing.Start(ctx, "finality.is_block_finalized", attribute.Int64("l2.height", int64(block.BlockHeight)))
+	defer func() {
+		span.SetAttributes(attribute.Bool("finalized", res))
+		tracing.End(span, err)
+	}()
+
 	// trim prefix 0x for the L2 block hash
 	block.BlockHash = strings.TrimPrefix(block.BlockHash, "0x")
 
 	// get all FPs voting power at this BTC height
-	allFpPower, err := p.queryAllPkPower(block)
+	allFpPower, err := p.queryAllPkPower(ctx, block)
 	if err != nil {
 		return false, errors.Wrap(err, "QueryMultiFpPower")
 	}
@@ -375,18 +577,26 @@ func (p *FinalizedStateProvider) QueryIsBlockBabylonFinalizedFromBabylon(block *
 
 	// no FP has voting power for the consumer chain
 	if totalPower == 0 {
-		p.logger.Sugar().Debugf("block not finalized by no totalPower for %v", block.BlockHeight)
+		p.log(ctx).Debugf("block not finalized by no totalPower for %v", block.BlockHeight)
+		p.decisions.record(newFinalityDecision(block, allFpPower, nil, true))
 		return true, nil
 	}
 
 	// get all FPs that voted this (L2 block height, L2 block hash) combination
-	votedFpPks, err := p.queryListOfVotedFinalityProviders(block)
+	votedFpPks, err := p.queryListOfVotedFinalityProviders(ctx, block)
 	if err != nil {
 		return false, errors.Wrap(err, "QueryListOfVotedFinalityProviders")
 	}
+
+	// decide records the decision with the voting breakdown for the status
+	decide := func(finalized bool) bool {
+		p.decisions.record(newFinalityDecision(block, allFpPower, votedFpPks, finalized))
+		return finalized
+	}
+
 	if votedFpPks == nil {
-		p.logger.Sugar().Debugw("votedFpPks nil", "height", block.BlockHeight)
-		return false, nil
+		p.log(ctx).Debugw("votedFpPks nil", "height", block.BlockHeight)
+		return decide(false), nil
 	}
 	// calculate voted voting power
 	var votedPower uint64 = 0
@@ -396,40 +606,51 @@ func (p *FinalizedStateProvider) QueryIsBlockBabylonFinalizedFromBabylon(block *
 		}
 	}
 
+	p.metrics.RecordVotedPower(block.BlockHeight, votedPower, totalPower)
+	span.SetAttributes(
+		attribute.Int64("voted_power", int64(votedPower)),
+		attribute.Int64("total_power", int64(totalPower)))
+
 	// quorom < 2/3
 	if votedPower*3 < totalPower*2 {
-		p.logger.Sugar().Debugf("voted power no enough %v to %v", votedPower, totalPower)
-		return false, nil
+		p.log(ctx).Debugf("voted power no enough %v to %v", votedPower, totalPower)
+		return decide(false), nil
 	}
-	return true, nil
+	return decide(true), nil
 }
 
-func (p *FinalizedStateProvider) queryAllFpBtcPubKeys() ([]string, error) {
+func (p *FinalizedStateProvider) queryAllFpBtcPubKeys(ctx context.Context) ([]string, error) {
+	endCache := traceCache(ctx, "all_fps")
 	res, useCache := func() ([]string, bool) {
 		p.cacheMu.RLock()
 		defer p.cacheMu.RUnlock()
 
-		p.logger.Sugar().Debugw("check cache for all fp", "cache len", len(p.allFpsCache), "current", time.Now(), "cache", p.allFpsCacheLastTime)
+		p.log(ctx).Debugw("check cache for all fp", "cache len", len(p.allFpsCache), "current", time.Now(), "cache", p.allFpsCacheLastTime)
 		if len(p.allFpsCache) > 0 && isTimeNotGreaterThan(p.allFpsCacheLastTime) {
 			return p.allFpsCache, true
 		}
 
 		return nil, false
 	}()
+	endCache(useCache)
 
 	if useCache {
-		p.logger.Sugar().Debugw("use cache for all fp btc keys", "res", res)
+		p.log(ctx).Debugw("use cache for all fp btc keys", "res", res)
 		return res, nil
 	}
 
 	// get the consumer chain id
+	_, end := p.traceUpstream(ctx, metrics.UpstreamCosmWasm, "consumer_id")
 	consumerId, err := p.cwClient.QueryConsumerId()
+	end(err)
 	if err != nil {
 		return nil, err
 	}
 
 	// get all the FPs pubkey for the consumer chain
+	_, end = p.traceUpstream(ctx, metrics.UpstreamBabylon, "all_fp_btc_pub_keys")
 	allFpPks, err := p.bbnClient.QueryAllFpBtcPubKeys(consumerId)
+	end(err)
 	if err != nil {
 		return nil, err
 	}
@@ -445,7 +666,8 @@ func (p *FinalizedStateProvider) queryAllFpBtcPubKeys() ([]string, error) {
 	return allFpPks, nil
 }
 
-func (p *FinalizedStateProvider) queryListOfVotedFinalityProviders(queryParams *types.Block) ([]string, error) {
+func (p *FinalizedStateProvider) queryListOfVotedFinalityProviders(ctx context.Context, queryParams *types.Block) ([]string, error) {
+	endCache := traceCache(ctx, "voted_fps")
 	res, useCache := func() ([]string, bool) {
 		p.cacheMu.RLock()
 		defer p.cacheMu.RUnlock()
@@ -457,20 +679,23 @@ func (p *FinalizedStateProvider) queryListOfVotedFinalityProviders(queryParams *
 
 		return nil, false
 	}()
+	endCache(useCache)
 
 	if useCache {
 		return res, nil
 	}
 
+	_, end := p.traceUpstream(ctx, metrics.UpstreamCosmWasm, "voted_finality_providers")
 	votedFpPks, err := p.cwClient.QueryListOfVotedFinalityProviders(queryParams)
+	end(err)
 
 	if err == nil {
 		func() {
 			p.cacheMu.Lock()
 			defer p.cacheMu.Unlock()
 
-			if len(p.votedFpPksCache) > CacheMapCount {
-				p.votedFpPksCache = make(map[string][]string, CacheMapCount)
+			if len(p.votedFpPksCache) > p.cacheSize() {
+				p.votedFpPksCache = make(map[string][]string, p.cacheSize())
 			}
 
 			p.votedFpPksCache[queryParams.BlockHash] = votedFpPks
@@ -478,13 +703,14 @@ func (p *FinalizedStateProvider) queryListOfVotedFinalityProviders(queryParams *
 	}
 
 	if len(votedFpPks) == 0 {
-		p.logger.Sugar().Debugw("not found voted finality provider", "block", queryParams.BlockHeight)
+		p.log(ctx).Debugw("not found voted finality provider", "block", queryParams.BlockHeight)
 	}
 
 	return votedFpPks, err
 }
 
-func (p *FinalizedStateProvider) getBlockHeightByTimestamp(block *types.Block) (uint32, error) {
+func (p *FinalizedStateProvider) getBlockHeightByTimestamp(ctx context.Context, block *types.Block) (uint32, error) {
+	endCache := traceCache(ctx, "btc_height")
 	res, useCache := func() (uint32, bool) {
 		p.cacheMu.RLock()
 		defer p.cacheMu.RUnlock()
@@ -496,13 +722,16 @@ func (p *FinalizedStateProvider) getBlockHeightByTimestamp(block *types.Block) (
 
 		return 0, false
 	}()
+	endCache(useCache)
 
 	if useCache {
 		return res, nil
 	}
 
 	// convert the L2 timestamp to BTC height
+	_, end := p.traceUpstream(ctx, metrics.UpstreamBitcoind, "block_height_by_timestamp")
 	btcblockHeight, err := p.btcClient.GetBlockHeightByTimestamp(block.BlockTimestamp)
+	end(err)
 	if err != nil {
 		return 0, errors.Wrap(err, "GetBlockHeightByTimestamp")
 	}
@@ -512,8 +741,8 @@ func (p *FinalizedStateProvider) getBlockHeightByTimestamp(block *types.Block) (
 			p.cacheMu.Lock()
 			defer p.cacheMu.Unlock()
 
-			if len(p.btcblockHeightCache) > CacheMapCount {
-				p.btcblockHeightCache = make(map[string]uint32, CacheMapCount)
+			if len(p.btcblockHeightCache) > p.cacheSize() {
+				p.btcblockHeightCache = make(map[string]uint32, p.cacheSize())
 			}
 
 			p.btcblockHeightCache[block.BlockHash] = btcblockHeight
@@ -524,9 +753,10 @@ func (p *FinalizedStateProvider) getBlockHeightByTimestamp(block *types.Block) (
 
 }
 
-func (p *FinalizedStateProvider) QueryEarliestActiveDelBtcHeight(fpPubkeyHexList []string) (uint32, error) {
+func (p *FinalizedStateProvider) QueryEarliestActiveDelBtcHeight(ctx context.Context, fpPubkeyHexList []string) (uint32, error) {
 	key := strings.Join(fpPubkeyHexList, ",")
 
+	endCache := traceCache(ctx, "earliest_del_btc_height")
 	res, useCache := func() (uint32, bool) {
 		p.cacheMu.RLock()
 		defer p.cacheMu.RUnlock()
@@ -538,13 +768,16 @@ func (p *FinalizedStateProvider) QueryEarliestActiveDelBtcHeight(fpPubkeyHexList
 
 		return 0, false
 	}()
+	endCache(useCache)
 
 	if useCache {
 		return res, nil
 	}
 
 	// check whether the btc staking is actived
+	_, end := p.traceUpstream(ctx, metrics.UpstreamBabylon, "earliest_active_del_btc_height")
 	earliestDelHeight, err := p.bbnClient.QueryEarliestActiveDelBtcHeight(fpPubkeyHexList)
+	end(err)
 	if err != nil {
 		return 0, errors.Wrap(err, "QueryEarliestActiveDelBtcHeight")
 	}
@@ -554,8 +787,8 @@ func (p *FinalizedStateProvider) QueryEarliestActiveDelBtcHeight(fpPubkeyHexList
 			p.cacheMu.Lock()
 			defer p.cacheMu.Unlock()
 
-			if len(p.earliestActiveDelBtcHeightCache) > CacheMapCount {
-				p.earliestActiveDelBtcHeightCache = make(map[string]uint32, CacheMapCount)
+			if len(p.earliestActiveDelBtcHeightCache) > p.cacheSize() {
+				p.earliestActiveDelBtcHeightCache = make(map[string]uint32, p.cacheSize())
 			}
 
 			p.earliestActiveDelBtcHeightCache[key] = earliestDelHeight
@@ -565,7 +798,8 @@ func (p *FinalizedStateProvider) QueryEarliestActiveDelBtcHeight(fpPubkeyHexList
 	return earliestDelHeight, err
 }
 
-func (p *FinalizedStateProvider) queryMultiFpPower(fpPubkeyHexList []string, btcHeight uint32) (map[string]uint64, error) {
+func (p *FinalizedStateProvider) queryMultiFpPower(ctx context.Context, fpPubkeyHexList []string, btcHeight uint32) (map[string]uint64, error) {
+	endCache := traceCache(ctx, "fp_power")
 	res, useCache := func() (map[string]uint64, bool) {
 		p.cacheMu.RLock()
 		defer p.cacheMu.RUnlock()
@@ -577,13 +811,16 @@ func (p *FinalizedStateProvider) queryMultiFpPower(fpPubkeyHexList []string, btc
 
 		return nil, false
 	}()
+	endCache(useCache)
 
 	if useCache {
 		return res, nil
 	}
 
 	// get all FPs voting power at this BTC height
+	_, end := p.traceUpstream(ctx, metrics.UpstreamBabylon, "multi_fp_power")
 	allFpPower, err := p.bbnClient.QueryMultiFpPower(fpPubkeyHexList, btcHeight)
+	end(err)
 	if err != nil {
 		return nil, errors.Wrap(err, "QueryMultiFpPower")
 	}
@@ -593,8 +830,8 @@ func (p *FinalizedStateProvider) queryMultiFpPower(fpPubkeyHexList []string, btc
 			p.cacheMu.Lock()
 			defer p.cacheMu.Unlock()
 
-			if len(p.multiFpPowerCache) > CacheMapCount {
-				p.multiFpPowerCache = make(map[uint32]map[string]uint64, CacheMapCount)
+			if len(p.multiFpPowerCache) > p.cacheSize() {
+				p.multiFpPowerCache = make(map[uint32]map[string]uint64, p.cacheSize())
 			}
 
 			p.multiFpPowerCache[btcHeight] = allFpPower
@@ -604,42 +841,42 @@ func (p *FinalizedStateProvider) queryMultiFpPower(fpPubkeyHexList []string, btc
 	return allFpPower, err
 }
 
-func (p *FinalizedStateProvider) queryAllPkPower(block *types.Block) (map[string]uint64, error) {
+func (p *FinalizedStateProvider) queryAllPkPower(ctx context.Context, block *types.Block) (map[string]uint64, error) {
 	// get all FPs pubkey for the consumer chain
-	allFpPks, err := p.queryAllFpBtcPubKeys()
+	allFpPks, err := p.queryAllFpBtcPubKeys(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "queryAllFpBtcPubKeys")
 	}
 
-	p.logger.Sugar().Infof("allFpPks %v", allFpPks)
+	p.log(ctx).Infof("allFpPks %v", allFpPks)
 
 	// convert the L2 timestamp to BTC height
-	btcblockHeight, err := p.getBlockHeightByTimestamp(block)
+	btcblockHeight, err := p.getBlockHeightByTimestamp(ctx, block)
 	if err != nil {
 		return nil, errors.Wrap(err, "GetBlockHeightByTimestamp")
 	}
 
-	p.logger.Sugar().Infof("btcblockHeight %v", btcblockHeight)
+	p.log(ctx).Infof("btcblockHeight %v", btcblockHeight)
 
 	// check whether the btc staking is actived
-	// earliestDelHeight, err := p.queryEarliestActiveDelBtcHeight(allFpPks)
+	// earliestDelHeight, err := p.QueryEarliestActiveDelBtcHeight(ctx, allFpPks)
 	// if err != nil {
 	//	return nil, errors.Wrap(err, "QueryEarliestActiveDelBtcHeight")
 	//}
 
-	// p.logger.Sugar().Info("earliestDelHeight ", earliestDelHeight)
+	// p.log(ctx).Info("earliestDelHeight ", earliestDelHeight)
 
 	// if btcblockHeight < earliestDelHeight {
 	//   return nil, errors.Wrapf(types.ErrBtcStakingNotActivated, "current %v, earliest %v", btcblockHeight, earliestDelHeight)
 	// }
 
 	// get all FPs voting power at this BTC height
-	allFpPower, err := p.queryMultiFpPower(allFpPks, btcblockHeight)
+	allFpPower, err := p.queryMultiFpPower(ctx, allFpPks, btcblockHeight)
 	if err != nil {
 		return nil, errors.Wrap(err, "QueryMultiFpPower")
 	}
 
-	p.logger.Sugar().Info("allFpPower ", allFpPower)
+	p.log(ctx).Info("allFpPower ", allFpPower)
 
 	return allFpPower, nil
 }
@@ -0,0 +1,272 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/babylonlabs-io/finality-gadget/types"
+
+	"github.com/alt-research/blitz/finality-gadget/metrics"
+)
+
+// ParticipationWindow is the number of the recent blocks recorded to calculate the participation rate.
+const ParticipationWindow = 100
+
+// minParticipationBlocks is the min blocks recorded of a fp to report its rate to the alerts,
+// the rate of a few blocks is not stable.
+const minParticipationBlocks = 10
+
+// ParticipationVoteDelay is the time for the finality providers to vote a block,
+// the block not finalized is recorded after it, as the votes may be not submitted yet.
+const ParticipationVoteDelay = time.Minute
+
+// FpParticipation is the votes of a finality provider on the recent blocks.
+type FpParticipation struct {
+	BtcPk string `json:"btc_pk"`
+	// the voting power at the btc height of the most recent block recorded
+	VotingPower     uint64 `json:"voting_power"`
+	VotedLastBlock  bool   `json:"voted_last_block"`
+	LastVotedHeight uint64 `json:"last_voted_height"`
+	// the blocks recorded which the fp has voting power, and voted of them
+	Blocks            int     `json:"blocks"`
+	Voted             int     `json:"voted"`
+	ParticipationRate float64 `json:"participation_rate"`
+}
+
+type ParticipationStatus struct {
+	// the most recent block recorded
+	Height            uint64            `json:"height"`
+	Window            int               `json:"window"`
+	FinalityProviders []FpParticipation `json:"finality_providers"`
+}
+
+// participationTracker records the votes of each finality provider on each recent block,
+// once the block is finalized or older than the vote delay.
+type participationTracker struct {
+	metrics *metrics.ParticipationMetrics
+	window  int
+
+	// the last height recorded in order, only one goroutine records the blocks at a time
+	recorded  atomic.Uint64
+	recording atomic.Bool
+
+	// the votes of the recent blocks by height, for the fps with voting power, true if voted
+	blocks  map[uint64]map[string]bool
+	heights []uint64
+	// the voting power at the most recent block
+	power     map[string]uint64
+	lastVoted map[string]uint64
+	// the fps in metrics, to delete the ones not in the recent blocks
+	exported map[string]struct{}
+	mu       sync.Mutex
+}
+
+var (
+	participationOnce     sync.Once
+	participationInstance *participationTracker
+)
+
+// processParticipation returns the participation tracker shared by the providers of the process,
+// as the metrics and the status of the finality providers are per process.
+func processParticipation() *participationTracker {
+	participationOnce.Do(func() {
+		participationInstance = newParticipationTracker(ParticipationWindow)
+		metrics.RegisterStatusProvider("finality-providers", func() any {
+			return participationInstance.Status()
+		})
+	})
+
+	return participationInstance
+}
+
+func newParticipationTracker(window int) *participationTracker {
+	return &participationTracker{
+		metrics:   metrics.NewParticipationMetrics(),
+		window:    window,
+		blocks:    make(map[uint64]map[string]bool, window),
+		power:     make(map[string]uint64),
+		lastVoted: make(map[string]uint64),
+		exported:  make(map[string]struct{}),
+	}
+}
+
+// record records the votes of the block, the fpPower is the voting power of all the fps at the btc height of the block.
+func (t *participationTracker) record(height uint64, fpPower map[string]uint64, votedFpPks []string) {
+	voted := make(map[string]struct{}, len(votedFpPks))
+	for _, pk := range votedFpPks {
+		voted[pk] = struct{}{}
+	}
+
+	votes := make(map[string]bool, len(fpPower))
+	for pk, power := range fpPower {
+		if power == 0 {
+			continue
+		}
+
+		_, ok := voted[pk]
+		votes[pk] = ok
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for pk, ok := range votes {
+		if ok && t.lastVoted[pk] < height {
+			t.lastVoted[pk] = height
+		}
+	}
+
+	if _, ok := t.blocks[height]; !ok {
+		if len(t.heights) >= t.window && height < t.heights[0] {
+			// too old to be in the window
+			return
+		}
+
+		idx := sort.Search(len(t.heights), func(i int) bool { return t.heights[i] > height })
+		t.heights = append(t.heights, 0)
+		copy(t.heights[idx+1:], t.heights[idx:])
+		t.heights[idx] = height
+
+		if len(t.heights) > t.window {
+			delete(t.blocks, t.heights[0])
+			t.heights = t.heights[1:]
+		}
+	}
+	t.blocks[height] = votes
+
+	if height == t.heights[len(t.heights)-1] {
+		t.power = make(map[string]uint64, len(votes))
+		for pk := range votes {
+			t.power[pk] = fpPower[pk]
+		}
+	}
+
+	t.updateMetrics()
+}
+
+func (t *participationTracker) updateMetrics() {
+	status := t.statusLocked()
+
+	current := make(map[string]struct{}, len(status.FinalityProviders))
+	for _, fp := range status.FinalityProviders {
+		current[fp.BtcPk] = struct{}{}
+		t.metrics.RecordFpParticipation(fp.BtcPk, fp.VotingPower, fp.VotedLastBlock, fp.ParticipationRate, fp.LastVotedHeight)
+	}
+
+	for pk := range t.exported {
+		if _, ok := current[pk]; !ok {
+			t.metrics.DeleteFp(pk)
+			delete(t.lastVoted, pk)
+		}
+	}
+	t.exported = current
+}
+
+// Status returns the participation of each fp in the recent blocks, sorted by the pk.
+func (t *participationTracker) Status() *ParticipationStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.statusLocked()
+}
+
+func (t *participationTracker) statusLocked() *ParticipationStatus {
+	status := &ParticipationStatus{
+		Window:            t.window,
+		FinalityProviders: []FpParticipation{},
+	}
+
+	if len(t.heights) == 0 {
+		return status
+	}
+
+	status.Height = t.heights[len(t.heights)-1]
+	latest := t.blocks[status.Height]
+
+	fps := make(map[string]*FpParticipation)
+	for _, height := range t.heights {
+		for pk, voted := range t.blocks[height] {
+			fp, ok := fps[pk]
+			if !ok {
+				fp = &FpParticipation{
+					BtcPk:           pk,
+					VotingPower:     t.power[pk],
+					VotedLastBlock:  latest[pk],
+					LastVotedHeight: t.lastVoted[pk],
+				}
+				fps[pk] = fp
+			}
+
+			fp.Blocks++
+			if voted {
+				fp.Voted++
+			}
+		}
+	}
+
+	for _, fp := range fps {
+		fp.ParticipationRate = float64(fp.Voted) / float64(fp.Blocks)
+		status.FinalityProviders = append(status.FinalityProviders, *fp)
+	}
+
+	sort.Slice(status.FinalityProviders, func(i, j int) bool {
+		return status.FinalityProviders[i].BtcPk < status.FinalityProviders[j].BtcPk
+	})
+
+	return status
+}
+
+// recordParticipation records the votes of the blocks after the last recorded to the l2 head, each block is recorded
+// once it is finalized or older than ParticipationVoteDelay, only the recent blocks in the window are recorded.
+func (p *FinalizedStateProvider) recordParticipation(ctx context.Context, l2Head, finalized uint64) {
+	t := p.participation
+	if !t.recording.CompareAndSwap(false, true) {
+		return
+	}
+	defer t.recording.Store(false)
+
+	from := t.recorded.Load() + 1
+	if window := uint64(t.window); l2Head >= window && from+window <= l2Head {
+		from = l2Head - window + 1
+	}
+
+	for height := from; height <= l2Head; height++ {
+		blk, err := p.blockByNumber(ctx, height)
+		if err != nil {
+			p.log(ctx).Debugf("get the block %d for participation failed: %v", height, err)
+			return
+		}
+
+		if height > finalized && time.Since(time.Unix(int64(blk.Time()), 0)) < ParticipationVoteDelay {
+			return
+		}
+
+		block := &types.Block{
+			BlockHash:      strings.TrimPrefix(blk.Hash().Hex(), "0x"),
+			BlockTimestamp: blk.Time(),
+			BlockHeight:    height,
+		}
+
+		allFpPower, err := p.queryAllPkPower(ctx, block)
+		if err != nil {
+			p.log(ctx).Debugf("query the fp power of block %d for participation failed: %v", height, err)
+			return
+		}
+
+		// not by the cache, which may be queried before all the votes submitted
+		_, end := p.traceUpstream(ctx, metrics.UpstreamCosmWasm, "voted_finality_providers")
+		votedFpPks, err := p.cwClient.QueryListOfVotedFinalityProviders(block)
+		end(err)
+		if err != nil {
+			p.log(ctx).Debugf("query the voted fps of block %d for participation failed: %v", height, err)
+			return
+		}
+
+		t.record(height, allFpPower, votedFpPks)
+		t.recorded.Store(height)
+	}
+}
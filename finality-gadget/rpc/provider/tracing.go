@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+
+	"github.com/alt-research/blitz/finality-gadget/tracing"
+)
+
+// The steps of the finality lookup, used as the span names.
+const (
+	stepCheckHead    = "check_head"
+	stepCheckRecent  = "check_recent"
+	stepCheckCurrent = "check_current"
+	stepCheckNext    = "check_next"
+	stepBisection    = "bisection"
+)
+
+// traceUpstream starts the span of an upstream call, the returned end records the span and the upstream metrics.
+func (p *FinalizedStateProvider) traceUpstream(ctx context.Context, upstream, method string) (context.Context, func(err error)) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, upstream+"."+method,
+		attribute.String("upstream", upstream),
+		attribute.String("method", method))
+
+	return ctx, func(err error) {
+		p.metrics.RecordUpstreamRequest(upstream, method, start, err)
+		tracing.End(span, err)
+	}
+}
+
+// traceCache starts the span of a cache lookup, the returned end records if it is a hit.
+func traceCache(ctx context.Context, cache string) func(hit bool) {
+	_, span := tracing.Start(ctx, "cache."+cache, attribute.String("cache", cache))
+
+	return func(hit bool) {
+		span.SetAttributes(attribute.Bool("cache.hit", hit))
+		span.End()
+	}
+}
+
+// log returns the logger with the trace and span ids in the ctx.
+func (p *FinalizedStateProvider) log(ctx context.Context) *zap.SugaredLogger {
+	return tracing.Logger(ctx, p.logger).Sugar()
+}
@@ -9,14 +9,17 @@ import (
 	"sync/atomic"
 
 	"cosmossdk.io/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
 	"github.com/ethereum/go-ethereum/node"
 	gethrpc "github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/alt-research/blitz/finality-gadget/client/l2eth"
+	"github.com/alt-research/blitz/finality-gadget/metrics"
 	"github.com/alt-research/blitz/finality-gadget/operator/configs"
 	"github.com/alt-research/blitz/finality-gadget/rpc/provider"
+	"github.com/alt-research/blitz/finality-gadget/tracing"
 	fpcfg "github.com/babylonlabs-io/finality-provider/finality-provider/config"
 )
 
@@ -26,6 +29,11 @@ type JsonRpcServer struct {
 	vhosts  []string
 	cors    []string
 	wg      *sync.WaitGroup
+
+	// the http handler with the cors and vhosts, rebuilt when the config is reloaded
+	rpcServer   *gethrpc.Server
+	httpHandler atomic.Pointer[http.Handler]
+	hostsMu     sync.Mutex
 }
 
 func NewJsonRpcServer(
@@ -57,6 +65,34 @@ func NewJsonRpcServer(
 	}, nil
 }
 
+// FinalizedStateProvider returns the provider of the finality state, shared with the stall detector.
+func (s *JsonRpcServer) FinalizedStateProvider() *provider.FinalizedStateProvider {
+	return s.handler.finalizedStateProvider
+}
+
+// ApplyConfig applies the hot reloadable config, the cors and vhosts take effect for the new requests.
+func (s *JsonRpcServer) ApplyConfig(cfg *configs.OperatorConfig) {
+	s.handler.finalizedStateProvider.SetCacheSize(cfg.Common.RpcCacheSize)
+
+	s.hostsMu.Lock()
+	defer s.hostsMu.Unlock()
+
+	s.cors = cfg.Common.RpcCors
+	s.vhosts = cfg.Common.RpcVhosts
+	if s.rpcServer != nil {
+		s.buildHttpHandler()
+	}
+}
+
+func (s *JsonRpcServer) buildHttpHandler() {
+	handler := node.NewHTTPHandlerStack(s.rpcServer, s.cors, s.vhosts, nil)
+	s.httpHandler.Store(&handler)
+}
+
+func (s *JsonRpcServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	(*s.httpHandler.Load()).ServeHTTP(w, r)
+}
+
 func (s *JsonRpcServer) GetAPI() gethrpc.API {
 	return gethrpc.API{
 		Namespace: "eth",
@@ -72,9 +108,10 @@ type loggerHandler struct {
 
 func (h *loggerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	rpcId := h.id.Add(1)
-	h.logger.Sugar().Debugf("handle http request %d", rpcId)
+	logger := tracing.Logger(r.Context(), h.logger)
+	logger.Sugar().Debugf("handle http request %d", rpcId)
 	h.next.ServeHTTP(w, r)
-	h.logger.Sugar().Debugf("handle http returned %d", rpcId)
+	logger.Sugar().Debugf("handle http returned %d", rpcId)
 }
 
 func (s *JsonRpcServer) StartServer(ctx context.Context, serverIpPortAddr string) {
@@ -93,14 +130,20 @@ func (s *JsonRpcServer) StartServer(ctx context.Context, serverIpPortAddr string
 	if err != nil {
 		s.logger.Sugar().Fatalf("Could not register API: %w", err)
 	}
-	handler := node.NewHTTPHandlerStack(srv, s.cors, s.vhosts, nil)
+	s.hostsMu.Lock()
+	s.rpcServer = srv
+	s.buildHttpHandler()
+	s.hostsMu.Unlock()
 
 	handlerWithLogger := &loggerHandler{
 		logger: s.logger,
-		next:   handler,
+		next:   http.HandlerFunc(s.serveHTTP),
 	}
 
-	httpServer, addr, err := node.StartHTTPEndpoint(serverIpPortAddr, gethrpc.DefaultHTTPTimeouts, handlerWithLogger)
+	// the span of the http request is the parent of the json rpc methods
+	handlerWithTracing := tracing.Handler("jsonrpc", handlerWithLogger)
+
+	httpServer, addr, err := node.StartHTTPEndpoint(serverIpPortAddr, gethrpc.DefaultHTTPTimeouts, handlerWithTracing)
 	if err != nil {
 		s.logger.Sugar().Fatalf("Could not start RPC api: %v", err)
 	}
@@ -156,11 +199,17 @@ func (h *JsonRpcHandler) init(ctx context.Context) error {
 func (h *JsonRpcHandler) GetBlockByNumber(
 	ctx context.Context,
 	number gethrpc.BlockNumber, fullTx bool,
-) (map[string]json.RawMessage, error) {
+) (res map[string]json.RawMessage, err error) {
+	ctx, span := tracing.Start(ctx, "eth_getBlockByNumber",
+		attribute.String("rpc.method", "eth_getBlockByNumber"),
+		attribute.String("block.number", number.String()),
+		attribute.Bool("full_tx", fullTx))
+	defer func() { tracing.End(span, err) }()
+
 	// for no finalized block number request, we just return the block from chain api
 	if number != gethrpc.FinalizedBlockNumber {
 		var raw map[string]json.RawMessage
-		err := h.ethClient.Client.Client().CallContext(ctx, &raw, "eth_getBlockByNumber", number.String(), fullTx)
+		err := h.callL2(ctx, &raw, "eth_getBlockByNumber", number.String(), fullTx)
 		if err != nil {
 			return nil, err
 		}
@@ -171,22 +220,31 @@ func (h *JsonRpcHandler) GetBlockByNumber(
 		return raw, nil
 	}
 
-	h.logger.Sugar().Debugf("request GetBlockByNumber by finalized block")
+	tracing.Logger(ctx, h.logger).Sugar().Debugf("request GetBlockByNumber by finalized block")
 	finalized, err := h.finalizedStateProvider.QueryFinalizedBlockInBabylon(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to QueryFinalizedBlockInBabylon")
 	}
 
 	var raw map[string]json.RawMessage
-	err = h.ethClient.Client.Client().CallContext(ctx, &raw, "eth_getBlockByNumber",
+	err = h.callL2(ctx, &raw, "eth_getBlockByNumber",
 		gethrpc.BlockNumber(finalized).String(),
 		fullTx)
 	if err != nil {
 		return nil, err
 	}
 
-	h.logger.Sugar().Debugf("get block by number %v", number)
+	tracing.Logger(ctx, h.logger).Sugar().Debugf("get block by number %v", number)
 	// h.logger.Sugar().Debugf("get block resp %v", raw)
 
 	return raw, nil
 }
+
+// callL2 calls the l2 node in a span.
+func (h *JsonRpcHandler) callL2(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	ctx, span := tracing.Start(ctx, "l2."+method, attribute.String("upstream", metrics.UpstreamL2), attribute.String("method", method))
+	err := h.ethClient.Client.Client().CallContext(ctx, result, method, args...)
+	tracing.End(span, err)
+
+	return err
+}
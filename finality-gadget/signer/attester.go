@@ -0,0 +1,136 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/node"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/alt-research/blitz/finality-gadget/attestation"
+	"github.com/alt-research/blitz/finality-gadget/client/l2eth"
+	operatorConfigs "github.com/alt-research/blitz/finality-gadget/operator/configs"
+	"github.com/alt-research/blitz/finality-gadget/rpc/provider"
+	"github.com/alt-research/blitz/finality-gadget/signer/configs"
+)
+
+// attestationRpcNamespace is the namespace of `blitz_getFinalityAttestation`.
+const attestationRpcNamespace = "blitz"
+
+// Attester signs the EIP-712 attestations for the l2 blocks finalized by babylon.
+type Attester struct {
+	logger    *zap.Logger
+	key       *ecdsa.PrivateKey
+	chainId   uint64
+	l2Client  *l2eth.L2EthClient
+	finalized *provider.FinalizedStateProvider
+}
+
+func newAttester(
+	ctx context.Context,
+	cfg *configs.SignerConfig,
+	l2Client *l2eth.L2EthClient,
+	logger *zap.Logger,
+) (*Attester, error) {
+	key, err := crypto.LoadECDSA(cfg.Attestation.KeyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the attestation key %s", cfg.Attestation.KeyFile)
+	}
+
+	finalized, err := provider.NewFinalizedStateProvider(ctx, &operatorConfigs.OperatorConfig{
+		Layer2:  cfg.Layer2,
+		Babylon: cfg.Babylon,
+	}, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create finalizedStateProvider")
+	}
+
+	logger.Info("the finality attestation signer",
+		zap.String("address", crypto.PubkeyToAddress(key.PublicKey).Hex()))
+
+	return &Attester{
+		logger:    logger.With(zap.String("module", "attester")),
+		key:       key,
+		chainId:   cfg.Layer2.ChainId,
+		l2Client:  l2Client,
+		finalized: finalized,
+	}, nil
+}
+
+// GetFinalityAttestation returns the signed attestation for the l2 block finalized by babylon,
+// the number can be a block number or `finalized` for the latest finalized block.
+func (a *Attester) GetFinalityAttestation(
+	ctx context.Context, number gethrpc.BlockNumber) (*attestation.SignedAttestation, error) {
+	if number < 0 && number != gethrpc.FinalizedBlockNumber {
+		return nil, fmt.Errorf("only support the block number or `finalized`, got %s", number)
+	}
+
+	finalized, err := a.finalized.QueryFinalizedBlockInBabylon(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to QueryFinalizedBlockInBabylon")
+	}
+
+	height := finalized
+	if number != gethrpc.FinalizedBlockNumber {
+		height = uint64(number.Int64())
+	}
+
+	if height > finalized {
+		return nil, fmt.Errorf("the block %d is not finalized by babylon, the latest finalized is %d", height, finalized)
+	}
+
+	header, err := a.l2Client.HeaderByNumber(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the l2 block %d", height)
+	}
+
+	att := &attestation.Attestation{
+		ChainId:   a.chainId,
+		Height:    height,
+		BlockHash: header.Hash(),
+	}
+
+	res, err := att.Sign(a.key)
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.Debug("signed finality attestation",
+		zap.Uint64("height", height), zap.String("hash", att.BlockHash.Hex()))
+
+	return res, nil
+}
+
+// serveRpc serves `blitz_getFinalityAttestation` until the ctx is done.
+func (a *Attester) serveRpc(ctx context.Context, addr string) error {
+	srv := gethrpc.NewServer()
+	srv.SetBatchLimits(node.DefaultConfig.BatchRequestLimit, node.DefaultConfig.BatchResponseMaxSize)
+
+	err := node.RegisterApis([]gethrpc.API{{
+		Namespace: attestationRpcNamespace,
+		Service:   a,
+	}}, []string{attestationRpcNamespace}, srv)
+	if err != nil {
+		return errors.Wrap(err, "could not register the attestation api")
+	}
+
+	handler := node.NewHTTPHandlerStack(srv, nil, []string{"*"}, nil)
+	httpServer, listenAddr, err := node.StartHTTPEndpoint(addr, gethrpc.DefaultHTTPTimeouts, handler)
+	if err != nil {
+		return errors.Wrap(err, "could not start the attestation rpc")
+	}
+
+	a.logger.Info("Starting finality attestation rpc", zap.String("address", listenAddr.String()))
+
+	<-ctx.Done()
+	if err := httpServer.Shutdown(context.Background()); err != nil {
+		a.logger.Error("stop the attestation rpc failed", zap.Error(err))
+	}
+
+	return nil
+}
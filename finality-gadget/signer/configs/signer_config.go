@@ -1,17 +1,183 @@
 package configs
 
 import (
+	"time"
+
+	"github.com/alt-research/blitz/finality-gadget/client/eotsmanager"
 	"github.com/alt-research/blitz/finality-gadget/client/l2eth"
 	commonConfig "github.com/alt-research/blitz/finality-gadget/core/configs"
+	"github.com/alt-research/blitz/finality-gadget/core/logging"
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
+)
+
+// EnvPrefix is the prefix of the env for the signer config fields, e.g. `FINALITY_GADGET_SIGNER_HOME_PATH`.
+const EnvPrefix = utils.EnvPrefix + "_SIGNER"
+
+const (
+	defaultGrpcListenAddress           = "127.0.0.1:12583"
+	defaultHeadPollInterval            = time.Second
+	defaultAttestationRpcListenAddress = "127.0.0.1:12584"
 )
 
 type SignerConfig struct {
-	Common commonConfig.CommonConfig `yaml:"common,omitempty"`
-	Layer2 l2eth.Config              `yaml:"layer2,omitempty"`
+	Common            commonConfig.CommonConfig  `yaml:"common,omitempty"`
+	Logging           logging.Config             `yaml:"logging,omitempty"`
+	Layer2            l2eth.Config               `yaml:"layer2,omitempty"`
+	Babylon           commonConfig.BabylonConfig `yaml:"babylon,omitempty"`
+	EOTSManagerConfig eotsmanager.Config         `yaml:"eotsManager,omitempty"`
+	Grpc              GrpcConfig                 `yaml:"grpc,omitempty"`
+	Attestation       AttestationConfig          `yaml:"attestation,omitempty"`
+
+	// The home path of the signer, the eots slashing protection db will be in `data` of it.
+	HomePath string `yaml:"home_path,omitempty"`
+	// The interval to poll the l2 head, default is 1s.
+	HeadPollInterval time.Duration `yaml:"head_poll_interval,omitempty"`
+}
+
+// GrpcConfig is the config of the signing gRPC service for the operators,
+// it is compatible with the eotsd gRPC service.
+type GrpcConfig struct {
+	// The listen address, default is `127.0.0.1:12583`.
+	ListenAddress string `yaml:"listen_address,omitempty"`
+	// The server cert and key to enable TLS.
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+	// The CA to verify the client cert, enable mutual-TLS if set.
+	ClientCAFile string `yaml:"client_ca_file,omitempty"`
+	// The bearer token the operators should send by the `authorization` metadata, needs TLS.
+	Token string `yaml:"token,omitempty" secret:"true"`
+	// The file of the bearer token, will be read for each request so it can be rotated.
+	TokenFile string `yaml:"token_file,omitempty"`
+}
+
+// HasToken returns true if the clients are authenticated by the bearer token.
+func (c *GrpcConfig) HasToken() bool {
+	return c.Token != "" || c.TokenFile != ""
+}
+
+// AttestationConfig is the config of the EIP-712 finality attestations.
+type AttestationConfig struct {
+	// The hex secp256k1 private key file to sign the attestations, the attestation is disabled if not set.
+	KeyFile string `yaml:"key_file,omitempty"`
+	// The json rpc listen address for `blitz_getFinalityAttestation`, default is `127.0.0.1:12584`.
+	RpcListenAddress string `yaml:"rpc_listen_address,omitempty"`
+}
+
+// IsEnabled returns true if the signer should serve the finality attestations.
+func (c *AttestationConfig) IsEnabled() bool {
+	return c.KeyFile != ""
 }
 
 // use the env config first for some keys
 func (c *SignerConfig) WithEnv() {
 	c.Common.WithEnv()
 	c.Layer2.WithEnv()
+	c.Babylon.WithEnv()
+	c.EOTSManagerConfig.WithEnv()
+
+	c.HomePath = utils.LookupEnvStr("FINALITY_GADGET_SIGNER_HOME_PATH", c.HomePath)
+	c.HeadPollInterval = utils.LookupEnvDuration("FINALITY_GADGET_SIGNER_HEAD_POLL_INTERVAL", c.HeadPollInterval)
+	c.Grpc.ListenAddress = utils.LookupEnvStr("FINALITY_GADGET_SIGNER_GRPC_LISTEN_ADDRESS", c.Grpc.ListenAddress)
+	c.Grpc.CertFile = utils.LookupEnvStr("FINALITY_GADGET_SIGNER_GRPC_CERT_FILE", c.Grpc.CertFile)
+	c.Grpc.KeyFile = utils.LookupEnvStr("FINALITY_GADGET_SIGNER_GRPC_KEY_FILE", c.Grpc.KeyFile)
+	c.Grpc.ClientCAFile = utils.LookupEnvStr("FINALITY_GADGET_SIGNER_GRPC_CLIENT_CA_FILE", c.Grpc.ClientCAFile)
+	c.Grpc.Token = utils.LookupEnvStr("FINALITY_GADGET_SIGNER_GRPC_TOKEN", c.Grpc.Token)
+	c.Grpc.TokenFile = utils.LookupEnvStr("FINALITY_GADGET_SIGNER_GRPC_TOKEN_FILE", c.Grpc.TokenFile)
+	c.Attestation.KeyFile = utils.LookupEnvStr("FINALITY_GADGET_SIGNER_ATTESTATION_KEY_FILE", c.Attestation.KeyFile)
+	c.Attestation.RpcListenAddress = utils.LookupEnvStr("FINALITY_GADGET_SIGNER_ATTESTATION_RPC_LISTEN_ADDRESS", c.Attestation.RpcListenAddress)
+}
+
+// WithDefault fills the default values.
+func (c *SignerConfig) WithDefault() {
+	if c.Grpc.ListenAddress == "" {
+		c.Grpc.ListenAddress = defaultGrpcListenAddress
+	}
+
+	if c.Attestation.RpcListenAddress == "" {
+		c.Attestation.RpcListenAddress = defaultAttestationRpcListenAddress
+	}
+
+	if c.HeadPollInterval == 0 {
+		c.HeadPollInterval = defaultHeadPollInterval
+	}
+}
+
+// Validate checks the config, it should be called after WithDefault.
+func (c *SignerConfig) Validate() error {
+	var errs utils.ConfigErrors
+
+	errs.Merge("common", c.Common.Validate())
+	errs.Merge("logging", c.Logging.Validate())
+	errs.Merge("layer2", c.Layer2.Validate())
+	errs.Merge("babylon", c.Babylon.Validate())
+	errs.Merge("eotsManager", c.EOTSManagerConfig.Validate())
+	errs.Merge("grpc", c.Grpc.Validate())
+	errs.Merge("attestation", c.Attestation.Validate())
+
+	if c.HomePath == "" {
+		errs.Addf("home_path", "required")
+	}
+
+	if c.HeadPollInterval < 0 {
+		errs.Addf("head_poll_interval", "should not be negative")
+	}
+
+	// the finality signature msg is checked by the signing context of the chain and contract
+	if c.Babylon.FinalityGadgetCfg.BBNChainID == "" {
+		errs.Addf("babylon.finality_gadget.bbnchainid", "required for the finality vote signing context")
+	}
+
+	if c.Babylon.FinalityGadgetCfg.FGContractAddress == "" {
+		errs.Addf("babylon.finality_gadget.fgcontractaddress", "required for the finality vote signing context")
+	}
+
+	// the attestation queries the finalized state from babylon
+	if c.Attestation.IsEnabled() {
+		errs.Merge("babylon.finality_gadget", c.Babylon.FinalityGadgetCfg.ValidateForQuery())
+	}
+
+	return errs.Err()
+}
+
+func (c *GrpcConfig) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.ListenAddress == "" {
+		errs.Addf("listen_address", "required")
+	} else {
+		errs.Add("listen_address", utils.CheckHostPort(c.ListenAddress))
+	}
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		errs.Addf("cert_file", "the cert_file and key_file should be set together")
+	}
+
+	if c.ClientCAFile != "" && c.CertFile == "" {
+		errs.Addf("client_ca_file", "the mutual-TLS needs the server cert_file and key_file")
+	}
+
+	// the signing service should never be open to any client
+	if c.ClientCAFile == "" && !c.HasToken() {
+		errs.Addf("client_ca_file", "the clients should be authenticated by the mutual-TLS or the token")
+	}
+
+	if c.Token != "" && c.TokenFile != "" {
+		errs.Addf("token", "the token and token_file should not be set together")
+	}
+
+	if c.HasToken() && c.CertFile == "" {
+		errs.Addf("token", "the token should be sent by tls, set the cert_file and key_file")
+	}
+
+	return errs.Err()
+}
+
+func (c *AttestationConfig) Validate() error {
+	var errs utils.ConfigErrors
+
+	if c.IsEnabled() && c.RpcListenAddress != "" {
+		errs.Add("rpc_listen_address", utils.CheckHostPort(c.RpcListenAddress))
+	}
+
+	return errs.Err()
 }
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli"
+
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
+	"github.com/alt-research/blitz/finality-gadget/signer/configs"
+)
+
+var configCommand = utils.NewConfigCommand(defaultConfigPath, func(cliCtx *cli.Context) (any, error) {
+	return loadConfig(cliCtx)
+})
+
+// loadConfig reads the config with env and defaults, and validates it.
+func loadConfig(cliCtx *cli.Context) (*configs.SignerConfig, error) {
+	var config configs.SignerConfig
+	if err := utils.ReadConfig(cliCtx, defaultConfigPath, &config); err != nil {
+		return nil, fmt.Errorf("read config failed: %w", err)
+	}
+	config.WithEnv()
+
+	// the uniform env names override the legacy ones
+	if err := utils.ApplyEnv(configs.EnvPrefix, &config); err != nil {
+		return nil, fmt.Errorf("invalid env: %w", err)
+	}
+	config.WithDefault()
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return &config, nil
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/urfave/cli"
 
 	"github.com/alt-research/blitz/finality-gadget/core/logging"
-	"github.com/alt-research/blitz/finality-gadget/core/utils"
 	"github.com/alt-research/blitz/finality-gadget/signer"
 	"github.com/alt-research/blitz/finality-gadget/signer/configs"
 )
@@ -28,6 +27,9 @@ func main() {
 	app.Description = "Service that sign the finality-gadget commit by FPs to babylon's contract"
 
 	app.Action = signerMain
+	app.Commands = []cli.Command{
+		configCommand,
+	}
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Fatalln("Application failed.", "Message:", err)
@@ -35,21 +37,20 @@ func main() {
 }
 
 func signerMain(cliCtx *cli.Context) error {
-	var config configs.SignerConfig
-	if err := utils.ReadConfig(cliCtx, defaultConfigPath, &config); err != nil {
-		log.Fatalf("read config failed by %v", err)
+	config, err := loadConfig(cliCtx)
+	if err != nil {
 		return err
 	}
-	config.WithEnv()
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	logger, err := logging.NewZapLogger(logging.NewLogLevel(config.Common.Production))
+	logger, err := logging.New(&config.Logging, config.Common.Production,
+		logging.ServiceFields(cliCtx.App.Name, config.Layer2.ChainId)...)
 	if err != nil {
-		log.Fatalf("new logger failed by %v", err)
-		return err
+		return errors.Wrap(err, "create logger failed")
 	}
+	defer logger.Sync()
 
 	logger.Info(
 		"Finality gadget signer Start",
@@ -61,7 +62,7 @@ func signerMain(cliCtx *cli.Context) error {
 
 	logger.Debug("configs", "cfg", config)
 
-	signerService, err := signer.NewFinalityGadgetSignerService(ctx, &config, logger)
+	signerService, err := signer.NewFinalityGadgetSignerService(ctx, config, logger)
 	if err != nil {
 		log.Fatalln("Finality gadget signer new failed", "err", err.Error())
 		return err
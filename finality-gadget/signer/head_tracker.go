@@ -0,0 +1,62 @@
+package signer
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/alt-research/blitz/finality-gadget/client/l2eth"
+	"github.com/alt-research/blitz/finality-gadget/core/logging"
+)
+
+// headTracker tracks the latest l2 head of the signer 's own l2 node.
+type headTracker struct {
+	logger   logging.Logger
+	client   *l2eth.L2EthClient
+	interval time.Duration
+
+	head atomic.Uint64
+}
+
+func newHeadTracker(logger logging.Logger, client *l2eth.L2EthClient, interval time.Duration) *headTracker {
+	return &headTracker{
+		logger:   logger,
+		client:   client,
+		interval: interval,
+	}
+}
+
+// Head returns the latest l2 height, 0 if not got yet.
+func (t *headTracker) Head() uint64 {
+	return t.head.Load()
+}
+
+func (t *headTracker) poll(ctx context.Context) error {
+	height, err := t.client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	if old := t.head.Swap(height); old > height {
+		t.logger.Warn("the l2 head moved backward", "old", old, "new", height)
+	}
+
+	return nil
+}
+
+func (t *headTracker) run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.poll(ctx); err != nil {
+			t.logger.Error("failed to poll the l2 head", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/babylonlabs-io/babylon/v3/app/signingcontext"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	commonConfig "github.com/alt-research/blitz/finality-gadget/core/configs"
+)
+
+const (
+	heightBytesLen    = 8
+	blockHashBytesLen = 32
+)
+
+var (
+	// errNotCanonical is returned when the block to sign is not canonical on the signer 's l2 node.
+	errNotCanonical = errors.New("the block is not canonical")
+	// errBlockNotFound is returned when the block to sign is not found, the signer 's l2 node may lag.
+	errBlockNotFound = errors.New("the block not found")
+	// errL2Unavailable is returned when failed to query the signer 's l2 node.
+	errL2Unavailable = errors.New("the l2 node unavailable")
+	// errInvalidMsg is returned when the msg is not a finality signature msg.
+	errInvalidMsg = errors.New("invalid finality signature msg")
+	// errInvalidContext is returned when the msg is not signed in the finality vote context of the contract,
+	// the signature may be used for another chain or contract.
+	errInvalidContext = errors.New("the msg is not in the finality vote signing context")
+)
+
+// finalityVoteContext is the signing context of the finality signature for the finality contract.
+func finalityVoteContext(cfg *commonConfig.Config) []byte {
+	return []byte(signingcontext.FpFinVoteContextV0(cfg.BBNChainID, cfg.FGContractAddress))
+}
+
+// parseFinalitySigMsg parses the msg of the finality signature,
+// which should be exactly `signing context | height (big endian) | block hash`.
+func parseFinalitySigMsg(msg []byte, signingContext []byte, height uint64) (common.Hash, error) {
+	if len(msg) != len(signingContext)+heightBytesLen+blockHashBytesLen {
+		return common.Hash{}, errors.Wrapf(errInvalidContext, "the msg length %d is not %d", len(msg), len(signingContext)+heightBytesLen+blockHashBytesLen)
+	}
+
+	if !bytes.Equal(msg[:len(signingContext)], signingContext) {
+		return common.Hash{}, errors.Wrapf(errInvalidContext, "the msg prefix %x is not %s", msg[:len(signingContext)], signingContext)
+	}
+
+	hashStart := len(msg) - blockHashBytesLen
+	msgHeight := binary.BigEndian.Uint64(msg[hashStart-heightBytesLen : hashStart])
+	if msgHeight != height {
+		return common.Hash{}, errors.Wrapf(errInvalidMsg, "the height in msg %d is not the request height %d", msgHeight, height)
+	}
+
+	return common.BytesToHash(msg[hashStart:]), nil
+}
+
+// checkCanonical checks the block (height, hash) is canonical on the signer 's own l2 node.
+func (s *FinalityGadgetSignerService) checkCanonical(ctx context.Context, height uint64, hash common.Hash) error {
+	header, err := s.l2Client.HeaderByNumber(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return errors.Wrapf(errBlockNotFound, "the block %d not found, the head is %d", height, s.tracker.Head())
+		}
+
+		return errors.Wrapf(errL2Unavailable, "failed to get the l2 block %d: %v", height, err)
+	}
+
+	if header.Hash() != hash {
+		return errors.Wrapf(errNotCanonical, "the block %d hash is %s, requested %s", height, header.Hash(), hash)
+	}
+
+	return nil
+}
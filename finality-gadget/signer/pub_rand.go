@@ -0,0 +1,147 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+
+	"github.com/babylonlabs-io/babylon/v3/app/signingcontext"
+	"github.com/cometbft/cometbft/crypto/merkle"
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	"github.com/pkg/errors"
+
+	commonConfig "github.com/alt-research/blitz/finality-gadget/core/configs"
+)
+
+const (
+	// maxPubRandLists is the number of the recent randomness lists kept for each finality provider,
+	// the operator signs the commit right after it created the list.
+	maxPubRandLists = 8
+	// maxPubRandSeqLen limits the randomness created one by one which is not committed yet.
+	maxPubRandSeqLen = 1 << 16
+)
+
+// errUnknownCommit is returned when the msg of the schnorr signature is not the commit of the randomness created by the signer.
+var errUnknownCommit = errors.New("the msg is not a public randomness commit of the randomness created by the signer")
+
+// pubRandList is a list of the public randomness from the start height by the interval.
+type pubRandList struct {
+	startHeight uint64
+	interval    uint64
+	pubRands    [][]byte
+}
+
+func (l *pubRandList) nextHeight() uint64 {
+	return l.startHeight + uint64(len(l.pubRands))*l.interval
+}
+
+// continues returns true if the single randomness at the height follows the list.
+func (l *pubRandList) continues(height uint64) bool {
+	if len(l.pubRands) == 1 {
+		return height > l.startHeight
+	}
+
+	return height == l.nextHeight()
+}
+
+// pubRandCommits keeps the public randomness recently created by the signer, the `SignSchnorrSig` is only allowed
+// for the commit of them, so the key can not be used to sign any other msg by the signer.
+type pubRandCommits struct {
+	contexts []string
+
+	mu    sync.Mutex
+	lists map[string][]*pubRandList
+	// seqs are the randomness created one by one, `CreateRandomnessPairListWithInterval` of the operator
+	// creates the randomness at the heights by the interval so.
+	seqs map[string]*pubRandList
+}
+
+func newPubRandCommits(cfg *commonConfig.Config) *pubRandCommits {
+	// the commit without the signing context is allowed for the contracts not using the context
+	contexts := []string{""}
+	if cfg.BBNChainID != "" && cfg.FGContractAddress != "" {
+		contexts = append(contexts, signingcontext.FpRandCommitContextV0(cfg.BBNChainID, cfg.FGContractAddress))
+	}
+
+	return &pubRandCommits{
+		contexts: contexts,
+		lists:    make(map[string][]*pubRandList),
+		seqs:     make(map[string]*pubRandList),
+	}
+}
+
+// add records the randomness list created for the finality provider.
+func (c *pubRandCommits) add(uid []byte, startHeight uint64, pubRands [][]byte) {
+	key := hex.EncodeToString(uid)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(pubRands) != 1 {
+		lists := append(c.lists[key], &pubRandList{startHeight: startHeight, interval: 1, pubRands: pubRands})
+		if len(lists) > maxPubRandLists {
+			lists = lists[len(lists)-maxPubRandLists:]
+		}
+		c.lists[key] = lists
+		return
+	}
+
+	seq, ok := c.seqs[key]
+	if !ok || !seq.continues(startHeight) || len(seq.pubRands) >= maxPubRandSeqLen {
+		c.seqs[key] = &pubRandList{startHeight: startHeight, interval: 1, pubRands: pubRands}
+		return
+	}
+
+	if len(seq.pubRands) == 1 {
+		seq.interval = startHeight - seq.startHeight
+	}
+	seq.pubRands = append(seq.pubRands, pubRands[0])
+}
+
+// consume checks the msg is the commit hash of a randomness list created, and removes the list committed.
+func (c *pubRandCommits) consume(uid []byte, msg []byte) error {
+	key := hex.EncodeToString(uid)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lists := c.lists[key]
+	for i, l := range lists {
+		if c.matches(l, msg) {
+			c.lists[key] = append(lists[:i:i], lists[i+1:]...)
+			return nil
+		}
+	}
+
+	if seq, ok := c.seqs[key]; ok {
+		last := &pubRandList{startHeight: seq.nextHeight() - seq.interval, interval: 1, pubRands: seq.pubRands[len(seq.pubRands)-1:]}
+		if c.matches(seq, msg) || c.matches(last, msg) {
+			delete(c.seqs, key)
+			return nil
+		}
+	}
+
+	return errUnknownCommit
+}
+
+func (c *pubRandCommits) matches(l *pubRandList, msg []byte) bool {
+	for _, signingContext := range c.contexts {
+		if bytes.Equal(msg, pubRandCommitHash(signingContext, l.startHeight, l.pubRands)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pubRandCommitHash is the hash signed for the public randomness commit,
+// which is `sha256(signing context | start height | num | merkle root of the randomness)`.
+func pubRandCommitHash(signingContext string, startHeight uint64, pubRands [][]byte) []byte {
+	msg := []byte(signingContext)
+	msg = binary.BigEndian.AppendUint64(msg, startHeight)
+	msg = binary.BigEndian.AppendUint64(msg, uint64(len(pubRands)))
+	msg = append(msg, merkle.HashFromByteSlices(pubRands)...)
+
+	return tmhash.Sum(msg)
+}
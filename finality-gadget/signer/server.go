@@ -0,0 +1,208 @@
+package signer
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/babylonlabs-io/finality-provider/eotsmanager/proto"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/alt-research/blitz/finality-gadget/client/eotsmanager"
+	"github.com/alt-research/blitz/finality-gadget/client/eotsmanager/protection"
+	"github.com/alt-research/blitz/finality-gadget/signer/configs"
+)
+
+// signerServer is the signing gRPC service for the operators, it is compatible with the eotsd,
+// so the operator can use the signer as the `eotsManager.remote_address`.
+type signerServer struct {
+	proto.UnimplementedEOTSManagerServer
+
+	s       *FinalityGadgetSignerService
+	commits *pubRandCommits
+	// voteContext is the prefix of the finality signature msg
+	voteContext []byte
+}
+
+func newGrpcServer(cfg *configs.GrpcConfig, s *FinalityGadgetSignerService) (*grpc.Server, error) {
+	opts := make([]grpc.ServerOption, 0, 2)
+
+	if cfg.CertFile != "" {
+		tlsCfg, err := loadServerTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	}
+
+	if cfg.HasToken() {
+		opts = append(opts, grpc.UnaryInterceptor(tokenInterceptor(cfg)))
+	}
+
+	server := grpc.NewServer(opts...)
+	proto.RegisterEOTSManagerServer(server, &signerServer{
+		s:           s,
+		commits:     newPubRandCommits(&s.cfg.Babylon.FinalityGadgetCfg),
+		voteContext: finalityVoteContext(&s.cfg.Babylon.FinalityGadgetCfg),
+	})
+
+	return server, nil
+}
+
+func loadServerTLSConfig(cfg *configs.GrpcConfig) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the server cert")
+	}
+
+	res := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.ClientCAFile != "" {
+		ca, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the client ca file %s", cfg.ClientCAFile)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.Errorf("no certificate found in the client ca file %s", cfg.ClientCAFile)
+		}
+
+		res.ClientCAs = pool
+		res.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return res, nil
+}
+
+// tokenInterceptor refuses the requests without the bearer token in the `authorization` metadata.
+func tokenInterceptor(cfg *configs.GrpcConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		token := cfg.Token
+		if cfg.TokenFile != "" {
+			data, err := os.ReadFile(cfg.TokenFile)
+			if err != nil {
+				return nil, status.Errorf(codes.Unavailable, "failed to read the token file: %v", err)
+			}
+			token = strings.TrimSpace(string(data))
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, v := range md.Get("authorization") {
+			got, ok := strings.CutPrefix(v, "Bearer ")
+			if ok && token != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+				return handler(ctx, req)
+			}
+		}
+
+		return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+}
+
+func (s *FinalityGadgetSignerService) serveGrpc(server *grpc.Server, listener net.Listener) {
+	s.logger.Info("Starting signer gRPC service", "address", listener.Addr().String())
+
+	if err := server.Serve(listener); err != nil {
+		s.logger.Error("signer gRPC service stopped", "err", err)
+	}
+}
+
+func (r *signerServer) Ping(context.Context, *proto.PingRequest) (*proto.PingResponse, error) {
+	return &proto.PingResponse{}, nil
+}
+
+// CreateRandomnessPairList is deterministic by the height, so no policy for it,
+// the randomness is recorded to check the commit signed by SignSchnorrSig.
+func (r *signerServer) CreateRandomnessPairList(
+	_ context.Context, req *proto.CreateRandomnessPairListRequest) (*proto.CreateRandomnessPairListResponse, error) {
+	pubRandList, err := r.s.em.CreateRandomnessPairList(req.Uid, req.ChainId, req.StartHeight, req.Num)
+	if err != nil {
+		return nil, toStatusErr(err)
+	}
+
+	pubRandBytesList := make([][]byte, 0, len(pubRandList))
+	for _, p := range pubRandList {
+		pubRandBytesList = append(pubRandBytesList, p.Bytes()[:])
+	}
+	r.commits.add(req.Uid, req.StartHeight, pubRandBytesList)
+
+	return &proto.CreateRandomnessPairListResponse{
+		PubRandList: pubRandBytesList,
+	}, nil
+}
+
+// SignEOTS signs the finality signature only if the block is canonical on the signer 's own l2 node.
+func (r *signerServer) SignEOTS(ctx context.Context, req *proto.SignEOTSRequest) (*proto.SignEOTSResponse, error) {
+	logger := r.s.logger.With("uid", hex.EncodeToString(req.Uid), "height", req.Height)
+
+	hash, err := parseFinalitySigMsg(req.Msg, r.voteContext, req.Height)
+	if err != nil {
+		logger.Warn("refuse to sign the invalid msg", "err", err)
+		return nil, toStatusErr(err)
+	}
+
+	if err := r.s.checkCanonical(ctx, req.Height, hash); err != nil {
+		logger.Warn("refuse to sign the block", "hash", hash.Hex(), "err", err)
+		return nil, toStatusErr(err)
+	}
+
+	sig, err := r.s.em.SignEOTS(req.Uid, req.ChainId, req.Msg, req.Height)
+	if err != nil {
+		logger.Error("sign EOTS failed", "hash", hash.Hex(), "err", err)
+		return nil, toStatusErr(err)
+	}
+
+	logger.Debug("signed EOTS", "hash", hash.Hex())
+	sigBytes := sig.Bytes()
+
+	return &proto.SignEOTSResponse{Sig: sigBytes[:]}, nil
+}
+
+func (r *signerServer) UnsafeSignEOTS(context.Context, *proto.SignEOTSRequest) (*proto.SignEOTSResponse, error) {
+	return nil, status.Error(codes.PermissionDenied, "the unsafe signing is not allowed by the signer")
+}
+
+// SignSchnorrSig only signs the public randomness commit of the randomness created by the signer,
+// the pop should be signed by the eotsd directly when registering the finality provider.
+func (r *signerServer) SignSchnorrSig(_ context.Context, req *proto.SignSchnorrSigRequest) (*proto.SignSchnorrSigResponse, error) {
+	if err := r.commits.consume(req.Uid, req.Msg); err != nil {
+		r.s.logger.Warn("refuse to sign the schnorr signature", "uid", hex.EncodeToString(req.Uid), "err", err)
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	sig, err := r.s.em.SignSchnorrSig(req.Uid, req.Msg)
+	if err != nil {
+		return nil, toStatusErr(err)
+	}
+
+	return &proto.SignSchnorrSigResponse{Sig: sig.Serialize()}, nil
+}
+
+// toStatusErr converts the error to the gRPC status, the transient errors will be retried by the operator.
+func toStatusErr(err error) error {
+	switch {
+	case errors.Is(err, errInvalidContext):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, errInvalidMsg):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errNotCanonical), errors.Is(err, protection.ErrDoubleSign):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, errBlockNotFound), errors.Is(err, errL2Unavailable), errors.Is(err, eotsmanager.ErrUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -2,21 +2,28 @@ package signer
 
 import (
 	"context"
+	"net"
 	"sync"
-	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/alt-research/blitz/finality-gadget/client/eotsmanager"
 	"github.com/alt-research/blitz/finality-gadget/client/l2eth"
 	"github.com/alt-research/blitz/finality-gadget/core/logging"
 	"github.com/alt-research/blitz/finality-gadget/signer/configs"
 )
 
+// FinalityGadgetSignerService is the signing front-end for the operators, it holds the eots keys
+// by the eotsmanager, and only signs the finality signature of the canonical l2 blocks.
 type FinalityGadgetSignerService struct {
 	logger logging.Logger
 	cfg    *configs.SignerConfig
 
 	l2Client *l2eth.L2EthClient
+	em       *eotsmanager.EOTSManagerClient
+	tracker  *headTracker
+	// attester is nil if the attestation is disabled
+	attester *Attester
 
 	wg sync.WaitGroup
 }
@@ -25,16 +32,35 @@ func NewFinalityGadgetSignerService(
 	ctx context.Context,
 	cfg *configs.SignerConfig,
 	logger logging.Logger) (*FinalityGadgetSignerService, error) {
+	cfg.WithDefault()
+
 	l2Client, err := l2eth.NewL2EthClient(ctx, &cfg.Layer2)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create l2 eth client")
 	}
 
+	em, err := eotsmanager.NewEOTSManagerClient(logger.Inner(), cfg.EOTSManagerConfig, cfg.HomePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create eotsmanager client")
+	}
+
+	var attester *Attester
+	if cfg.Attestation.IsEnabled() {
+		attester, err = newAttester(ctx, cfg, l2Client, logger.Inner())
+		if err != nil {
+			em.Close()
+			return nil, errors.Wrap(err, "failed to create attester")
+		}
+	}
+
 	return &FinalityGadgetSignerService{
 		logger: logger,
 		cfg:    cfg,
 
 		l2Client: l2Client,
+		em:       em,
+		tracker:  newHeadTracker(logger, l2Client, cfg.HeadPollInterval),
+		attester: attester,
 	}, nil
 }
 
@@ -42,22 +68,50 @@ func (s *FinalityGadgetSignerService) Start(ctx context.Context) error {
 	s.wg.Add(1)
 	defer func() {
 		s.logger.Info("Stop finality gadget signer service", "name", s.cfg.Common.Name)
+		if err := s.em.Close(); err != nil {
+			s.logger.Error("close eotsmanager client failed", "err", err)
+		}
 		s.wg.Done()
 	}()
 
 	s.logger.Info("Starting finality gadget signer service", "name", s.cfg.Common.Name)
 
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
+	server, err := newGrpcServer(&s.cfg.Grpc, s)
+	if err != nil {
+		return errors.Wrap(err, "failed to create signer gRPC service")
+	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			s.logger.Debug("on FinalityGadgetSignerService service ticker")
-		}
+	listener, err := net.Listen("tcp", s.cfg.Grpc.ListenAddress)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", s.cfg.Grpc.ListenAddress)
 	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.tracker.run(ctx)
+	}()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.serveGrpc(server, listener)
+	}()
+
+	if s.attester != nil {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			if err := s.attester.serveRpc(ctx, s.cfg.Attestation.RpcListenAddress); err != nil {
+				s.logger.Error("finality attestation rpc stopped", "err", err)
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	server.GracefulStop()
+
+	return nil
 }
 
 func (s *FinalityGadgetSignerService) Wait() {
@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"net/url"
+
+	"github.com/alt-research/blitz/finality-gadget/core/utils"
+)
+
+// The exporters of the spans.
+const (
+	// ExporterNone disables the tracing, it is the default.
+	ExporterNone = "none"
+	// ExporterOtlpGrpc exports the spans to the OTLP collector by grpc, like `localhost:4317`.
+	ExporterOtlpGrpc = "otlp-grpc"
+	// ExporterOtlpHttp exports the spans to the OTLP collector by http, like `localhost:4318`.
+	ExporterOtlpHttp = "otlp-http"
+	// ExporterStdout writes the spans to stdout as json, for offline debugging.
+	ExporterStdout = "stdout"
+	// ExporterFile writes the spans to the file as json lines, for offline debugging.
+	ExporterFile = "file"
+)
+
+type Config struct {
+	// The exporter: `none`, `otlp-grpc`, `otlp-http`, `stdout` or `file`, default is `none`.
+	Exporter string `yaml:"exporter,omitempty"`
+	// The collector address for the otlp exporters, like `localhost:4317`.
+	Endpoint string `yaml:"endpoint,omitempty"`
+	// Use plain text to connect the collector.
+	Insecure bool `yaml:"insecure,omitempty"`
+	// The headers sent to the collector, like the auth token.
+	Headers map[string]string `yaml:"headers,omitempty" secret:"true"`
+	// The file path for the `file` exporter, the spans are appended.
+	FilePath string `yaml:"file_path,omitempty"`
+	// The ratio of the traces sampled, from 0 to 1, default is 1.
+	SampleRatio float64 `yaml:"sample_ratio,omitempty"`
+	// The service name of the spans, default is the binary name.
+	ServiceName string `yaml:"service_name,omitempty"`
+}
+
+// Enabled returns true if an exporter is set.
+func (c *Config) Enabled() bool {
+	return c.Exporter != "" && c.Exporter != ExporterNone
+}
+
+func (c *Config) Validate() error {
+	var errs utils.ConfigErrors
+
+	switch c.Exporter {
+	case "", ExporterNone, ExporterStdout:
+	case ExporterOtlpGrpc, ExporterOtlpHttp:
+		if c.Endpoint == "" {
+			errs.Addf("endpoint", "required by the %s exporter", c.Exporter)
+		} else if isURL(c.Endpoint) {
+			if _, err := url.Parse(c.Endpoint); err != nil {
+				errs.Addf("endpoint", "invalid endpoint %s: %v", c.Endpoint, err)
+			}
+		} else {
+			errs.Add("endpoint", utils.CheckHostPort(c.Endpoint))
+		}
+	case ExporterFile:
+		if c.FilePath == "" {
+			errs.Addf("file_path", "required by the file exporter")
+		}
+	default:
+		errs.Addf("exporter", "unknown exporter %s", c.Exporter)
+	}
+
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		errs.Addf("sample_ratio", "should be in [0, 1], got %v", c.SampleRatio)
+	}
+
+	return errs.Err()
+}